@@ -56,6 +56,24 @@ pass swap with big value
 				Flags:  swapKeyFlags,
 				Description: `
 reswap failed swap
+`,
+			},
+			{
+				Name:   "retryswap",
+				Usage:  "retry swap reverted in simulation",
+				Action: retryswap,
+				Flags:  swapKeyFlags,
+				Description: `
+retry swap whose destination tx is reverted in simulation (status TxSimulateFailed)
+`,
+			},
+			{
+				Name:   "restoreswap",
+				Usage:  "restore archived swap",
+				Action: restoreswap,
+				Flags:  swapKeyFlags,
+				Description: `
+move archived swap and its swap result back from archive collections
 `,
 			},
 			{
@@ -65,6 +83,82 @@ reswap failed swap
 				Flags:  append(swapKeyFlags, utils.GasPriceFlag),
 				Description: `
 replace pending swap with same nonce and new gas price
+`,
+			},
+			{
+				Name:   "fillnoncegap",
+				Usage:  "fill nonce gap of mpc",
+				Action: fillnoncegap,
+				Flags:  []cli.Flag{utils.ChainIDFlag, utils.MPCAddressFlag, utils.NonceFlag},
+				Description: `
+fill nonce gap of mpc with a zero value self transfer signed by mpc.
+the nonce must be an unallocated gap reported by nonceaudit,
+nonce allocated to swap whose tx is not in pool should be replaced or reswapped.
+`,
+			},
+			{
+				Name:   "nonceaudit",
+				Usage:  "check nonce gaps and orphan nonces of mpc",
+				Action: nonceaudit,
+				Flags:  []cli.Flag{utils.ChainIDFlag, utils.MPCAddressFlag},
+				Description: `
+check nonce gaps and orphan nonces of mpc, if mpc address is not specified
+then check all mpc accounts of the chain.
+gaps with reason 'unallocated' are not allocated to any swap and can be filled
+by fillnoncegap, gaps with reason 'notinpool' are allocated to swaps whose tx
+is not in pool and should be replaced or reswapped.
+`,
+			},
+			{
+				Name:      "signgroup",
+				Usage:     "show or manage mpc sign groups",
+				Action:    signgroup,
+				Flags:     []cli.Flag{utils.FastMPCFlag, utils.HighValueMPCFlag},
+				ArgsUsage: "<list|enable|disable> [groupID]",
+				Description: `
+show health stats of mpc sign groups, or manually enable/disable a sign group.
+disabled sign groups will not be selected when signing.
+
+examples:
+
+list
+<enable|disable> <groupID>
+`,
+			},
+			{
+				Name:   "disagrees",
+				Usage:  "show disagree reasons of swap reported by oracles",
+				Action: disagrees,
+				Flags:  swapKeyFlags,
+				Description: `
+show disagree reasons of swap reported by oracles, and the count of each reason code
+`,
+			},
+			{
+				Name:   "dryrunreload",
+				Usage:  "show what reload router config would change",
+				Action: dryrunreload,
+				Description: `
+compute the diff of chain and token configs between the ones in use and
+the latest ones in config source (onchain contract or local file),
+without applying them
+`,
+			},
+			{
+				Name:      "configsnapshot",
+				Usage:     "list or apply config snapshots",
+				Action:    configsnapshot,
+				ArgsUsage: "<list|apply> [limit|key]",
+				Description: `
+list the latest config snapshots saved on reload (with diffs to their previous ones),
+or apply chain and token configs of a snapshot to rollback a bad reload.
+the applied configs will be overwritten by the next reload from config source,
+so fix the config source before the next reload.
+
+examples:
+
+list [limit]
+apply <key>
 `,
 			},
 		},
@@ -169,6 +263,48 @@ func reswap(ctx *cli.Context) error {
 	return err
 }
 
+func retryswap(ctx *cli.Context) error {
+	utils.SetLogger(ctx)
+	method := "retryswap"
+	err := admin.Prepare(ctx)
+	if err != nil {
+		return err
+	}
+	chainID, txid, logIndex, err := getKeys(ctx)
+	if err != nil {
+		return err
+	}
+
+	log.Printf("%v: %v %v %v", method, chainID, txid, logIndex)
+
+	params := []string{chainID, txid, logIndex}
+	result, err := admin.SwapAdmin(method, params)
+
+	log.Printf("result is '%v'", result)
+	return err
+}
+
+func restoreswap(ctx *cli.Context) error {
+	utils.SetLogger(ctx)
+	method := "restoreswap"
+	err := admin.Prepare(ctx)
+	if err != nil {
+		return err
+	}
+	chainID, txid, logIndex, err := getKeys(ctx)
+	if err != nil {
+		return err
+	}
+
+	log.Printf("%v: %v %v %v", method, chainID, txid, logIndex)
+
+	params := []string{chainID, txid, logIndex}
+	result, err := admin.SwapAdmin(method, params)
+
+	log.Printf("result is '%v'", result)
+	return err
+}
+
 func replaceswap(ctx *cli.Context) error {
 	utils.SetLogger(ctx)
 	method := "replaceswap"
@@ -193,3 +329,171 @@ func replaceswap(ctx *cli.Context) error {
 	log.Printf("result is '%v'", result)
 	return err
 }
+
+func fillnoncegap(ctx *cli.Context) error {
+	utils.SetLogger(ctx)
+	method := "fillnoncegap"
+	err := admin.Prepare(ctx)
+	if err != nil {
+		return err
+	}
+	chainID := ctx.String(utils.ChainIDFlag.Name)
+	if _, err = common.GetBigIntFromStr(chainID); err != nil || chainID == "" {
+		return fmt.Errorf("wrong chain id '%v'", chainID)
+	}
+	mpcAddress := ctx.String(utils.MPCAddressFlag.Name)
+	if !common.IsHexAddress(mpcAddress) {
+		return fmt.Errorf("wrong mpc address '%v'", mpcAddress)
+	}
+	nonce := fmt.Sprintf("%d", ctx.Uint64(utils.NonceFlag.Name))
+
+	log.Printf("%v: %v %v %v", method, chainID, mpcAddress, nonce)
+
+	params := []string{chainID, mpcAddress, nonce}
+	result, err := admin.SwapAdmin(method, params)
+
+	log.Printf("result is '%v'", result)
+	return err
+}
+
+func nonceaudit(ctx *cli.Context) error {
+	utils.SetLogger(ctx)
+	method := "nonceaudit"
+	err := admin.Prepare(ctx)
+	if err != nil {
+		return err
+	}
+	chainID := ctx.String(utils.ChainIDFlag.Name)
+	if _, err = common.GetBigIntFromStr(chainID); err != nil || chainID == "" {
+		return fmt.Errorf("wrong chain id '%v'", chainID)
+	}
+	params := []string{chainID}
+	if mpcAddress := ctx.String(utils.MPCAddressFlag.Name); mpcAddress != "" {
+		if !common.IsHexAddress(mpcAddress) {
+			return fmt.Errorf("wrong mpc address '%v'", mpcAddress)
+		}
+		params = append(params, mpcAddress)
+	}
+
+	log.Printf("%v: %v", method, params)
+
+	result, err := admin.SwapAdmin(method, params)
+
+	log.Printf("result is '%v'", result)
+	return err
+}
+
+func signgroup(ctx *cli.Context) error {
+	utils.SetLogger(ctx)
+	if ctx.NArg() == 0 {
+		return fmt.Errorf("signgroup: no action is specified")
+	}
+
+	method := "signgroup"
+	err := admin.Prepare(ctx)
+	if err != nil {
+		return err
+	}
+
+	mpcKind := "mpc"
+	if ctx.Bool(utils.FastMPCFlag.Name) {
+		mpcKind = "fastmpc"
+	} else if ctx.Bool(utils.HighValueMPCFlag.Name) {
+		mpcKind = "highvaluempc"
+	}
+	action := ctx.Args().Get(0)
+	params := []string{action}
+	switch action {
+	case "list":
+		if ctx.IsSet(utils.FastMPCFlag.Name) || ctx.IsSet(utils.HighValueMPCFlag.Name) {
+			params = append(params, mpcKind)
+		}
+	case "enable", "disable":
+		if ctx.NArg() != 2 {
+			return fmt.Errorf("signgroup: %v need sign group id", action)
+		}
+		params = append(params, mpcKind, ctx.Args().Get(1))
+	default:
+		return fmt.Errorf("signgroup: unknown action '%v'", action)
+	}
+
+	log.Printf("%v: %v", method, params)
+
+	result, err := admin.SwapAdmin(method, params)
+
+	log.Printf("result is '%v'", result)
+	return err
+}
+
+func disagrees(ctx *cli.Context) error {
+	utils.SetLogger(ctx)
+	method := "disagrees"
+	err := admin.Prepare(ctx)
+	if err != nil {
+		return err
+	}
+	chainID, txid, logIndex, err := getKeys(ctx)
+	if err != nil {
+		return err
+	}
+
+	log.Printf("%v: %v %v %v", method, chainID, txid, logIndex)
+
+	params := []string{chainID, txid, logIndex}
+	result, err := admin.SwapAdmin(method, params)
+
+	log.Printf("result is '%v'", result)
+	return err
+}
+
+func dryrunreload(ctx *cli.Context) error {
+	utils.SetLogger(ctx)
+	method := "dryrunreload"
+	err := admin.Prepare(ctx)
+	if err != nil {
+		return err
+	}
+
+	log.Printf("%v", method)
+
+	result, err := admin.SwapAdmin(method, []string{})
+
+	log.Printf("result is '%v'", result)
+	return err
+}
+
+func configsnapshot(ctx *cli.Context) error {
+	utils.SetLogger(ctx)
+	if ctx.NArg() == 0 {
+		return fmt.Errorf("configsnapshot: no action is specified")
+	}
+
+	method := "configsnapshot"
+	err := admin.Prepare(ctx)
+	if err != nil {
+		return err
+	}
+
+	action := ctx.Args().Get(0)
+	params := []string{action}
+	switch action {
+	case "list":
+		if ctx.NArg() > 1 {
+			params = append(params, ctx.Args().Get(1))
+		}
+	case "apply":
+		if ctx.NArg() != 2 {
+			return fmt.Errorf("configsnapshot: apply need snapshot key")
+		}
+		params = append(params, ctx.Args().Get(1))
+	default:
+		return fmt.Errorf("configsnapshot: unknown action '%v'", action)
+	}
+
+	log.Printf("%v: %v", method, params)
+
+	result, err := admin.SwapAdmin(method, params)
+
+	log.Printf("result is '%v'", result)
+	return err
+}
@@ -0,0 +1,179 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/anyswap/CrossChain-Router/v3/admin"
+	"github.com/anyswap/CrossChain-Router/v3/cmd/utils"
+	"github.com/anyswap/CrossChain-Router/v3/internal/swapapi"
+	"github.com/anyswap/CrossChain-Router/v3/log"
+	"github.com/urfave/cli/v2"
+)
+
+var (
+	exportCommand = &cli.Command{
+		Name:   "export",
+		Usage:  "export swap history for accounting",
+		Action: exportSwaps,
+		Flags: append(append([]cli.Flag{
+			exportFormatFlag,
+			exportStartFlag,
+			exportEndFlag,
+			exportTokenIDFlag,
+			exportFromChainFlag,
+			exportToChainFlag,
+			exportStatusFlag,
+			exportOutputFlag,
+			exportPageSizeFlag,
+		}, admin.CommonFlags...), utils.CommonLogFlags...),
+		Description: `
+export swap results registered in time range as CSV or JSON Lines through admin api,
+including computed fee (value minus swapvalue converted via token decimals),
+source and destination tx hashes and timestamps.
+
+example: export completed swaps of USDC in Jan 2023
+
+swaprouter export --swapserver <url> --keystore <file> --password <file> \
+	--start 2023-01-01 --end 2023-02-01 --tokenid USDC --output usdc-202301.csv
+`,
+	}
+
+	exportFormatFlag = &cli.StringFlag{
+		Name:  "format",
+		Usage: "export format, csv or jsonl",
+		Value: swapapi.ExportFormatCSV,
+	}
+
+	exportStartFlag = &cli.StringFlag{
+		Name:  "start",
+		Usage: "start time (inclusive), unix seconds or date (2006-01-02 in UTC)",
+	}
+
+	exportEndFlag = &cli.StringFlag{
+		Name:  "end",
+		Usage: "end time (exclusive), unix seconds or date (2006-01-02 in UTC)",
+	}
+
+	exportTokenIDFlag = &cli.StringFlag{
+		Name:  "tokenid",
+		Usage: "filter by token id",
+	}
+
+	exportFromChainFlag = &cli.StringFlag{
+		Name:  "fromchain",
+		Usage: "filter by source chain id",
+	}
+
+	exportToChainFlag = &cli.StringFlag{
+		Name:  "tochain",
+		Usage: "filter by destination chain id",
+	}
+
+	exportStatusFlag = &cli.StringFlag{
+		Name:  "status",
+		Usage: "comma separated swap result statuses, default is MatchTxStable (10)",
+	}
+
+	exportOutputFlag = &cli.StringFlag{
+		Name:  "output",
+		Usage: "output file, default is stdout",
+	}
+
+	exportPageSizeFlag = &cli.IntFlag{
+		Name:  "pagesize",
+		Usage: "count of swap results exported in each admin call (max 1000)",
+		Value: 500,
+	}
+)
+
+func parseExportTime(value string) (int64, error) {
+	if value == "" {
+		return 0, nil
+	}
+	if secs, err := strconv.ParseInt(value, 10, 64); err == nil {
+		return secs, nil
+	}
+	t, err := time.Parse("2006-01-02", value)
+	if err != nil {
+		return 0, fmt.Errorf("wrong time '%v'", value)
+	}
+	return t.Unix(), nil
+}
+
+func exportSwaps(ctx *cli.Context) (err error) {
+	utils.SetLogger(ctx)
+	method := "export"
+	if err = admin.Prepare(ctx); err != nil {
+		return err
+	}
+
+	args := &swapapi.ExportArgs{
+		Format:      ctx.String(exportFormatFlag.Name),
+		TokenID:     ctx.String(exportTokenIDFlag.Name),
+		FromChainID: ctx.String(exportFromChainFlag.Name),
+		ToChainID:   ctx.String(exportToChainFlag.Name),
+		Status:      ctx.String(exportStatusFlag.Name),
+		Limit:       ctx.Int(exportPageSizeFlag.Name),
+	}
+	if args.StartTime, err = parseExportTime(ctx.String(exportStartFlag.Name)); err != nil {
+		return err
+	}
+	if args.EndTime, err = parseExportTime(ctx.String(exportEndFlag.Name)); err != nil {
+		return err
+	}
+
+	var w io.Writer = os.Stdout
+	if output := ctx.String(exportOutputFlag.Name); output != "" {
+		file, errf := os.Create(output)
+		if errf != nil {
+			return errf
+		}
+		defer file.Close()
+		w = file
+	}
+
+	total := 0
+	for {
+		res, errf := callExport(method, args)
+		if errf != nil {
+			return errf
+		}
+		if _, err = io.WriteString(w, res.Data); err != nil {
+			return err
+		}
+		total += res.Count
+		log.Info("export swap results", "count", res.Count, "total", total)
+		if res.Cursor == "" {
+			break
+		}
+		args.Cursor = res.Cursor
+	}
+	log.Info("export swap results finished", "total", total)
+	return nil
+}
+
+func callExport(method string, args *swapapi.ExportArgs) (*swapapi.ExportResult, error) {
+	data, err := json.Marshal(args)
+	if err != nil {
+		return nil, err
+	}
+	result, err := admin.SwapAdmin(method, []string{string(data)})
+	if err != nil {
+		return nil, err
+	}
+	resStr, ok := result.(string)
+	if !ok {
+		return nil, errors.New("wrong export result type")
+	}
+	var res swapapi.ExportResult
+	if err = json.Unmarshal([]byte(resStr), &res); err != nil {
+		return nil, err
+	}
+	return &res, nil
+}
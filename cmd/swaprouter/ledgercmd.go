@@ -0,0 +1,120 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/anyswap/CrossChain-Router/v3/cmd/utils"
+	"github.com/anyswap/CrossChain-Router/v3/rpc/client"
+	"github.com/urfave/cli/v2"
+)
+
+var (
+	ledgerCommand = &cli.Command{
+		Name:  "ledger",
+		Usage: "query oracle accept ledger",
+		Flags: []cli.Flag{ledgerURLFlag},
+		Description: `
+query accept ledger of running oracle through its local api
+(config 'Oracle.LocalAPIPort' to enable it)
+`,
+		Subcommands: []*cli.Command{
+			{
+				Name:   "records",
+				Usage:  "get accept ledger records in time range",
+				Action: getLedgerRecords,
+				Flags:  []cli.Flag{ledgerStartFlag, ledgerEndFlag, ledgerLimitFlag},
+			},
+			{
+				Name:      "record",
+				Usage:     "get accept ledger record of keyID",
+				Action:    getLedgerRecord,
+				ArgsUsage: "<keyID>",
+			},
+			{
+				Name:   "swap",
+				Usage:  "get accept ledger records of swap",
+				Action: getLedgerSwapRecords,
+				Flags:  swapKeyFlags,
+			},
+			{
+				Name:   "flags",
+				Usage:  "get swap txs of server not agreed by this oracle",
+				Action: getLedgerFlags,
+				Flags:  []cli.Flag{ledgerLimitFlag},
+			},
+		},
+	}
+
+	ledgerURLFlag = &cli.StringFlag{
+		Name:  "url",
+		Usage: "oracle local api url",
+		Value: "http://127.0.0.1:11557/rpc",
+	}
+
+	ledgerStartFlag = &cli.Int64Flag{
+		Name:  "start",
+		Usage: "start timestamp (seconds)",
+	}
+
+	ledgerEndFlag = &cli.Int64Flag{
+		Name:  "end",
+		Usage: "end timestamp (seconds), default is now",
+	}
+
+	ledgerLimitFlag = &cli.IntFlag{
+		Name:  "limit",
+		Usage: "max count of results",
+		Value: 100,
+	}
+)
+
+func callLedgerAPI(ctx *cli.Context, method string, args interface{}) error {
+	var result interface{}
+	url := ctx.String(ledgerURLFlag.Name)
+	err := client.RPCPostWithTimeout(60, &result, url, method, args)
+	if err != nil {
+		return err
+	}
+	jsdata, err := json.MarshalIndent(result, "", "  ")
+	if err != nil {
+		return err
+	}
+	fmt.Println(string(jsdata))
+	return nil
+}
+
+func getLedgerRecords(ctx *cli.Context) error {
+	args := map[string]interface{}{
+		"start": ctx.Int64(ledgerStartFlag.Name),
+		"end":   ctx.Int64(ledgerEndFlag.Name),
+		"limit": ctx.Int(ledgerLimitFlag.Name),
+	}
+	return callLedgerAPI(ctx, "ledger.GetRecords", args)
+}
+
+func getLedgerRecord(ctx *cli.Context) error {
+	if ctx.NArg() != 1 {
+		return fmt.Errorf("record: need keyID")
+	}
+	args := map[string]interface{}{
+		"keyid": ctx.Args().Get(0),
+	}
+	return callLedgerAPI(ctx, "ledger.GetRecord", args)
+}
+
+func getLedgerSwapRecords(ctx *cli.Context) error {
+	args := map[string]interface{}{
+		"chainid":  ctx.String(utils.ChainIDFlag.Name),
+		"txid":     ctx.String(utils.TxIDFlag.Name),
+		"logindex": fmt.Sprintf("%d", ctx.Int(utils.LogIndexFlag.Name)),
+	}
+	return callLedgerAPI(ctx, "ledger.GetSwapRecords", args)
+}
+
+func getLedgerFlags(ctx *cli.Context) error {
+	args := map[string]interface{}{
+		"limit": ctx.Int(ledgerLimitFlag.Name),
+	}
+	return callLedgerAPI(ctx, "ledger.GetFlags", args)
+}
@@ -8,9 +8,9 @@ import (
 
 	"github.com/anyswap/CrossChain-Router/v3/cmd/utils"
 	"github.com/anyswap/CrossChain-Router/v3/log"
-	"github.com/anyswap/CrossChain-Router/v3/mongodb"
 	"github.com/anyswap/CrossChain-Router/v3/params"
 	rpcserver "github.com/anyswap/CrossChain-Router/v3/rpc/server"
+	"github.com/anyswap/CrossChain-Router/v3/storage"
 	"github.com/anyswap/CrossChain-Router/v3/tokens"
 	"github.com/anyswap/CrossChain-Router/v3/worker"
 	"github.com/urfave/cli/v2"
@@ -32,7 +32,11 @@ func initApp() {
 	app.Copyright = "Copyright 2017-2020 The CrossChain-Router Authors"
 	app.Commands = []*cli.Command{
 		adminCommand,
+		ledgerCommand,
 		configCommand,
+		migrateCommand,
+		exportCommand,
+		recoverCommand,
 		toolsCommand,
 		utils.LicenseCommand,
 		utils.VersionCommand,
@@ -72,22 +76,33 @@ func swaprouter(ctx *cli.Context) error {
 	tokens.InitRouterSwapType(config.SwapType)
 
 	if isServer {
-		appName := params.GetIdentifier()
-		dbConfig := config.Server.MongoDB
-		mongodb.MongoServerInit(
-			appName,
-			dbConfig.DBURLs,
-			dbConfig.DBName,
-			dbConfig.UserName,
-			dbConfig.Password,
-		)
+		initServerStorage(config)
 		worker.StartRouterSwapWork(true)
 		time.Sleep(100 * time.Millisecond)
 		rpcserver.StartAPIServer()
 	} else {
 		worker.StartRouterSwapWork(false)
+		time.Sleep(100 * time.Millisecond)
+		rpcserver.StartOracleAPIServer()
 	}
 
 	utils.TopWaitGroup.Wait()
 	return nil
 }
+
+func initServerStorage(config *params.RouterConfig) {
+	if config.Server.EmbeddedDBPath != "" {
+		storage.InitEmbeddedStorage(config.Server.EmbeddedDBPath)
+		return
+	}
+	appName := params.GetIdentifier()
+	dbConfig := config.Server.MongoDB
+	storage.InitMongoStorage(
+		appName,
+		dbConfig.DBURLs,
+		dbConfig.DBName,
+		dbConfig.UserName,
+		dbConfig.Password,
+	)
+	migrateOnStartup(dbConfig)
+}
@@ -0,0 +1,109 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/anyswap/CrossChain-Router/v3/cmd/utils"
+	"github.com/anyswap/CrossChain-Router/v3/log"
+	"github.com/anyswap/CrossChain-Router/v3/mongodb"
+	"github.com/anyswap/CrossChain-Router/v3/params"
+	"github.com/urfave/cli/v2"
+)
+
+var (
+	migrateCommand = &cli.Command{
+		Name:   "migrate",
+		Usage:  "run mongodb schema migrations",
+		Action: runMigrations,
+		Flags:  append([]cli.Flag{utils.ConfigFileFlag, dryRunFlag}, utils.CommonLogFlags...),
+		Description: `
+apply pending mongodb schema migrations (data backfills, index creation/removal)
+of the server node, and record them in the 'Migrations' collection.
+`,
+		Subcommands: []*cli.Command{
+			{
+				Name:   "status",
+				Usage:  "list migrations and whether they are applied",
+				Action: getMigrationStatus,
+				Flags:  append([]cli.Flag{utils.ConfigFileFlag}, utils.CommonLogFlags...),
+			},
+		},
+	}
+
+	dryRunFlag = &cli.BoolFlag{
+		Name:  "dryrun",
+		Usage: "only print the migration steps without modifying database",
+	}
+)
+
+func initMigrateDatabase(ctx *cli.Context) error {
+	utils.SetLogger(ctx)
+	config := params.LoadRouterConfig(utils.GetConfigFilePath(ctx), true, false)
+	dbConfig := config.Server.MongoDB
+	if dbConfig == nil {
+		return errors.New("migrations are only for mongodb, but 'Server.MongoDB' is not configed")
+	}
+	if err := dbConfig.CheckConfig(); err != nil {
+		return err
+	}
+	mongodb.MongoServerInit(
+		params.GetIdentifier(),
+		dbConfig.DBURLs,
+		dbConfig.DBName,
+		dbConfig.UserName,
+		dbConfig.Password,
+	)
+	return nil
+}
+
+func runMigrations(ctx *cli.Context) error {
+	if err := initMigrateDatabase(ctx); err != nil {
+		return err
+	}
+	dryRun := ctx.Bool(dryRunFlag.Name)
+	applied, err := mongodb.RunMigrations(dryRun)
+	for _, mig := range applied {
+		fmt.Printf("migration %v (%v) applied, dryrun is %v\n", mig.Version, mig.Description, dryRun)
+	}
+	if err != nil {
+		return err
+	}
+	if len(applied) == 0 {
+		fmt.Println("no pending migrations")
+	}
+	return nil
+}
+
+func getMigrationStatus(ctx *cli.Context) error {
+	if err := initMigrateDatabase(ctx); err != nil {
+		return err
+	}
+	status, err := mongodb.GetMigrationStatus()
+	if err != nil {
+		return err
+	}
+	jsdata, err := json.MarshalIndent(status, "", "  ")
+	if err != nil {
+		return err
+	}
+	fmt.Println(string(jsdata))
+	return nil
+}
+
+// run pending migrations on server startup unless skipped in config
+func migrateOnStartup(dbConfig *params.MongoDBConfig) {
+	if dbConfig.SkipMigration {
+		pending, err := mongodb.GetPendingMigrations()
+		if err != nil {
+			log.Warn("get pending migrations failed", "err", err)
+		} else if len(pending) > 0 {
+			log.Warn("skip pending migrations, please run 'swaprouter migrate'", "count", len(pending))
+		}
+		return
+	}
+	if _, err := mongodb.RunMigrations(false); err != nil {
+		log.Fatal("run migrations failed", "err", err)
+	}
+}
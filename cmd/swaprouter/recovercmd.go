@@ -0,0 +1,139 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/anyswap/CrossChain-Router/v3/cmd/utils"
+	"github.com/anyswap/CrossChain-Router/v3/log"
+	"github.com/anyswap/CrossChain-Router/v3/params"
+	"github.com/anyswap/CrossChain-Router/v3/router/bridge"
+	"github.com/anyswap/CrossChain-Router/v3/tokens"
+	"github.com/anyswap/CrossChain-Router/v3/worker"
+	"github.com/urfave/cli/v2"
+)
+
+var (
+	recoverCommand = &cli.Command{
+		Name:   "recover",
+		Usage:  "recover swaps and swap results from chain logs",
+		Action: recoverSwaps,
+		Flags: append([]cli.Flag{
+			utils.DataDirFlag,
+			utils.ConfigFileFlag,
+			recoverRangeFlag,
+			recoverBatchSizeFlag,
+			recoverDryRunFlag,
+			recoverReportFlag,
+		}, utils.CommonLogFlags...),
+		Description: `
+rebuild swaps and swap results after the database is lost.
+scan router swapout logs on source chains and anySwapIn logs (which contain
+the source tx hash) on dest chains in the block ranges, and match them exactly
+by dest chain, bind address and token to rebuild swaps in processed status and
+swap results in stable status with swaptx. the init time is the source tx time.
+
+source swaps without matched anySwapIn are NOT registered, as their anySwapIn
+may be out of the scanned ranges. they are reported for review, with anySwapIn
+logs whose source swap is not found. the report is in json format.
+
+example: recover swaps between chain 1 and 56
+
+swaprouter recover --config config.toml --report report.json \
+	--range 1:15000000:15100000 --range 56:20000000:20400000
+`,
+	}
+
+	recoverRangeFlag = &cli.StringSliceFlag{
+		Name:  "range",
+		Usage: "block range to scan in format 'chainID:start:end' (inclusive), can be repeated",
+	}
+
+	recoverBatchSizeFlag = &cli.Uint64Flag{
+		Name:  "batch",
+		Usage: "count of blocks in each log query",
+		Value: 2000,
+	}
+
+	recoverDryRunFlag = &cli.BoolFlag{
+		Name:  "dryrun",
+		Usage: "only match and report without accessing database",
+	}
+
+	recoverReportFlag = &cli.StringFlag{
+		Name:  "report",
+		Usage: "report file, default is stdout",
+	}
+)
+
+func parseRecoverRange(value string) (*worker.RecoverRange, error) {
+	parts := strings.Split(value, ":")
+	if len(parts) != 3 {
+		return nil, fmt.Errorf("wrong range '%v'", value)
+	}
+	start, err := strconv.ParseUint(parts[1], 10, 64)
+	if err != nil {
+		return nil, fmt.Errorf("wrong range '%v': %w", value, err)
+	}
+	end, err := strconv.ParseUint(parts[2], 10, 64)
+	if err != nil {
+		return nil, fmt.Errorf("wrong range '%v': %w", value, err)
+	}
+	if end < start {
+		return nil, fmt.Errorf("wrong range '%v': end is less than start", value)
+	}
+	return &worker.RecoverRange{ChainID: parts[0], Start: start, End: end}, nil
+}
+
+func recoverSwaps(ctx *cli.Context) error {
+	utils.SetLogger(ctx)
+	args := &worker.RecoverArgs{
+		BatchSize: ctx.Uint64(recoverBatchSizeFlag.Name),
+		DryRun:    ctx.Bool(recoverDryRunFlag.Name),
+	}
+	for _, value := range ctx.StringSlice(recoverRangeFlag.Name) {
+		r, err := parseRecoverRange(value)
+		if err != nil {
+			return err
+		}
+		args.Ranges = append(args.Ranges, r)
+	}
+	if len(args.Ranges) == 0 {
+		return fmt.Errorf("must specify block ranges with '--%v'", recoverRangeFlag.Name)
+	}
+
+	params.SetDataDir(utils.GetDataDir(ctx), true)
+	config := params.LoadRouterConfig(utils.GetConfigFilePath(ctx), true, true)
+	tokens.InitRouterSwapType(config.SwapType)
+	if !args.DryRun {
+		initServerStorage(config)
+	}
+	bridge.InitRouterBridges(true)
+
+	report, err := worker.RecoverRouterSwaps(args)
+	if report != nil {
+		log.Info("recover swaps finished", "recovered", report.Recovered, "existing", report.Existing,
+			"unmatched", len(report.Unmatched), "orphanSwapins", len(report.OrphanSwapins), "dryrun", args.DryRun)
+		if errw := writeRecoverReport(ctx.String(recoverReportFlag.Name), report); errw != nil {
+			log.Warn("write recover report failed", "err", errw)
+		}
+	}
+	return err
+}
+
+func writeRecoverReport(output string, report *worker.RecoverReport) error {
+	data, err := json.MarshalIndent(report, "", "  ")
+	if err != nil {
+		return err
+	}
+	data = append(data, '\n')
+	if output == "" {
+		_, err = os.Stdout.Write(data)
+		return err
+	}
+	return ioutil.WriteFile(output, data, 0600)
+}
@@ -131,6 +131,22 @@ var (
 		Name:  "gasPrice",
 		Usage: "gas price",
 	}
+	// NonceFlag --nonce
+	NonceFlag = &cli.Uint64Flag{
+		Name:  "nonce",
+		Usage: "account nonce",
+	}
+	// FastMPCFlag --fastmpc
+	FastMPCFlag = &cli.BoolFlag{
+		Name:  "fastmpc",
+		Usage: "use fast mpc",
+	}
+
+	// HighValueMPCFlag --highvaluempc
+	HighValueMPCFlag = &cli.BoolFlag{
+		Name:  "highvaluempc",
+		Usage: "use high value mpc",
+	}
 
 	// CommonLogFlags common log flags
 	CommonLogFlags = []cli.Flag{
@@ -11,6 +11,7 @@ import (
 	"github.com/anyswap/CrossChain-Router/v3/mpc"
 	"github.com/anyswap/CrossChain-Router/v3/params"
 	"github.com/anyswap/CrossChain-Router/v3/router"
+	"github.com/anyswap/CrossChain-Router/v3/storage"
 	"github.com/anyswap/CrossChain-Router/v3/tokens"
 	"github.com/anyswap/CrossChain-Router/v3/worker"
 	rpcjson "github.com/gorilla/rpc/v2/json2"
@@ -19,6 +20,15 @@ import (
 var (
 	oraclesInfo sync.Map // string -> *OracleInfo // key is enode
 
+	// oracle is stale if no heartbeat in this duration
+	oracleStaleInterval = int64(600) // seconds
+
+	serverStat          *serverStatCache
+	serverStatLock      sync.Mutex
+	serverStatCacheTime = int64(60) // seconds
+
+	maxOracleDisagreesPerReport = 1000
+
 	errAlreadyRegistered = newRPCError(-32001, "already registered")
 )
 
@@ -49,41 +59,110 @@ func GetServerInfo() *ServerInfo {
 func GetOracleInfo() map[string]*OracleInfo {
 	result := make(map[string]*OracleInfo, 4)
 	oraclesInfo.Range(func(k, v interface{}) bool {
-		enode := k.(string)
-		startIndex := strings.Index(enode, "enode://")
-		endIndex := strings.Index(enode, "@")
-		if startIndex != -1 && endIndex != -1 {
-			info := v.(*OracleInfo)
-			enodeID := enode[startIndex+8 : endIndex]
-			result[strings.ToLower(enodeID)] = info
+		if enodeID := getEnodeID(k.(string)); enodeID != "" {
+			info := *v.(*OracleInfo)
+			info.IsStale = info.HeartbeatTimestamp+oracleStaleInterval < time.Now().Unix()
+			result[enodeID] = &info
 		}
 		return true
 	})
 	return result
 }
 
+// getEnodeID get lower case node ID in enode url
+func getEnodeID(enode string) string {
+	startIndex := strings.Index(enode, "enode://")
+	endIndex := strings.Index(enode, "@")
+	if startIndex == -1 || endIndex == -1 {
+		return ""
+	}
+	return strings.ToLower(enode[startIndex+8 : endIndex])
+}
+
+// serverStatCache cached server stat to compare with oracles
+type serverStatCache struct {
+	timestamp    int64
+	configHash   string
+	chainHeights map[string]uint64
+}
+
+func getServerStat() *serverStatCache {
+	serverStatLock.Lock()
+	defer serverStatLock.Unlock()
+
+	now := time.Now().Unix()
+	if serverStat != nil && serverStat.timestamp+serverStatCacheTime > now {
+		return serverStat
+	}
+	stat := &serverStatCache{
+		timestamp:    now,
+		configHash:   router.GetCurrentConfigSnapshot().Hash(),
+		chainHeights: make(map[string]uint64, len(router.AllChainIDs)),
+	}
+	for _, chainID := range router.AllChainIDs {
+		bridge := router.GetBridgeByChainID(chainID.String())
+		if bridge == nil {
+			continue
+		}
+		if height, err := bridge.GetLatestBlockNumber(); err == nil {
+			stat.chainHeights[chainID.String()] = height
+		}
+	}
+	serverStat = stat
+	return stat
+}
+
+// checkOracleInfo flag oracle with stale config or lagging chains
+func checkOracleInfo(info *OracleInfo) {
+	stat := getServerStat()
+	if info.ConfigHash != "" && info.ConfigHash != stat.configHash {
+		info.StaleConfig = true
+	}
+	for chainID, height := range info.ChainHeights {
+		serverHeight, exist := stat.chainHeights[chainID]
+		if !exist || serverHeight <= height {
+			continue
+		}
+		bridge := router.GetBridgeByChainID(chainID)
+		if bridge == nil {
+			continue
+		}
+		// lagging more than confirmations will delay verifying
+		if lag := serverHeight - height; lag > bridge.GetChainConfig().Confirmations {
+			if info.LaggingChains == nil {
+				info.LaggingChains = make(map[string]uint64)
+			}
+			info.LaggingChains[chainID] = lag
+		}
+	}
+}
+
 // GetStatusInfo api
 func GetStatusInfo(status string) (map[string]interface{}, error) {
-	return mongodb.GetStatusInfo(status)
+	return storage.Get().GetStatusInfo(status)
 }
 
-// ReportOracleInfo report oracle info
-func ReportOracleInfo(oracle string, info *OracleInfo) error {
+func isValidOracle(oracle string) bool {
 	mpcConfig := mpc.GetMPCConfig(false)
-	var exist bool
 	for _, enode := range mpcConfig.GetAllEnodes() {
 		if strings.EqualFold(oracle, enode) {
-			if !strings.EqualFold(oracle, mpcConfig.GetSelfEnode()) {
-				exist = true
-			}
-			break
+			return !strings.EqualFold(oracle, mpcConfig.GetSelfEnode())
 		}
 	}
-	if !exist {
+	return false
+}
+
+// ReportOracleInfo report oracle info
+func ReportOracleInfo(oracle string, info *OracleInfo) error {
+	if !isValidOracle(oracle) {
 		return newRPCError(-32000, "wrong oracle info")
 	}
 
 	key := strings.ToLower(oracle)
+	checkOracleInfo(info)
+	if info.StaleConfig || len(info.LaggingChains) > 0 {
+		log.Warn("oracle has stale config or lagging chains", "oracle", oracle, "staleConfig", info.StaleConfig, "laggingChains", info.LaggingChains)
+	}
 	if val, exist := oraclesInfo.Load(key); exist {
 		oldInfo := val.(*OracleInfo)
 		oldTime := oldInfo.HeartbeatTimestamp
@@ -97,6 +176,36 @@ func ReportOracleInfo(oracle string, info *OracleInfo) error {
 	return nil
 }
 
+// ReportOracleDisagrees save disagree reasons reported by oracle to swap results
+func ReportOracleDisagrees(oracle string, disagrees []*OracleDisagree) error {
+	if !isValidOracle(oracle) {
+		return newRPCError(-32000, "wrong oracle info")
+	}
+	if len(disagrees) > maxOracleDisagreesPerReport {
+		return newRPCError(-32000, "too many disagrees")
+	}
+	oracleID := getEnodeID(oracle)
+	reportTime := time.Now().Unix()
+	for _, d := range disagrees {
+		if !common.IsHexHash(d.TxID) || d.Code == "" {
+			continue
+		}
+		reason := d.Reason
+		if len(reason) > 1000 {
+			reason = reason[:1000]
+		}
+		_ = storage.Get().AddRouterSwapResultDisagree(d.FromChainID, d.TxID, d.LogIndex, &mongodb.DisagreeReason{
+			Oracle:     oracleID,
+			KeyID:      d.KeyID,
+			Code:       d.Code,
+			Reason:     reason,
+			Timestamp:  d.Timestamp,
+			ReportTime: reportTime,
+		})
+	}
+	return nil
+}
+
 // RegisterRouterSwap register router swap
 // if logIndex is 0 then check all logs, otherwise only check the specified log
 //nolint:funlen,gocyclo // allow long method
@@ -115,7 +224,7 @@ func RegisterRouterSwap(fromChainID, txid, logIndexStr string) (*MapIntResult, e
 	if bridge == nil {
 		return nil, newRPCInternalError(tokens.ErrNoBridgeForChainID)
 	}
-	_, registeredOk := mongodb.GetRegisteredRouterSwap(fromChainID, txid, logIndex)
+	_, registeredOk := storage.GetRegisteredRouterSwap(fromChainID, txid, logIndex)
 	if registeredOk {
 		return nil, errAlreadyRegistered
 	}
@@ -136,7 +245,7 @@ func RegisterRouterSwap(fromChainID, txid, logIndexStr string) (*MapIntResult, e
 			result[logIndex] = "verify error: " + memo
 			continue
 		}
-		oldSwap, registeredOk := mongodb.GetRegisteredRouterSwap(fromChainID, txid, logIndex)
+		oldSwap, registeredOk := storage.GetRegisteredRouterSwap(fromChainID, txid, logIndex)
 		if registeredOk {
 			result[logIndex] = "already registered"
 			continue
@@ -163,7 +272,7 @@ func RegisterRouterSwap(fromChainID, txid, logIndexStr string) (*MapIntResult, e
 			case newStatus != oldSwap.Status:
 				mgoSwapInfo := mongodb.ConvertToSwapInfo(&swapInfo.SwapInfo)
 				log.Info("[register] update swap info and status", "chainid", fromChainID, "txid", txid, "logIndex", logIndexStr, "oldStatus", oldSwap.Status, "newStatus", newStatus, "swapinfo", mgoSwapInfo)
-				err = mongodb.UpdateRouterSwapInfoAndStatus(fromChainID, txid, logIndex, &mgoSwapInfo, newStatus, time.Now().Unix(), memo)
+				err = storage.Get().UpdateRouterSwapInfoAndStatus(fromChainID, txid, logIndex, &mgoSwapInfo, newStatus, time.Now().Unix(), memo, "register")
 				worker.DeleteCachedVerifyingSwap(oldSwap.Key)
 			}
 		default:
@@ -196,7 +305,7 @@ func addMgoSwap(swapInfo *tokens.SwapTxInfo, status mongodb.SwapStatus, memo str
 		Memo:        memo,
 	}
 	swap.SwapInfo = mongodb.ConvertToSwapInfo(&swapInfo.SwapInfo)
-	err = mongodb.AddRouterSwap(swap)
+	err = storage.Get().AddRouterSwap(swap)
 	if err != nil {
 		log.Warn("[api] add router swap", "swap", swap, "err", err)
 	} else {
@@ -225,17 +334,33 @@ func GetRouterSwap(fromChainID, txid, logindexStr string) (*SwapInfo, error) {
 	if err != nil {
 		return nil, err
 	}
-	result, err := mongodb.FindRouterSwapResultAuto(fromChainID, txid, logindex)
+	result, err := storage.Get().FindRouterSwapResultAuto(fromChainID, txid, logindex)
 	if err == nil {
 		return ConvertMgoSwapResultToSwapInfo(result), nil
 	}
-	register, err := mongodb.FindRouterSwapAuto(fromChainID, txid, logindex)
+	register, err := storage.Get().FindRouterSwapAuto(fromChainID, txid, logindex)
 	if err == nil {
 		return ConvertMgoSwapToSwapInfo(register), nil
 	}
 	return nil, mongodb.ErrSwapNotFound
 }
 
+// GetRouterSwapBySwapTx impl
+func GetRouterSwapBySwapTx(swapTx string) (*SwapTxLookupResult, error) {
+	if swapTx == "" {
+		return nil, newRPCError(-32099, "empty swap tx")
+	}
+	res, err := storage.Get().FindRouterSwapResultBySwapTx(swapTx)
+	if err != nil {
+		return nil, mongodb.ErrSwapNotFound
+	}
+	_, isCurrent := mongodb.MatchSwapTx(res, swapTx)
+	return &SwapTxLookupResult{
+		Swap:      ConvertMgoSwapResultToSwapInfo(res),
+		IsCurrent: isCurrent,
+	}, nil
+}
+
 // GetRouterSwapHistory impl
 func GetRouterSwapHistory(fromChainID, address string, offset, limit int, status string) ([]*SwapInfo, error) {
 	switch {
@@ -246,7 +371,22 @@ func GetRouterSwapHistory(fromChainID, address string, offset, limit int, status
 	case limit < -100:
 		limit = -100
 	}
-	result, err := mongodb.FindRouterSwapResults(fromChainID, address, offset, limit, status)
+	result, err := storage.Get().FindRouterSwapResults(fromChainID, address, offset, limit, status)
+	if err != nil {
+		return nil, err
+	}
+	return ConvertMgoSwapResultsToSwapInfos(result), nil
+}
+
+// GetSwapResultsSince impl
+func GetSwapResultsSince(since int64, afterKey string, limit int) ([]*SwapInfo, error) {
+	switch {
+	case limit <= 0:
+		limit = 20 // default
+	case limit > 100:
+		limit = 100
+	}
+	result, err := storage.Get().FindRouterSwapResultsSince(since, strings.ToLower(afterKey), int64(limit))
 	if err != nil {
 		return nil, err
 	}
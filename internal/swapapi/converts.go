@@ -71,7 +71,10 @@ func ConvertMgoSwapResultToSwapInfo(mr *mongodb.MgoSwapResult) *SwapInfo {
 		Timestamp:     mr.Timestamp,
 		Memo:          mr.Memo,
 		ReplaceCount:  len(mr.OldSwapTxs),
+		OldSwapTxs:    mr.OldSwapTxs,
 		Confirmations: confirmations,
+		SwapGasLimit:  mr.SwapGasLimit,
+		SwapGasUsed:   mr.SwapGasUsed,
 	}
 }
 
@@ -0,0 +1,258 @@
+package swapapi
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"strconv"
+	"strings"
+
+	"github.com/anyswap/CrossChain-Router/v3/common"
+	"github.com/anyswap/CrossChain-Router/v3/mongodb"
+	"github.com/anyswap/CrossChain-Router/v3/router"
+	"github.com/anyswap/CrossChain-Router/v3/storage"
+	"github.com/anyswap/CrossChain-Router/v3/tokens"
+)
+
+// export formats
+const (
+	ExportFormatCSV   = "csv"
+	ExportFormatJSONL = "jsonl"
+)
+
+var (
+	defaultExportLimit = 500
+	maxExportLimit     = 1000
+
+	exportColumns = []string{
+		"fromChainID", "toChainID", "tokenID", "txid", "logIndex", "from", "bind",
+		"value", "swapvalue", "fee", "fromDecimals", "toDecimals", "swaptx",
+		"status", "statusName", "txtime", "swaptime", "inittime",
+	}
+)
+
+// ExportArgs args of exporting swap results
+type ExportArgs struct {
+	Format      string `json:"format"`    // csv (default) or jsonl
+	StartTime   int64  `json:"start"`     // registered time in seconds (inclusive)
+	EndTime     int64  `json:"end"`       // registered time in seconds (exclusive), 0 means no end
+	TokenID     string `json:"tokenid"`   // optional
+	FromChainID string `json:"fromchain"` // optional
+	ToChainID   string `json:"tochain"`   // optional
+	Status      string `json:"status"`    // comma separated swap result statuses, default is MatchTxStable
+	Cursor      string `json:"cursor"`    // returned by the previous export call
+	Limit       int    `json:"limit"`
+}
+
+// ExportResult result of exporting swap results
+type ExportResult struct {
+	Data   string `json:"data"` // csv has header line if cursor is empty
+	Count  int    `json:"count"`
+	Cursor string `json:"cursor,omitempty"` // empty means no more swap results
+}
+
+// ExportRecord swap result record for accounting
+type ExportRecord struct {
+	FromChainID  string `json:"fromChainID"`
+	ToChainID    string `json:"toChainID"`
+	TokenID      string `json:"tokenID"`
+	TxID         string `json:"txid"`
+	LogIndex     int    `json:"logIndex"`
+	From         string `json:"from"`
+	Bind         string `json:"bind"`
+	Value        string `json:"value"`
+	SwapValue    string `json:"swapvalue"`
+	Fee          string `json:"fee"` // value minus swapvalue, in decimals of source token
+	FromDecimals *uint8 `json:"fromDecimals"`
+	ToDecimals   *uint8 `json:"toDecimals"`
+	SwapTx       string `json:"swaptx"`
+	Status       uint32 `json:"status"`
+	StatusName   string `json:"statusName"`
+	TxTime       uint64 `json:"txtime"`
+	SwapTime     uint64 `json:"swaptime"`
+	InitTime     int64  `json:"inittime"` // registered time in seconds
+}
+
+// ExportSwapResults export swap results in csv or jsonl format, call it
+// repeatedly with the returned cursor to export all the matched ones.
+func ExportSwapResults(args *ExportArgs) (*ExportResult, error) {
+	format := strings.ToLower(args.Format)
+	switch format {
+	case "":
+		format = ExportFormatCSV
+	case ExportFormatCSV, ExportFormatJSONL:
+	default:
+		return nil, newRPCError(-32099, "unknown export format "+args.Format)
+	}
+	limit := args.Limit
+	switch {
+	case limit <= 0:
+		limit = defaultExportLimit
+	case limit > maxExportLimit:
+		limit = maxExportLimit
+	}
+
+	filter, err := getExportFilter(args)
+	if err != nil {
+		return nil, err
+	}
+	results, err := storage.Get().FindRouterSwapResultsToExport(filter, int64(limit))
+	if err != nil {
+		return nil, newRPCInternalError(err)
+	}
+	records := make([]*ExportRecord, len(results))
+	for i, res := range results {
+		records[i] = convertToExportRecord(res)
+	}
+
+	var data string
+	if format == ExportFormatCSV {
+		data, err = formatCSVRecords(records, args.Cursor == "")
+	} else {
+		data, err = formatJSONLRecords(records)
+	}
+	if err != nil {
+		return nil, newRPCInternalError(err)
+	}
+
+	result := &ExportResult{Data: data, Count: len(records)}
+	if len(results) == limit {
+		last := results[len(results)-1]
+		result.Cursor = fmt.Sprintf("%d:%s", last.InitTime, last.Key)
+	}
+	return result, nil
+}
+
+func getExportFilter(args *ExportArgs) (*mongodb.ExportFilter, error) {
+	filter := &mongodb.ExportFilter{
+		StartTime:   args.StartTime * 1000,
+		EndTime:     args.EndTime * 1000,
+		TokenID:     args.TokenID,
+		FromChainID: args.FromChainID,
+		ToChainID:   args.ToChainID,
+	}
+	if args.EndTime != 0 && args.EndTime <= args.StartTime {
+		return nil, newRPCError(-32099, "end time is not after start time")
+	}
+	if args.Status == "" {
+		filter.Statuses = []mongodb.SwapStatus{mongodb.MatchTxStable}
+	} else {
+		registerStatuses, resultStatuses := mongodb.GetStatusesFromStr(args.Status)
+		if len(registerStatuses) > 0 || len(resultStatuses) == 0 {
+			return nil, newRPCError(-32099, "wrong swap result status "+args.Status)
+		}
+		filter.Statuses = resultStatuses
+	}
+	if args.Cursor != "" {
+		parts := strings.SplitN(args.Cursor, ":", 2)
+		initTime, err := strconv.ParseInt(parts[0], 10, 64)
+		if err != nil || len(parts) != 2 || parts[1] == "" {
+			return nil, newRPCError(-32099, "wrong export cursor "+args.Cursor)
+		}
+		filter.AfterInitTime, filter.AfterKey = initTime, parts[1]
+	}
+	return filter, nil
+}
+
+func convertToExportRecord(res *mongodb.MgoSwapResult) *ExportRecord {
+	record := &ExportRecord{
+		FromChainID: res.FromChainID,
+		ToChainID:   res.ToChainID,
+		TxID:        res.TxID,
+		LogIndex:    res.LogIndex,
+		From:        res.From,
+		Bind:        res.Bind,
+		Value:       res.Value,
+		SwapValue:   res.SwapValue,
+		SwapTx:      res.SwapTx,
+		Status:      uint32(res.Status),
+		StatusName:  res.Status.String(),
+		TxTime:      res.TxTime,
+		SwapTime:    res.SwapTime,
+		InitTime:    res.InitTime / 1000,
+	}
+	if res.ERC20SwapInfo != nil {
+		record.TokenID = res.ERC20SwapInfo.TokenID
+		record.Fee, record.FromDecimals, record.ToDecimals = calcSwapFee(res)
+	}
+	return record
+}
+
+// calc fee in decimals of source token, returns empty fee if token config is unknown
+func calcSwapFee(res *mongodb.MgoSwapResult) (fee string, fromDecimals, toDecimals *uint8) {
+	fromDecimals = getTokenDecimals(res.FromChainID, res.ERC20SwapInfo.Token)
+	toToken := router.GetCachedMultichainToken(res.ERC20SwapInfo.TokenID, res.ToChainID)
+	toDecimals = getTokenDecimals(res.ToChainID, toToken)
+	if fromDecimals == nil || toDecimals == nil || res.SwapValue == "" {
+		return "", fromDecimals, toDecimals
+	}
+	value, err := common.GetBigIntFromStr(res.Value)
+	if err != nil {
+		return "", fromDecimals, toDecimals
+	}
+	swapValue, err := common.GetBigIntFromStr(res.SwapValue)
+	if err != nil {
+		return "", fromDecimals, toDecimals
+	}
+	swapValue = tokens.ConvertTokenValue(swapValue, *toDecimals, *fromDecimals)
+	return new(big.Int).Sub(value, swapValue).String(), fromDecimals, toDecimals
+}
+
+func getTokenDecimals(chainID, token string) *uint8 {
+	if token == "" {
+		return nil
+	}
+	bridge := router.GetBridgeByChainID(chainID)
+	if bridge == nil {
+		return nil
+	}
+	tokenCfg := bridge.GetTokenConfig(token)
+	if tokenCfg == nil {
+		return nil
+	}
+	decimals := tokenCfg.Decimals
+	return &decimals
+}
+
+func formatCSVRecords(records []*ExportRecord, withHeader bool) (string, error) {
+	var buf bytes.Buffer
+	w := csv.NewWriter(&buf)
+	if withHeader {
+		if err := w.Write(exportColumns); err != nil {
+			return "", err
+		}
+	}
+	for _, r := range records {
+		row := []string{
+			r.FromChainID, r.ToChainID, r.TokenID, r.TxID, strconv.Itoa(r.LogIndex), r.From, r.Bind,
+			r.Value, r.SwapValue, r.Fee, formatDecimals(r.FromDecimals), formatDecimals(r.ToDecimals), r.SwapTx,
+			strconv.FormatUint(uint64(r.Status), 10), r.StatusName,
+			strconv.FormatUint(r.TxTime, 10), strconv.FormatUint(r.SwapTime, 10), strconv.FormatInt(r.InitTime, 10),
+		}
+		if err := w.Write(row); err != nil {
+			return "", err
+		}
+	}
+	w.Flush()
+	return buf.String(), w.Error()
+}
+
+func formatDecimals(decimals *uint8) string {
+	if decimals == nil {
+		return ""
+	}
+	return strconv.Itoa(int(*decimals))
+}
+
+func formatJSONLRecords(records []*ExportRecord) (string, error) {
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	for _, r := range records {
+		if err := enc.Encode(r); err != nil {
+			return "", err
+		}
+	}
+	return buf.String(), nil
+}
@@ -24,6 +24,33 @@ type ServerInfo struct {
 type OracleInfo struct {
 	Heartbeat          string
 	HeartbeatTimestamp int64
+
+	// reported by oracle
+	Version          string `json:",omitempty"`
+	ConfigHash       string `json:",omitempty"`
+	MPCConnected     bool
+	FastMPCConnected *bool `json:",omitempty"`
+	AcceptQueue      int64
+	AcceptRoutines   int64
+	AgreeCount       uint64
+	DisagreeCount    uint64
+	ChainHeights     map[string]uint64 `json:",omitempty"` // key is chain ID
+
+	// judged by server
+	IsStale       bool              // no heartbeat for a long time
+	StaleConfig   bool              // config hash is different from server's
+	LaggingChains map[string]uint64 `json:",omitempty"` // chain ID -> lagging blocks
+}
+
+// OracleDisagree disagree reason of swap reported by oracle
+type OracleDisagree struct {
+	FromChainID string `json:"fromChainID"`
+	TxID        string `json:"txid"`
+	LogIndex    int    `json:"logIndex"`
+	KeyID       string `json:"keyID"`
+	Code        string `json:"code"`
+	Reason      string `json:"reason"`
+	Timestamp   int64  `json:"timestamp"`
 }
 
 // SwapInfo swap info
@@ -50,7 +77,16 @@ type SwapInfo struct {
 	Timestamp     int64              `json:"timestamp"`
 	Memo          string             `json:"memo,omitempty"`
 	ReplaceCount  int                `json:"replaceCount,omitempty"`
+	OldSwapTxs    []string           `json:"oldswaptxs,omitempty"`
 	Confirmations uint64             `json:"confirmations"`
+	SwapGasLimit  uint64             `json:"swapgaslimit,omitempty"`
+	SwapGasUsed   uint64             `json:"swapgasused,omitempty"`
+}
+
+// SwapTxLookupResult swap found by its swap tx on dest chain
+type SwapTxLookupResult struct {
+	Swap      *SwapInfo `json:"swap"`
+	IsCurrent bool      `json:"isCurrent"` // false means it is a replaced old swap tx
 }
 
 // ChainConfig rpc type
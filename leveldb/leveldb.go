@@ -9,6 +9,7 @@ import (
 	dberrors "github.com/syndtr/goleveldb/leveldb/errors"
 	"github.com/syndtr/goleveldb/leveldb/filter"
 	"github.com/syndtr/goleveldb/leveldb/opt"
+	"github.com/syndtr/goleveldb/leveldb/storage"
 	"github.com/syndtr/goleveldb/leveldb/util"
 
 	"github.com/anyswap/CrossChain-Router/v3/log"
@@ -61,6 +62,15 @@ func New(path string, cache int, handles int, readonly bool) (*Database, error)
 	})
 }
 
+// NewMemory returns a wrapped LevelDB object on memory storage.
+func NewMemory() (*Database, error) {
+	db, err := goleveldb.Open(storage.NewMemStorage(), nil)
+	if err != nil {
+		return nil, err
+	}
+	return &Database{lvldb: db}, nil
+}
+
 // NewCustom returns a wrapped LevelDB object.
 // The customize function allows the caller to modify the leveldb options.
 func NewCustom(path string, customize func(options *opt.Options)) (*Database, error) {
@@ -10,7 +10,6 @@ import (
 
 	"github.com/anyswap/CrossChain-Router/v3/common"
 	"github.com/anyswap/CrossChain-Router/v3/log"
-	"github.com/anyswap/CrossChain-Router/v3/router"
 	"github.com/anyswap/CrossChain-Router/v3/tokens"
 
 	"go.mongodb.org/mongo-driver/bson"
@@ -31,6 +30,10 @@ var (
 	updateOldSwapTxsLock sync.Mutex
 
 	maxCountOfResults = int64(1000)
+
+	maxDisagreesOfSwap = 50
+
+	maxStatusHistory = 100
 )
 
 // GetRouterSwapKey get router swap key
@@ -38,10 +41,61 @@ func GetRouterSwapKey(fromChainID, txid string, logindex int) string {
 	return strings.ToLower(fmt.Sprintf("%v:%v:%v", fromChainID, txid, logindex))
 }
 
-// AddRouterSwap add router swap
+// getVersionFilter filter by document version (no version field means version 0)
+func getVersionFilter(key string, version uint64) bson.M {
+	if version == 0 {
+		return bson.M{"_id": key, "version": bson.M{"$in": bson.A{0, nil}}}
+	}
+	return bson.M{"_id": key, "version": version}
+}
+
+// updateWithVersion update document only if its version is not changed since read,
+// and increase the version (optimistic concurrency control)
+func updateWithVersion(coll *mongo.Collection, key string, version uint64, update bson.M) error {
+	update["$inc"] = bson.M{"version": 1}
+	res, err := coll.UpdateOne(clientCtx, getVersionFilter(key, version), update)
+	if err != nil {
+		return mgoError(err)
+	}
+	if res.MatchedCount == 0 {
+		count, errc := coll.CountDocuments(clientCtx, bson.M{"_id": key})
+		if errc == nil && count == 0 {
+			return ErrItemNotFound
+		}
+		return ErrVersionConflict
+	}
+	return nil
+}
+
+// updateStatusWithVersion update status with document version checked,
+// and record the status transition in the status history
+func updateStatusWithVersion(coll *mongo.Collection, key string, version uint64, from, to SwapStatus, updates bson.M, actor, memo string, timestamp int64) error {
+	update := bson.M{"$set": updates}
+	if from != to {
+		change := &StatusChange{
+			From:      from,
+			To:        to,
+			Actor:     actor,
+			Memo:      memo,
+			Timestamp: timestamp,
+		}
+		update["$push"] = bson.M{"statushistory": bson.M{
+			"$each":  []*StatusChange{change},
+			"$slice": -maxStatusHistory,
+		}}
+	}
+	return updateWithVersion(coll, key, version, update)
+}
+
+// AddRouterSwap add router swap (init time is now if not given)
 func AddRouterSwap(ms *MgoSwap) error {
 	ms.Key = GetRouterSwapKey(ms.FromChainID, ms.TxID, ms.LogIndex)
-	ms.InitTime = common.NowMilli()
+	if ms.InitTime == 0 {
+		ms.InitTime = common.NowMilli()
+	}
+	if isArchived(collRouterSwapArchive, ms.Key) {
+		return ErrItemIsDup
+	}
 	_, err := collRouterSwap.InsertOne(clientCtx, ms)
 	switch {
 	case err == nil:
@@ -54,7 +108,7 @@ func AddRouterSwap(ms *MgoSwap) error {
 		if errt == nil && swap.Status == TxNotSwapped {
 			now := time.Now().Unix()
 			if swap.Timestamp+3*24*3600 < now {
-				_, _ = collRouterSwap.UpdateByID(clientCtx, ms.Key, bson.M{"$set": bson.M{"timestamp": now}})
+				_ = updateWithVersion(collRouterSwap, ms.Key, swap.Version, bson.M{"$set": bson.M{"timestamp": now}})
 			}
 		}
 	}
@@ -76,7 +130,7 @@ func PassRouterSwapVerify(fromChainID, txid string, logindex int, timestamp int6
 
 	key := GetRouterSwapKey(fromChainID, txid, logindex)
 	updates := bson.M{"status": TxNotSwapped, "timestamp": timestamp}
-	_, err = collRouterSwap.UpdateByID(clientCtx, key, bson.M{"$set": updates})
+	err = updateStatusWithVersion(collRouterSwap, key, swap.Version, swap.Status, TxNotSwapped, updates, "verify", "", timestamp)
 	if err == nil {
 		log.Info("mongodb pass verify success", "chainid", fromChainID, "txid", txid, "logindex", logindex)
 	} else {
@@ -85,11 +139,20 @@ func PassRouterSwapVerify(fromChainID, txid string, logindex int, timestamp int6
 	return mgoError(err)
 }
 
-// UpdateRouterSwapStatus update router swap status
-func UpdateRouterSwapStatus(fromChainID, txid string, logindex int, status SwapStatus, timestamp int64, memo string) error {
+// UpdateRouterSwapStatus update router swap status (`actor` is worker job or admin)
+func UpdateRouterSwapStatus(fromChainID, txid string, logindex int, status SwapStatus, timestamp int64, memo, actor string) error {
 	if status == TxNotStable {
 		return errors.New("forbid update swap status to TxNotStable")
 	}
+	swap, err := FindRouterSwap(fromChainID, txid, logindex)
+	if err != nil {
+		return err
+	}
+	err = CheckSwapStatusTransition(swap.Status, status)
+	if err != nil {
+		log.Warn("mongodb update router swap status forbidden", "chainid", fromChainID, "txid", txid, "logindex", logindex, "actor", actor, "err", err)
+		return err
+	}
 	key := GetRouterSwapKey(fromChainID, txid, logindex)
 	updates := bson.M{"status": status, "timestamp": timestamp}
 	if memo != "" {
@@ -97,18 +160,18 @@ func UpdateRouterSwapStatus(fromChainID, txid string, logindex int, status SwapS
 	} else if status == TxNotSwapped {
 		updates["memo"] = ""
 	}
-	_, err := collRouterSwap.UpdateByID(clientCtx, key, bson.M{"$set": updates})
+	err = updateStatusWithVersion(collRouterSwap, key, swap.Version, swap.Status, status, updates, actor, memo, timestamp)
 	if err == nil {
 		logFunc := log.GetPrintFuncOr(func() bool { return status == TxVerifyFailed }, log.Warn, log.Info)
 		logFunc("mongodb update router swap status success", "chainid", fromChainID, "txid", txid, "logindex", logindex, "status", status)
 	} else {
 		log.Error("mongodb update router swap status failed", "chainid", fromChainID, "txid", txid, "logindex", logindex, "status", status, "err", err)
 	}
-	return mgoError(err)
+	return err
 }
 
 // UpdateRouterSwapInfoAndStatus update router swap info and status
-func UpdateRouterSwapInfoAndStatus(fromChainID, txid string, logindex int, swapInfo *SwapInfo, status SwapStatus, timestamp int64, memo string) error {
+func UpdateRouterSwapInfoAndStatus(fromChainID, txid string, logindex int, swapInfo *SwapInfo, status SwapStatus, timestamp int64, memo, actor string) error {
 	retryLock.Lock()
 	defer retryLock.Unlock()
 
@@ -121,6 +184,10 @@ func UpdateRouterSwapInfoAndStatus(fromChainID, txid string, logindex int, swapI
 	if swap.Status.IsRegisteredOk() {
 		return fmt.Errorf("forbid update swap info from registered status %v", swap.Status.String())
 	}
+	err = CheckSwapStatusTransition(swap.Status, status)
+	if err != nil {
+		return err
+	}
 
 	result := &MgoSwapResult{}
 	err = collRouterSwapResult.FindOne(clientCtx, bson.M{"_id": key}).Decode(result)
@@ -136,13 +203,13 @@ func UpdateRouterSwapInfoAndStatus(fromChainID, txid string, logindex int, swapI
 		"memo":      memo,
 	}
 
-	_, err = collRouterSwap.UpdateByID(clientCtx, key, bson.M{"$set": updates})
+	err = updateStatusWithVersion(collRouterSwap, key, swap.Version, swap.Status, status, updates, actor, memo, timestamp)
 	if err == nil {
 		log.Info("mongodb update router swap info and status success", "chainid", fromChainID, "txid", txid, "logindex", logindex, "status", status, "swapinfo", swapInfo)
 	} else {
 		log.Error("mongodb update router swap info and status failed", "chainid", fromChainID, "txid", txid, "logindex", logindex, "status", status, "swapinfo", swapInfo, "err", err)
 	}
-	return mgoError(err)
+	return err
 }
 
 // FindRouterSwap find router swap
@@ -156,18 +223,24 @@ func FindRouterSwap(fromChainID, txid string, logindex int) (*MgoSwap, error) {
 	return result, nil
 }
 
-// FindRouterSwapAuto find router swap
+// FindRouterSwapAuto find router swap (fallback to archive)
 func FindRouterSwapAuto(fromChainID, txid string, logindex int) (*MgoSwap, error) {
-	if logindex == 0 {
-		return findFirstRouterSwap(fromChainID, txid)
+	result, err := findRouterSwapAuto(collRouterSwap, fromChainID, txid, logindex)
+	if errors.Is(err, ErrItemNotFound) {
+		return findRouterSwapAuto(collRouterSwapArchive, fromChainID, txid, logindex)
 	}
-	return FindRouterSwap(fromChainID, txid, logindex)
+	return result, err
 }
 
-func findFirstRouterSwap(fromChainID, txid string) (*MgoSwap, error) {
+func findRouterSwapAuto(coll *mongo.Collection, fromChainID, txid string, logindex int) (*MgoSwap, error) {
+	var query bson.M
+	if logindex == 0 {
+		query = getChainAndTxIDQuery(fromChainID, txid)
+	} else {
+		query = bson.M{"_id": GetRouterSwapKey(fromChainID, txid, logindex)}
+	}
 	result := &MgoSwap{}
-	query := getChainAndTxIDQuery(fromChainID, txid)
-	err := collRouterSwap.FindOne(clientCtx, query).Decode(result)
+	err := coll.FindOne(clientCtx, query).Decode(result)
 	if err != nil {
 		return nil, mgoError(err)
 	}
@@ -234,10 +307,15 @@ func FindRouterSwapsWithChainIDAndStatus(fromChainID string, status SwapStatus,
 	return result, nil
 }
 
-// AddRouterSwapResult add router swap result
+// AddRouterSwapResult add router swap result (init time is now if not given)
 func AddRouterSwapResult(mr *MgoSwapResult) error {
 	mr.Key = GetRouterSwapKey(mr.FromChainID, mr.TxID, mr.LogIndex)
-	mr.InitTime = common.NowMilli()
+	if mr.InitTime == 0 {
+		mr.InitTime = common.NowMilli()
+	}
+	if isArchived(collRouterSwapResultArchive, mr.Key) {
+		return ErrItemIsDup
+	}
 	_, err := collRouterSwapResult.InsertOne(clientCtx, mr)
 	if err == nil {
 		log.Info("mongodb add router swap result success", "chainid", mr.FromChainID, "txid", mr.TxID, "logindex", mr.LogIndex)
@@ -261,7 +339,11 @@ func AllocateRouterSwapNonce(args *tokens.BuildTxArgs, nonceptr *uint64, isRecyc
 		return 0, errors.New("swap nonce is alreay recycled")
 	}
 
-	err = checkRouterSwapResultUpdate(fromChainID, txid, logindex, swapnonce)
+	swapRes, err := checkRouterSwapResultUpdate(fromChainID, txid, logindex, swapnonce)
+	if err != nil {
+		return 0, err
+	}
+	err = CheckSwapResultStatusTransition(swapRes.Status, MatchTxNotStable)
 	if err != nil {
 		return 0, err
 	}
@@ -278,16 +360,15 @@ func AllocateRouterSwapNonce(args *tokens.BuildTxArgs, nonceptr *uint64, isRecyc
 	if args.SwapValue != nil {
 		resUpdates["swapvalue"] = args.SwapValue.String()
 	}
-	_, err = collRouterSwapResult.UpdateByID(clientCtx, key, bson.M{"$set": resUpdates})
+	err = updateStatusWithVersion(collRouterSwapResult, key, swapRes.Version, swapRes.Status, MatchTxNotStable, resUpdates, "swap", "", nowTime)
 	if err != nil {
 		log.Warn("mongodb allocate swap nonce failed", "chainid", fromChainID, "txid", txid, "logindex", logindex, "swapnonce", swapnonce, "err", err)
-		return 0, mgoError(err)
+		return 0, err
 	}
 
 	log.Info("mongodb allocate swap nonce success", "chainid", fromChainID, "txid", txid, "logindex", logindex, "swapnonce", swapnonce)
 
-	statusUpdates := bson.M{"status": TxProcessed, "timestamp": nowTime}
-	_, errf := collRouterSwap.UpdateByID(clientCtx, key, bson.M{"$set": statusUpdates})
+	errf := UpdateRouterSwapStatus(fromChainID, txid, logindex, TxProcessed, nowTime, "", "swap")
 	if errf != nil {
 		log.Warn("mongodb update swap status to TxProcessed failed", "chainid", fromChainID, "txid", txid, "logindex", logindex, "swapnonce", swapnonce, "err", errf)
 	}
@@ -300,8 +381,17 @@ func AllocateRouterSwapNonce(args *tokens.BuildTxArgs, nonceptr *uint64, isRecyc
 	return swapnonce, nil
 }
 
-// UpdateRouterSwapResultStatus update router swap result status
-func UpdateRouterSwapResultStatus(fromChainID, txid string, logindex int, status SwapStatus, timestamp int64, memo string) error {
+// UpdateRouterSwapResultStatus update router swap result status (`actor` is worker job or admin)
+func UpdateRouterSwapResultStatus(fromChainID, txid string, logindex int, status SwapStatus, timestamp int64, memo, actor string) error {
+	swapRes, err := FindRouterSwapResult(fromChainID, txid, logindex)
+	if err != nil {
+		return err
+	}
+	err = CheckSwapResultStatusTransition(swapRes.Status, status)
+	if err != nil {
+		log.Warn("mongodb update swap result status forbidden", "chainid", fromChainID, "txid", txid, "logindex", logindex, "actor", actor, "err", err)
+		return err
+	}
 	key := GetRouterSwapKey(fromChainID, txid, logindex)
 	updates := bson.M{"status": status, "timestamp": timestamp}
 	if memo != "" {
@@ -315,13 +405,13 @@ func UpdateRouterSwapResultStatus(fromChainID, txid string, logindex int, status
 		updates["swaptime"] = 0
 		updates["swapnonce"] = 0
 	}
-	_, err := collRouterSwapResult.UpdateByID(clientCtx, key, bson.M{"$set": updates})
+	err = updateStatusWithVersion(collRouterSwapResult, key, swapRes.Version, swapRes.Status, status, updates, actor, memo, timestamp)
 	if err == nil {
 		log.Info("mongodb update swap result status success", "chainid", fromChainID, "txid", txid, "logindex", logindex, "status", status)
 	} else {
 		log.Error("mongodb update swap result status failed", "chainid", fromChainID, "txid", txid, "logindex", logindex, "status", status, "err", err)
 	}
-	return mgoError(err)
+	return err
 }
 
 // UpdateRouterOldSwapTxs update old swaptxs by appending `swapTx`
@@ -364,12 +454,41 @@ func UpdateRouterOldSwapTxs(fromChainID, txid string, logindex int, swapTx strin
 	}
 
 	key := GetRouterSwapKey(fromChainID, txid, logindex)
-	_, err = collRouterSwapResult.UpdateByID(clientCtx, key, updates)
+	err = updateWithVersion(collRouterSwapResult, key, swapRes.Version, updates)
 	if err == nil {
 		log.Info("UpdateRouterOldSwapTxs success", "fromChainID", fromChainID, "txid", txid, "logIndex", logindex, "swaptx", swapTx, "nonce", swapRes.SwapNonce)
 	} else {
 		log.Error("UpdateRouterOldSwapTxs failed", "fromChainID", fromChainID, "txid", txid, "logIndex", logindex, "swaptx", swapTx, "nonce", swapRes.SwapNonce, "err", err)
 	}
+	return err
+}
+
+// AddRouterSwapTxAttempt add swap tx attempt with fee params
+func AddRouterSwapTxAttempt(fromChainID, txid string, logindex int, attempt *SwapTxAttempt) error {
+	key := GetRouterSwapKey(fromChainID, txid, logindex)
+	updates := bson.M{"$push": bson.M{"swaptxattempts": attempt}}
+	_, err := collRouterSwapResult.UpdateByID(clientCtx, key, updates)
+	if err == nil {
+		log.Info("AddRouterSwapTxAttempt success", "fromChainID", fromChainID, "txid", txid, "logIndex", logindex, "swaptx", attempt.SwapTx, "nonce", attempt.Nonce, "replaceNum", attempt.ReplaceNum)
+	} else {
+		log.Error("AddRouterSwapTxAttempt failed", "fromChainID", fromChainID, "txid", txid, "logIndex", logindex, "swaptx", attempt.SwapTx, "nonce", attempt.Nonce, "replaceNum", attempt.ReplaceNum, "err", err)
+	}
+	return mgoError(err)
+}
+
+// AddRouterSwapResultDisagree add disagree reason of oracle (keep the latest ones)
+func AddRouterSwapResultDisagree(fromChainID, txid string, logindex int, disagree *DisagreeReason) error {
+	key := GetRouterSwapKey(fromChainID, txid, logindex)
+	updates := bson.M{"$push": bson.M{"disagrees": bson.M{
+		"$each":  []*DisagreeReason{disagree},
+		"$slice": -maxDisagreesOfSwap,
+	}}}
+	_, err := collRouterSwapResult.UpdateByID(clientCtx, key, updates)
+	if err == nil {
+		log.Info("AddRouterSwapResultDisagree success", "fromChainID", fromChainID, "txid", txid, "logIndex", logindex, "oracle", disagree.Oracle, "keyID", disagree.KeyID, "code", disagree.Code)
+	} else {
+		log.Error("AddRouterSwapResultDisagree failed", "fromChainID", fromChainID, "txid", txid, "logIndex", logindex, "oracle", disagree.Oracle, "keyID", disagree.KeyID, "code", disagree.Code, "err", err)
+	}
 	return mgoError(err)
 }
 
@@ -384,18 +503,24 @@ func FindRouterSwapResult(fromChainID, txid string, logindex int) (*MgoSwapResul
 	return result, nil
 }
 
-// FindRouterSwapResultAuto find router swap result
+// FindRouterSwapResultAuto find router swap result (fallback to archive)
 func FindRouterSwapResultAuto(fromChainID, txid string, logindex int) (*MgoSwapResult, error) {
-	if logindex == 0 {
-		return findFirstRouterSwapResult(fromChainID, txid)
+	result, err := findRouterSwapResultAuto(collRouterSwapResult, fromChainID, txid, logindex)
+	if errors.Is(err, ErrItemNotFound) {
+		return findRouterSwapResultAuto(collRouterSwapResultArchive, fromChainID, txid, logindex)
 	}
-	return FindRouterSwapResult(fromChainID, txid, logindex)
+	return result, err
 }
 
-func findFirstRouterSwapResult(fromChainID, txid string) (*MgoSwapResult, error) {
+func findRouterSwapResultAuto(coll *mongo.Collection, fromChainID, txid string, logindex int) (*MgoSwapResult, error) {
+	var query bson.M
+	if logindex == 0 {
+		query = getChainAndTxIDQuery(fromChainID, txid)
+	} else {
+		query = bson.M{"_id": GetRouterSwapKey(fromChainID, txid, logindex)}
+	}
 	result := &MgoSwapResult{}
-	query := getChainAndTxIDQuery(fromChainID, txid)
-	err := collRouterSwapResult.FindOne(clientCtx, query).Decode(result)
+	err := coll.FindOne(clientCtx, query).Decode(result)
 	if err != nil {
 		return nil, mgoError(err)
 	}
@@ -451,6 +576,9 @@ func FindNextSwapNonce(chainID, mpc string) (uint64, error) {
 	}
 	result := &MgoSwapResult{}
 	err := collRouterSwapResult.FindOne(clientCtx, bson.M{"$and": queries}, opts).Decode(result)
+	if errors.Is(err, mongo.ErrNoDocuments) { // all are archived
+		err = collRouterSwapResultArchive.FindOne(clientCtx, bson.M{"$and": queries}, opts).Decode(result)
+	}
 	if err != nil {
 		log.Error("FindNextSwapNonce failed", "chainID", chainID, "mpc", mpc, "err", err)
 		return 0, mgoError(err)
@@ -459,6 +587,76 @@ func FindNextSwapNonce(chainID, mpc string) (uint64, error) {
 	return result.SwapNonce + 1, nil
 }
 
+// FindRouterSwapResultsWithNonceRange find swap results of mpc with swap nonce in range [fromNonce, toNonce)
+func FindRouterSwapResultsWithNonceRange(chainID, mpc string, fromNonce, toNonce uint64) ([]*MgoSwapResult, error) {
+	qchainid := bson.M{"toChainID": chainID}
+	qmpc := bson.M{"mpc": bson.M{"$regex": primitive.Regex{Pattern: mpc, Options: "i"}}}
+	qnonce := bson.M{"swapnonce": bson.M{"$gte": fromNonce, "$lt": toNonce}}
+	queries := []bson.M{qchainid, qmpc, qnonce}
+	opts := &options.FindOptions{
+		Sort: bson.D{{Key: "swapnonce", Value: 1}},
+	}
+	cur, err := collRouterSwapResult.Find(clientCtx, bson.M{"$and": queries}, opts)
+	if err != nil {
+		return nil, mgoError(err)
+	}
+	result := make([]*MgoSwapResult, 0, 20)
+	err = cur.All(clientCtx, &result)
+	if err != nil {
+		return nil, mgoError(err)
+	}
+	return result, nil
+}
+
+// FindRouterSwapResultsWithPassedNonce find not stable swap results of mpc
+// whose swap nonce is lower than the latest account nonce but swap tx is not on chain
+func FindRouterSwapResultsWithPassedNonce(chainID, mpc string, latestNonce uint64) ([]*MgoSwapResult, error) {
+	qchainid := bson.M{"toChainID": chainID}
+	qmpc := bson.M{"mpc": bson.M{"$regex": primitive.Regex{Pattern: mpc, Options: "i"}}}
+	qnonce := bson.M{"swapnonce": bson.M{"$gt": 0, "$lt": latestNonce}}
+	qstatus := bson.M{"status": MatchTxNotStable}
+	qheight := bson.M{"swapheight": 0}
+	queries := []bson.M{qchainid, qmpc, qnonce, qstatus, qheight}
+	limit := int64(100)
+	opts := &options.FindOptions{
+		Sort:  bson.D{{Key: "swapnonce", Value: 1}},
+		Limit: &limit,
+	}
+	cur, err := collRouterSwapResult.Find(clientCtx, bson.M{"$and": queries}, opts)
+	if err != nil {
+		return nil, mgoError(err)
+	}
+	result := make([]*MgoSwapResult, 0, 20)
+	err = cur.All(clientCtx, &result)
+	if err != nil {
+		return nil, mgoError(err)
+	}
+	return result, nil
+}
+
+// FindRouterSwapResultsSince find swap results updated since timestamp,
+// those updated at `since` are after `afterKey` (page by timestamp and key)
+func FindRouterSwapResultsSince(since int64, afterKey string, limit int64) ([]*MgoSwapResult, error) {
+	qtime := bson.M{"$or": []bson.M{
+		{"timestamp": bson.M{"$gt": since}},
+		{"timestamp": since, "_id": bson.M{"$gt": afterKey}},
+	}}
+	opts := &options.FindOptions{
+		Sort:  bson.D{{Key: "timestamp", Value: 1}, {Key: "_id", Value: 1}},
+		Limit: &limit,
+	}
+	cur, err := collRouterSwapResult.Find(clientCtx, qtime, opts)
+	if err != nil {
+		return nil, mgoError(err)
+	}
+	result := make([]*MgoSwapResult, 0, limit)
+	err = cur.All(clientCtx, &result)
+	if err != nil {
+		return nil, mgoError(err)
+	}
+	return result, nil
+}
+
 // FindRouterSwapResultsToStable find swap results to stable
 func FindRouterSwapResultsToStable(chainID string, septime int64) ([]*MgoSwapResult, error) {
 	qtime := bson.M{"inittime": bson.M{"$gte": septime}}
@@ -508,7 +706,8 @@ func FindRouterSwapResultsToReplace(chainID string, septime int64) ([]*MgoSwapRe
 	return result, nil
 }
 
-func getStatusesFromStr(status string) (registerStatuses, resultStatuses []SwapStatus) {
+// GetStatusesFromStr get register and result statuses from comma separated string
+func GetStatusesFromStr(status string) (registerStatuses, resultStatuses []SwapStatus) {
 	parts := strings.Split(status, ",")
 	registerStatuses = make([]SwapStatus, 0, 5)
 	resultStatuses = make([]SwapStatus, 0, 5)
@@ -529,7 +728,7 @@ func getStatusesFromStr(status string) (registerStatuses, resultStatuses []SwapS
 	return registerStatuses, resultStatuses
 }
 
-// FindRouterSwapResults find router swap results with chainid and address
+// FindRouterSwapResults find router swap results with chainid and address (include archived ones)
 //nolint:gocyclo // allow long method
 func FindRouterSwapResults(fromChainID, address string, offset, limit int, status string) ([]*MgoSwapResult, error) {
 	var queries []bson.M
@@ -543,7 +742,7 @@ func FindRouterSwapResults(fromChainID, address string, offset, limit int, statu
 		queries = append(queries, bson.M{"fromChainID": fromChainID})
 	}
 
-	registerStatuses, resultStatuses := getStatusesFromStr(status)
+	registerStatuses, resultStatuses := GetStatusesFromStr(status)
 	filterStatuses, isInResultColl := resultStatuses, true
 	if len(resultStatuses) == 0 && len(registerStatuses) > 0 {
 		filterStatuses = registerStatuses
@@ -558,32 +757,88 @@ func FindRouterSwapResults(fromChainID, address string, offset, limit int, statu
 		}
 	}
 
-	opts := &options.FindOptions{}
-	if limit >= 0 {
-		opts = opts.SetSort(bson.D{{Key: "inittime", Value: 1}}).
-			SetSkip(int64(offset)).SetLimit(int64(limit))
-	} else {
-		opts = opts.SetSort(bson.D{{Key: "inittime", Value: -1}}).
-			SetSkip(int64(offset)).SetLimit(int64(-limit))
-	}
-
-	var coll *mongo.Collection
-	if isInResultColl {
-		coll = collRouterSwapResult
-	} else {
-		coll = collRouterSwap
-	}
-
-	var cur *mongo.Cursor
-	var err error
+	var query bson.M
 	switch len(queries) {
 	case 0:
-		cur, err = coll.Find(clientCtx, bson.M{}, opts)
+		query = bson.M{}
 	case 1:
-		cur, err = coll.Find(clientCtx, queries[0], opts)
+		query = queries[0]
 	default:
-		cur, err = coll.Find(clientCtx, bson.M{"$and": queries}, opts)
+		query = bson.M{"$and": queries}
+	}
+
+	coll, archiveColl := collRouterSwapResult, collRouterSwapResultArchive
+	if !isInResultColl {
+		coll, archiveColl = collRouterSwap, collRouterSwapArchive
+	}
+
+	sortOrder, count := 1, int64(limit)
+	if limit < 0 {
+		sortOrder, count = -1, int64(-limit)
+	}
+
+	// archived swaps may be newer than live ones by inittime (eg. restored ones),
+	// so get the first `offset+count` items of both and merge them in order.
+	total := int64(offset) + count
+	live, err := findSwapResultsInColl(coll, isInResultColl, query, sortOrder, total)
+	if err != nil {
+		return nil, err
 	}
+	archived, err := findSwapResultsInColl(archiveColl, isInResultColl, query, sortOrder, total)
+	if err != nil {
+		return nil, err
+	}
+	result := mergeSwapResults(live, archived, sortOrder)
+	if int64(len(result)) <= int64(offset) {
+		return []*MgoSwapResult{}, nil
+	}
+	result = result[offset:]
+	if int64(len(result)) > count {
+		result = result[:count]
+	}
+	return result, nil
+}
+
+// merge swap results which are sorted by (inittime, key) in `sortOrder`,
+// and skip the ones in `b` which are also in `a` (eg. archiving halfway).
+func mergeSwapResults(a, b []*MgoSwapResult, sortOrder int) []*MgoSwapResult {
+	keys := make(map[string]struct{}, len(a))
+	for _, res := range a {
+		keys[res.Key] = struct{}{}
+	}
+	dedup := make([]*MgoSwapResult, 0, len(b))
+	for _, res := range b {
+		if _, exist := keys[res.Key]; !exist {
+			dedup = append(dedup, res)
+		}
+	}
+	b = dedup
+
+	isBefore := func(x, y *MgoSwapResult) bool {
+		if x.InitTime != y.InitTime {
+			return (x.InitTime < y.InitTime) == (sortOrder > 0)
+		}
+		return (x.Key < y.Key) == (sortOrder > 0)
+	}
+	result := make([]*MgoSwapResult, 0, len(a)+len(b))
+	i, j := 0, 0
+	for i < len(a) && j < len(b) {
+		if isBefore(b[j], a[i]) {
+			result = append(result, b[j])
+			j++
+		} else {
+			result = append(result, a[i])
+			i++
+		}
+	}
+	result = append(result, a[i:]...)
+	return append(result, b[j:]...)
+}
+
+func findSwapResultsInColl(coll *mongo.Collection, isInResultColl bool, query bson.M, sortOrder int, limit int64) ([]*MgoSwapResult, error) {
+	opts := options.Find().SetSort(bson.D{{Key: "inittime", Value: sortOrder}, {Key: "_id", Value: sortOrder}}).
+		SetLimit(limit)
+	cur, err := coll.Find(clientCtx, query, opts)
 	if err != nil {
 		return nil, mgoError(err)
 	}
@@ -594,7 +849,7 @@ func FindRouterSwapResults(fromChainID, address string, offset, limit int, statu
 		swaps := make([]*MgoSwap, 0, 20)
 		err = cur.All(clientCtx, &swaps)
 		if err == nil {
-			result = convertToSwapResults(swaps)
+			result = ConvertToSwapResults(swaps)
 		}
 	}
 	if err != nil {
@@ -627,16 +882,24 @@ func UpdateRouterSwapResult(fromChainID, txid string, logindex int, items *SwapR
 	if items.SwapValue != "" {
 		updates["swapvalue"] = items.SwapValue
 	}
+	if items.SwapGasLimit != 0 {
+		updates["swapgaslimit"] = items.SwapGasLimit
+	}
+	if items.SwapGasUsed != 0 {
+		updates["swapgasused"] = items.SwapGasUsed
+	}
 	if items.Memo != "" {
 		updates["memo"] = items.Memo
 	} else if items.Status == MatchTxNotStable {
 		updates["memo"] = ""
 	}
+	var swapRes *MgoSwapResult
+	var err error
 	if items.SwapNonce != 0 || items.Status == MatchTxNotStable {
 		updateResultLock.Lock()
 		defer updateResultLock.Unlock()
 
-		err := checkRouterSwapResultUpdate(fromChainID, txid, logindex, items.SwapNonce)
+		swapRes, err = checkRouterSwapResultUpdate(fromChainID, txid, logindex, items.SwapNonce)
 		if err != nil {
 			return err
 		}
@@ -644,31 +907,50 @@ func UpdateRouterSwapResult(fromChainID, txid string, logindex int, items *SwapR
 			updates["swapnonce"] = items.SwapNonce
 		}
 	}
-	_, err := collRouterSwapResult.UpdateByID(clientCtx, key, bson.M{"$set": updates})
+	if swapRes == nil {
+		swapRes, err = FindRouterSwapResult(fromChainID, txid, logindex)
+		if err != nil {
+			return err
+		}
+	}
+	// the version filter ensures swaptx is not changed after this check
+	if items.ExpectedSwapTx != nil && !strings.EqualFold(swapRes.SwapTx, *items.ExpectedSwapTx) {
+		log.Warn("forbid update swap result as swap tx is modified", "chainid", fromChainID, "txid", txid, "logindex", logindex, "expected", *items.ExpectedSwapTx, "current", swapRes.SwapTx)
+		return ErrSwapTxModified
+	}
+	if items.Status == KeepStatus {
+		err = updateWithVersion(collRouterSwapResult, key, swapRes.Version, bson.M{"$set": updates})
+	} else {
+		err = CheckSwapResultStatusTransition(swapRes.Status, items.Status)
+		if err != nil {
+			return err
+		}
+		err = updateStatusWithVersion(collRouterSwapResult, key, swapRes.Version, swapRes.Status, items.Status, updates, items.Actor, items.Memo, items.Timestamp)
+	}
 	if err == nil {
 		log.Info("mongodb update router swap result success", "chainid", fromChainID, "txid", txid, "logindex", logindex, "updates", updates)
 	} else {
 		log.Error("mongodb update router swap result failed", "chainid", fromChainID, "txid", txid, "logindex", logindex, "updates", updates, "err", err)
 	}
-	return mgoError(err)
+	return err
 }
 
-func checkRouterSwapResultUpdate(fromChainID, txid string, logindex int, swapnonce uint64) error {
+func checkRouterSwapResultUpdate(fromChainID, txid string, logindex int, swapnonce uint64) (*MgoSwapResult, error) {
 	swapRes, err := FindRouterSwapResult(fromChainID, txid, logindex)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
 	if swapRes.SwapNonce != 0 {
 		log.Error("forbid update swap nonce again", "old", swapRes.SwapNonce, "new", swapnonce)
-		return ErrForbidUpdateNonce
+		return nil, ErrForbidUpdateNonce
 	}
 
 	if swapRes.SwapTx != "" {
 		log.Error("forbid update swap tx again", "old", swapRes.SwapTx)
-		return ErrForbidUpdateSwapTx
+		return nil, ErrForbidUpdateSwapTx
 	}
-	return nil
+	return swapRes, nil
 }
 
 // AddUsedRValue add used r, if error mean already exist
@@ -701,92 +983,109 @@ func AddUsedRValue(pubkey, r string) error {
 	}
 }
 
-// ----------------------------- admin functions -------------------------------------
-
-// RouterAdminPassBigValue pass big value
-func RouterAdminPassBigValue(fromChainID, txid string, logIndex int) error {
-	swap, err := FindRouterSwap(fromChainID, txid, logIndex)
-	if err != nil {
-		return err
-	}
-	if swap.Status != TxWithBigValue {
-		return fmt.Errorf("swap status is %v, not big value status %v", swap.Status.String(), TxWithBigValue.String())
+// AddConfigSnapshot add config snapshot
+func AddConfigSnapshot(cs *MgoConfigSnapshot) error {
+	if cs.Key == "" {
+		cs.Key = fmt.Sprintf("%d", time.Now().UnixNano())
 	}
-
-	_, err = FindRouterSwapResult(fromChainID, txid, logIndex)
+	_, err := collConfigSnapshot.InsertOne(clientCtx, cs)
 	if err == nil {
-		return fmt.Errorf("can not pass big value swap with result exist")
+		log.Info("mongodb add config snapshot success", "key", cs.Key, "timestamp", cs.Timestamp)
+	} else {
+		log.Warn("mongodb add config snapshot failed", "key", cs.Key, "timestamp", cs.Timestamp, "err", err)
 	}
-	return UpdateRouterSwapStatus(fromChainID, txid, logIndex, TxNotSwapped, time.Now().Unix(), "")
+	return mgoError(err)
 }
 
-// RouterAdminReswap reswap
-func RouterAdminReswap(fromChainID, txid string, logIndex int) error {
-	swap, err := FindRouterSwap(fromChainID, txid, logIndex)
-	if err != nil {
-		return err
+// FindLatestConfigSnapshots find latest config snapshots
+func FindLatestConfigSnapshots(limit int64) ([]*MgoConfigSnapshot, error) {
+	if limit <= 0 || limit > maxCountOfResults {
+		limit = maxCountOfResults
 	}
-	if swap.Status != TxProcessed {
-		return fmt.Errorf("swap status is %v, can not reswap", swap.Status.String())
+	opts := &options.FindOptions{
+		Sort:  bson.D{{Key: "timestamp", Value: -1}},
+		Limit: &limit,
 	}
-
-	res, err := FindRouterSwapResult(fromChainID, txid, logIndex)
+	cur, err := collConfigSnapshot.Find(clientCtx, bson.M{}, opts)
 	if err != nil {
-		return err
+		return nil, mgoError(err)
 	}
-	if res.Status != MatchTxFailed {
-		return fmt.Errorf("swap result status is %v, can not reswap", res.Status.String())
+	result := make([]*MgoConfigSnapshot, 0, 10)
+	err = cur.All(clientCtx, &result)
+	if err != nil {
+		return nil, mgoError(err)
 	}
+	return result, nil
+}
 
-	if res.SwapTx == "" {
-		return errors.New("swap without swaptx")
+// FindConfigSnapshot find config snapshot by key
+func FindConfigSnapshot(key string) (*MgoConfigSnapshot, error) {
+	result := &MgoConfigSnapshot{}
+	err := collConfigSnapshot.FindOne(clientCtx, bson.M{"_id": key}).Decode(result)
+	if err != nil {
+		return nil, mgoError(err)
 	}
+	return result, nil
+}
 
-	resBridge := router.GetBridgeByChainID(swap.ToChainID)
-	if resBridge == nil {
-		return tokens.ErrNoBridgeForChainID
+// AddNonceGapFill add nonce gap fill record
+func AddNonceGapFill(fill *MgoNonceGapFill) error {
+	fill.MPC = strings.ToLower(fill.MPC)
+	fill.Key = fmt.Sprintf("%v:%v:%v", fill.ChainID, fill.MPC, fill.Nonce)
+	opts := options.Replace().SetUpsert(true)
+	_, err := collNonceGapFill.ReplaceOne(clientCtx, bson.M{"_id": fill.Key}, fill, opts)
+	if err == nil {
+		log.Info("mongodb add nonce gap fill success", "key", fill.Key, "txhash", fill.TxHash)
+	} else {
+		log.Warn("mongodb add nonce gap fill failed", "key", fill.Key, "txhash", fill.TxHash, "err", err)
 	}
+	return mgoError(err)
+}
 
-	txStatus, txHash := getSwapResultsTxStatus(resBridge, res)
-	if txStatus != nil && txStatus.BlockHeight > 0 && !txStatus.IsSwapTxOnChainAndFailed() {
-		_ = UpdateRouterSwapResultStatus(fromChainID, txid, logIndex, MatchTxNotStable, time.Now().Unix(), "")
-		return fmt.Errorf("swap succeed with swaptx %v", txHash)
+// FindNonceGapFills find latest nonce gap fill records of mpc
+func FindNonceGapFills(chainID, mpc string, limit int64) ([]*MgoNonceGapFill, error) {
+	if limit <= 0 || limit > maxCountOfResults {
+		limit = maxCountOfResults
 	}
-
-	nonceSetter, ok := resBridge.(tokens.NonceSetter)
-	if ok {
-		mpcAddress := res.MPC
-		nonce, errf := nonceSetter.GetPoolNonce(mpcAddress, "latest")
-		if errf != nil {
-			log.Warn("get router mpc nonce failed", "address", mpcAddress)
-			return errf
-		}
-		if nonce <= res.SwapNonce {
-			return errors.New("can not retry swap with lower nonce")
-		}
+	query := bson.M{"chainID": chainID, "mpc": strings.ToLower(mpc)}
+	opts := &options.FindOptions{
+		Sort:  bson.D{{Key: "nonce", Value: -1}},
+		Limit: &limit,
 	}
-
-	log.Info("[reswap] update status to TxNotSwapped", "chainid", fromChainID, "txid", txid, "logIndex", logIndex, "swaptx", res.SwapTx)
-
-	err = UpdateRouterSwapResultStatus(fromChainID, txid, logIndex, Reswapping, time.Now().Unix(), "")
+	cur, err := collNonceGapFill.Find(clientCtx, query, opts)
 	if err != nil {
-		return err
+		return nil, mgoError(err)
 	}
+	result := make([]*MgoNonceGapFill, 0, 10)
+	err = cur.All(clientCtx, &result)
+	if err != nil {
+		return nil, mgoError(err)
+	}
+	return result, nil
+}
 
-	return UpdateRouterSwapStatus(fromChainID, txid, logIndex, TxNotSwapped, time.Now().Unix(), "")
+// UpdateSignGroupStat add or update mpc sign group statistics
+func UpdateSignGroupStat(stat *MgoSignGroupStat) error {
+	opts := options.Replace().SetUpsert(true)
+	_, err := collSignGroupStat.ReplaceOne(clientCtx, bson.M{"_id": stat.Key}, stat, opts)
+	if err != nil {
+		log.Warn("mongodb update sign group stat failed", "key", stat.Key, "err", err)
+	}
+	return mgoError(err)
 }
 
-func getSwapResultsTxStatus(bridge tokens.IBridge, res *MgoSwapResult) (status *tokens.TxStatus, txHash string) {
-	var err error
-	if status, err = bridge.GetTransactionStatus(res.SwapTx); err == nil {
-		return status, res.SwapTx
+// FindSignGroupStats find mpc sign group statistics
+func FindSignGroupStats(isFastMPC bool) ([]*MgoSignGroupStat, error) {
+	cur, err := collSignGroupStat.Find(clientCtx, bson.M{"fastmpc": isFastMPC})
+	if err != nil {
+		return nil, mgoError(err)
 	}
-	for _, tx := range res.OldSwapTxs {
-		if status, err = bridge.GetTransactionStatus(tx); err == nil {
-			return status, tx
-		}
+	result := make([]*MgoSignGroupStat, 0, 10)
+	err = cur.All(clientCtx, &result)
+	if err != nil {
+		return nil, mgoError(err)
 	}
-	return nil, ""
+	return result, nil
 }
 
 var defaultGetStatusInfoRegisterFilter = []SwapStatus{
@@ -800,13 +1099,19 @@ var defaultGetStatusInfoResultFilter = []SwapStatus{
 	MatchTxFailed,    // 14
 }
 
-// GetStatusInfo get status info
-func GetStatusInfo(statuses string) (statusInfo map[string]interface{}, err error) {
-	registerStatuses, resultStatuses := getStatusesFromStr(statuses)
+// GetStatusInfoFilters get statuses to count in status info (use default filters if empty)
+func GetStatusInfoFilters(statuses string) (registerStatuses, resultStatuses []SwapStatus) {
+	registerStatuses, resultStatuses = GetStatusesFromStr(statuses)
 	if len(registerStatuses) == 0 && len(resultStatuses) == 0 {
 		registerStatuses = defaultGetStatusInfoRegisterFilter
 		resultStatuses = defaultGetStatusInfoResultFilter
 	}
+	return registerStatuses, resultStatuses
+}
+
+// GetStatusInfo get status info
+func GetStatusInfo(statuses string) (statusInfo map[string]interface{}, err error) {
+	registerStatuses, resultStatuses := GetStatusInfoFilters(statuses)
 
 	var registerInfo, resusltInfo []bson.M
 
@@ -856,21 +1161,3 @@ func getStatusInfo(coll *mongo.Collection, filterStatuses []SwapStatus) (result
 
 	return result, nil
 }
-
-// ----------------------------- helper functions -------------------------------------
-
-// GetRegisteredRouterSwap get registered router swap
-func GetRegisteredRouterSwap(fromChainID, txid string, logIndex int) (oldSwap *MgoSwap, registeredOk bool) {
-	oldSwap, err := FindRouterSwap(fromChainID, txid, logIndex)
-	if err != nil || oldSwap == nil {
-		return nil, false
-	}
-	if oldSwap.Status.IsRegisteredOk() {
-		return oldSwap, true
-	}
-	oldSwapRes, err := FindRouterSwapResult(fromChainID, txid, logIndex)
-	if err == nil && oldSwapRes != nil {
-		return oldSwap, true
-	}
-	return oldSwap, false
-}
@@ -0,0 +1,39 @@
+package mongodb
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestMergeSwapResults(t *testing.T) {
+	newResult := func(key string, initTime int64) *MgoSwapResult {
+		return &MgoSwapResult{Key: key, InitTime: initTime}
+	}
+	getKeys := func(results []*MgoSwapResult) string {
+		keys := make([]string, len(results))
+		for i, res := range results {
+			keys[i] = res.Key
+		}
+		return strings.Join(keys, ",")
+	}
+
+	live := []*MgoSwapResult{newResult("a", 1), newResult("c", 3), newResult("e", 3)}
+	archived := []*MgoSwapResult{newResult("b", 2), newResult("d", 3), newResult("f", 5)}
+	if keys := getKeys(mergeSwapResults(live, archived, 1)); keys != "a,b,c,d,e,f" {
+		t.Errorf("wrong ascending merge result %v", keys)
+	}
+
+	live = []*MgoSwapResult{newResult("e", 3), newResult("c", 3), newResult("a", 1)}
+	archived = []*MgoSwapResult{newResult("f", 5), newResult("d", 3), newResult("b", 2)}
+	if keys := getKeys(mergeSwapResults(live, archived, -1)); keys != "f,e,d,c,b,a" {
+		t.Errorf("wrong descending merge result %v", keys)
+	}
+
+	// the live copy is kept if both exist
+	live = []*MgoSwapResult{newResult("a", 1), newResult("c", 3)}
+	archived = []*MgoSwapResult{newResult("b", 2), {Key: "c", InitTime: 3, Memo: "archived"}}
+	merged := mergeSwapResults(live, archived, 1)
+	if keys := getKeys(merged); keys != "a,b,c" || merged[2].Memo != "" {
+		t.Errorf("wrong merge result with duplicate %v", keys)
+	}
+}
@@ -0,0 +1,234 @@
+package mongodb
+
+import (
+	"errors"
+
+	"github.com/anyswap/CrossChain-Router/v3/log"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// swaps in these statuses will never change, and can be archived
+var (
+	archivedSwapResultStatuses = []SwapStatus{MatchTxStable}
+	archivedSwapStatuses       = []SwapStatus{ManualMakeFail}
+
+	errNotArchivable = errors.New("swap is not archivable")
+)
+
+// ArchiveRouterSwaps move swaps and swap results in terminal statuses
+// whose last update time is before `before` to archive collections.
+func ArchiveRouterSwaps(before, limit int64) (count int, err error) {
+	archived := make(map[string]struct{})
+	for _, coll := range []*mongo.Collection{collRouterSwapResult, collRouterSwap} {
+		statuses := archivedSwapResultStatuses
+		if coll == collRouterSwap {
+			statuses = archivedSwapStatuses
+		}
+		query := bson.M{
+			"status":    bson.M{"$in": statuses},
+			"timestamp": bson.M{"$lt": before},
+			"restored":  bson.M{"$ne": true},
+		}
+		// page by key, so the not archivable ones do not block the others
+		for int64(count) < limit {
+			keys, errf := findKeys(coll, query, limit)
+			if errf != nil {
+				return count, errf
+			}
+			if len(keys) == 0 {
+				break
+			}
+			query["_id"] = bson.M{"$gt": keys[len(keys)-1]}
+			for _, key := range keys {
+				if _, exist := archived[key]; exist {
+					continue
+				}
+				archived[key] = struct{}{}
+				err = archiveRouterSwap(key)
+				if errors.Is(err, ErrItemNotFound) || errors.Is(err, errNotArchivable) ||
+					errors.Is(err, ErrVersionConflict) {
+					continue
+				}
+				if err != nil {
+					log.Warn("archive router swap failed", "key", key, "err", err)
+					return count, err
+				}
+				count++
+			}
+		}
+	}
+	return count, nil
+}
+
+// RestoreRouterSwap move swap and its swap result from archive collections back,
+// and mark them restored to not be archived again.
+func RestoreRouterSwap(fromChainID, txid string, logindex int) error {
+	key := GetRouterSwapKey(fromChainID, txid, logindex)
+	swap, res, err := findSwapAndResult(collRouterSwapArchive, collRouterSwapResultArchive, key)
+	if err != nil {
+		return err
+	}
+	markRestored(swap, res)
+
+	// copy first then delete, so rerun can recover the halfway failure
+	if res != nil {
+		if err = insertIgnoreDup(collRouterSwapResult, res); err != nil {
+			return err
+		}
+	}
+	if swap != nil {
+		if err = insertIgnoreDup(collRouterSwap, swap); err != nil {
+			return err
+		}
+	}
+	if _, err = collRouterSwapResultArchive.DeleteOne(clientCtx, bson.M{"_id": key}); err != nil {
+		return mgoError(err)
+	}
+	if _, err = collRouterSwapArchive.DeleteOne(clientCtx, bson.M{"_id": key}); err != nil {
+		return mgoError(err)
+	}
+	log.Info("restore router swap success", "chainid", fromChainID, "txid", txid, "logindex", logindex, "hasSwap", swap != nil, "hasResult", res != nil)
+	return nil
+}
+
+func archiveRouterSwap(key string) error {
+	swap, res, err := findSwapAndResult(collRouterSwap, collRouterSwapResult, key)
+	if err != nil {
+		return err
+	}
+	if !isArchivable(swap, res) {
+		return errNotArchivable
+	}
+
+	// copy first then delete, so rerun can recover the halfway failure
+	opts := options.Replace().SetUpsert(true)
+	if swap != nil {
+		if _, err = collRouterSwapArchive.ReplaceOne(clientCtx, bson.M{"_id": key}, swap, opts); err != nil {
+			return mgoError(err)
+		}
+	}
+	if res != nil {
+		if _, err = collRouterSwapResultArchive.ReplaceOne(clientCtx, bson.M{"_id": key}, res, opts); err != nil {
+			return mgoError(err)
+		}
+	}
+	// delete with version to not lose concurrent modifications,
+	// and delete the stale archived copy if modified concurrently.
+	if res != nil {
+		if err = deleteArchivedWithVersion(collRouterSwapResult, collRouterSwapResultArchive, key, res.Version); err != nil {
+			return err
+		}
+	}
+	if swap != nil {
+		if err = deleteArchivedWithVersion(collRouterSwap, collRouterSwapArchive, key, swap.Version); err != nil {
+			return err
+		}
+	}
+	log.Debug("archive router swap success", "key", key, "hasSwap", swap != nil, "hasResult", res != nil)
+	return nil
+}
+
+func deleteArchivedWithVersion(coll, archiveColl *mongo.Collection, key string, version uint64) error {
+	deleted, err := coll.DeleteOne(clientCtx, getVersionFilter(key, version))
+	if err != nil {
+		return mgoError(err)
+	}
+	if deleted.DeletedCount > 0 {
+		return nil
+	}
+	if _, err = archiveColl.DeleteOne(clientCtx, bson.M{"_id": key}); err != nil {
+		return mgoError(err)
+	}
+	log.Info("archive router swap conflict", "key", key, "version", version)
+	return ErrVersionConflict
+}
+
+func markRestored(swap *MgoSwap, res *MgoSwapResult) {
+	if swap != nil {
+		swap.Restored = true
+	}
+	if res != nil {
+		res.Restored = true
+	}
+}
+
+// the swap result may be still processing even if the swap is in terminal status
+func isArchivable(swap *MgoSwap, res *MgoSwapResult) bool {
+	if (swap != nil && swap.Restored) || (res != nil && res.Restored) {
+		return false
+	}
+	if res != nil && containsStatus(archivedSwapResultStatuses, res.Status) {
+		return true
+	}
+	if swap == nil || !containsStatus(archivedSwapStatuses, swap.Status) {
+		return false
+	}
+	return res == nil || (res.Status != MatchTxNotStable && res.Status != Reswapping)
+}
+
+func containsStatus(statuses []SwapStatus, status SwapStatus) bool {
+	for _, s := range statuses {
+		if s == status {
+			return true
+		}
+	}
+	return false
+}
+
+// find swap and swap result of `key`, returns ErrItemNotFound if both not exist
+func findSwapAndResult(swapColl, resColl *mongo.Collection, key string) (swap *MgoSwap, res *MgoSwapResult, err error) {
+	swap = &MgoSwap{}
+	err = swapColl.FindOne(clientCtx, bson.M{"_id": key}).Decode(swap)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		swap = nil
+	} else if err != nil {
+		return nil, nil, mgoError(err)
+	}
+	res = &MgoSwapResult{}
+	err = resColl.FindOne(clientCtx, bson.M{"_id": key}).Decode(res)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		res = nil
+	} else if err != nil {
+		return nil, nil, mgoError(err)
+	}
+	if swap == nil && res == nil {
+		return nil, nil, ErrItemNotFound
+	}
+	return swap, res, nil
+}
+
+// find keys in ascending order
+func findKeys(coll *mongo.Collection, query bson.M, limit int64) ([]string, error) {
+	opts := options.Find().SetProjection(bson.M{"_id": 1}).SetSort(bson.M{"_id": 1}).SetLimit(limit)
+	cur, err := coll.Find(clientCtx, query, opts)
+	if err != nil {
+		return nil, mgoError(err)
+	}
+	var docs []struct {
+		Key string `bson:"_id"`
+	}
+	if err = cur.All(clientCtx, &docs); err != nil {
+		return nil, mgoError(err)
+	}
+	keys := make([]string, len(docs))
+	for i, doc := range docs {
+		keys[i] = doc.Key
+	}
+	return keys, nil
+}
+
+func insertIgnoreDup(coll *mongo.Collection, doc interface{}) error {
+	_, err := coll.InsertOne(clientCtx, doc)
+	if err != nil && !mongo.IsDuplicateKeyError(err) {
+		return mgoError(err)
+	}
+	return nil
+}
+
+func isArchived(coll *mongo.Collection, key string) bool {
+	count, err := coll.CountDocuments(clientCtx, bson.M{"_id": key}, options.Count().SetLimit(1))
+	return err == nil && count > 0
+}
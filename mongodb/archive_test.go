@@ -0,0 +1,93 @@
+package mongodb
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+func TestIsArchivable(t *testing.T) {
+	tests := []struct {
+		swap *MgoSwap
+		res  *MgoSwapResult
+		want bool
+	}{
+		{nil, &MgoSwapResult{Status: MatchTxStable}, true},
+		{&MgoSwap{Status: TxProcessed}, &MgoSwapResult{Status: MatchTxStable}, true},
+		{&MgoSwap{Status: ManualMakeFail}, nil, true},
+		{&MgoSwap{Status: ManualMakeFail}, &MgoSwapResult{Status: MatchTxNotStable}, false},
+		{&MgoSwap{Status: TxProcessed}, &MgoSwapResult{Status: MatchTxNotStable}, false},
+		{&MgoSwap{Status: TxProcessed, Restored: true}, &MgoSwapResult{Status: MatchTxStable, Restored: true}, false},
+		{&MgoSwap{Status: ManualMakeFail, Restored: true}, nil, false},
+	}
+	for i, test := range tests {
+		if have := isArchivable(test.swap, test.res); have != test.want {
+			t.Errorf("test %v: archivable mismatch, have %v want %v", i, have, test.want)
+		}
+	}
+}
+
+// set env ROUTER_TEST_MONGODB_URLS (comma separated), ROUTER_TEST_MONGODB_NAME,
+// ROUTER_TEST_MONGODB_USER and ROUTER_TEST_MONGODB_PASS to run it
+func TestRestoreThenArchive(t *testing.T) {
+	urls := os.Getenv("ROUTER_TEST_MONGODB_URLS")
+	if urls == "" {
+		t.Skip("ROUTER_TEST_MONGODB_URLS is not set")
+	}
+	MongoServerInit(
+		"archive-test",
+		strings.Split(urls, ","),
+		os.Getenv("ROUTER_TEST_MONGODB_NAME"),
+		os.Getenv("ROUTER_TEST_MONGODB_USER"),
+		os.Getenv("ROUTER_TEST_MONGODB_PASS"),
+	)
+
+	fromChainID, logIndex := "1", 1
+	txid := fmt.Sprintf("0x%064x", time.Now().UnixNano())
+	key := GetRouterSwapKey(fromChainID, txid, logIndex)
+	defer func() {
+		_, _ = collRouterSwap.DeleteOne(clientCtx, bson.M{"_id": key})
+		_, _ = collRouterSwapResult.DeleteOne(clientCtx, bson.M{"_id": key})
+		_, _ = collRouterSwapArchive.DeleteOne(clientCtx, bson.M{"_id": key})
+		_, _ = collRouterSwapResultArchive.DeleteOne(clientCtx, bson.M{"_id": key})
+	}()
+
+	oldTime := time.Now().Unix() - 100*24*3600
+	err := AddRouterSwap(&MgoSwap{FromChainID: fromChainID, TxID: txid, LogIndex: logIndex, Status: TxProcessed, Timestamp: oldTime})
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = AddRouterSwapResult(&MgoSwapResult{FromChainID: fromChainID, TxID: txid, LogIndex: logIndex, Status: MatchTxStable, Timestamp: oldTime})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	before := time.Now().Unix() - 90*24*3600
+	if _, err = ArchiveRouterSwaps(before, 1<<30); err != nil {
+		t.Fatal(err)
+	}
+	if !isArchived(collRouterSwapResultArchive, key) || !isArchived(collRouterSwapArchive, key) {
+		t.Fatal("swap is not archived")
+	}
+
+	if err = RestoreRouterSwap(fromChainID, txid, logIndex); err != nil {
+		t.Fatal(err)
+	}
+	if _, err = ArchiveRouterSwaps(before, 1<<30); err != nil {
+		t.Fatal(err)
+	}
+	if isArchived(collRouterSwapResultArchive, key) || isArchived(collRouterSwapArchive, key) {
+		t.Fatal("restored swap is archived again")
+	}
+	res, err := FindRouterSwapResult(fromChainID, txid, logIndex)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !res.Restored {
+		t.Fatal("restored swap result is not marked")
+	}
+}
@@ -9,7 +9,8 @@ import (
 	"github.com/anyswap/CrossChain-Router/v3/tokens"
 )
 
-func convertToSwapResults(swaps []*MgoSwap) []*MgoSwapResult {
+// ConvertToSwapResults convert swaps to swap results
+func ConvertToSwapResults(swaps []*MgoSwap) []*MgoSwapResult {
 	result := make([]*MgoSwapResult, len(swaps))
 	for i, swap := range swaps {
 		result[i] = swap.ToSwapResult()
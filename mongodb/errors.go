@@ -35,4 +35,8 @@ var (
 	ErrWrongKey           = newError(-32012, "mgoError: Wrong key")
 	ErrForbidUpdateNonce  = newError(-32013, "mgoError: Forbid update swap nonce")
 	ErrForbidUpdateSwapTx = newError(-32014, "mgoError: Forbid update swap tx")
+
+	ErrIllegalStatusTransition = newError(-32015, "mgoError: Illegal status transition")
+	ErrVersionConflict         = newError(-32016, "mgoError: Item is modified concurrently")
+	ErrSwapTxModified          = newError(-32017, "mgoError: Swap tx is modified by others")
 )
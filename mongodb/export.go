@@ -0,0 +1,126 @@
+package mongodb
+
+import (
+	"sort"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// ExportFilter filter of swap results to export, sorted by inittime and key
+type ExportFilter struct {
+	StartTime   int64 // inittime in milliseconds (inclusive)
+	EndTime     int64 // inittime in milliseconds (exclusive), 0 means no end
+	TokenID     string
+	FromChainID string
+	ToChainID   string
+	Statuses    []SwapStatus
+
+	// cursor, only export swap results after it
+	AfterInitTime int64
+	AfterKey      string
+}
+
+// Match is swap result matching the filter
+func (f *ExportFilter) Match(res *MgoSwapResult) bool {
+	if res.InitTime < f.StartTime || (f.EndTime > 0 && res.InitTime >= f.EndTime) {
+		return false
+	}
+	if f.TokenID != "" && (res.ERC20SwapInfo == nil || res.ERC20SwapInfo.TokenID != f.TokenID) {
+		return false
+	}
+	if f.FromChainID != "" && res.FromChainID != f.FromChainID {
+		return false
+	}
+	if f.ToChainID != "" && res.ToChainID != f.ToChainID {
+		return false
+	}
+	if len(f.Statuses) > 0 && !containsStatus(f.Statuses, res.Status) {
+		return false
+	}
+	if f.AfterKey != "" && !isAfterCursor(f.AfterInitTime, f.AfterKey, res) {
+		return false
+	}
+	return true
+}
+
+func isAfterCursor(initTime int64, key string, res *MgoSwapResult) bool {
+	return res.InitTime > initTime || (res.InitTime == initTime && res.Key > key)
+}
+
+// SortExportedSwapResults sort swap results in export order
+func SortExportedSwapResults(results []*MgoSwapResult) {
+	sort.SliceStable(results, func(i, j int) bool {
+		return isAfterCursor(results[i].InitTime, results[i].Key, results[j])
+	})
+}
+
+func (f *ExportFilter) toQuery() bson.M {
+	qtime := bson.M{"$gte": f.StartTime}
+	if f.EndTime > 0 {
+		qtime["$lt"] = f.EndTime
+	}
+	queries := []bson.M{{"inittime": qtime}}
+	if f.TokenID != "" {
+		queries = append(queries, bson.M{"swapinfo.routerSwapInfo.tokenID": f.TokenID})
+	}
+	if f.FromChainID != "" {
+		queries = append(queries, bson.M{"fromChainID": f.FromChainID})
+	}
+	if f.ToChainID != "" {
+		queries = append(queries, bson.M{"toChainID": f.ToChainID})
+	}
+	if len(f.Statuses) > 0 {
+		queries = append(queries, bson.M{"status": bson.M{"$in": f.Statuses}})
+	}
+	if f.AfterKey != "" {
+		queries = append(queries, bson.M{"$or": []bson.M{
+			{"inittime": bson.M{"$gt": f.AfterInitTime}},
+			{"inittime": f.AfterInitTime, "_id": bson.M{"$gt": f.AfterKey}},
+		}})
+	}
+	return bson.M{"$and": queries}
+}
+
+// FindRouterSwapResultsToExport find swap results to export (include archived ones)
+func FindRouterSwapResultsToExport(filter *ExportFilter, limit int64) ([]*MgoSwapResult, error) {
+	query := filter.toQuery()
+	opts := options.Find().
+		SetSort(bson.D{{Key: "inittime", Value: 1}, {Key: "_id", Value: 1}}).
+		SetLimit(limit)
+
+	// merge the first `limit` ones of both collections
+	var result []*MgoSwapResult
+	for _, coll := range []*mongo.Collection{collRouterSwapResult, collRouterSwapResultArchive} {
+		cur, err := coll.Find(clientCtx, query, opts)
+		if err != nil {
+			return nil, mgoError(err)
+		}
+		items := make([]*MgoSwapResult, 0, limit)
+		err = cur.All(clientCtx, &items)
+		if err != nil {
+			return nil, mgoError(err)
+		}
+		result = append(result, items...)
+	}
+	result = uniqueSwapResults(result) // the live one is preferred
+	SortExportedSwapResults(result)
+	if int64(len(result)) > limit {
+		result = result[:limit]
+	}
+	return result, nil
+}
+
+func uniqueSwapResults(results []*MgoSwapResult) []*MgoSwapResult {
+	exist := make(map[string]struct{}, len(results))
+	unique := results[:0]
+	for _, res := range results {
+		if _, ok := exist[res.Key]; ok {
+			continue
+		}
+		exist[res.Key] = struct{}{}
+		unique = append(unique, res)
+	}
+	return unique
+}
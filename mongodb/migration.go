@@ -0,0 +1,244 @@
+package mongodb
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/anyswap/CrossChain-Router/v3/log"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// Migration numbered schema migration, applied in ascending order of version.
+// `Migrate` must be idempotent, as it is rerun if failed halfway.
+type Migration struct {
+	Version     int
+	Description string
+	Migrate     func(m *Migrator) error
+}
+
+// MigrationStatus migration with its applied info
+type MigrationStatus struct {
+	Version     int    `json:"version"`
+	Description string `json:"description"`
+	Applied     bool   `json:"applied"`
+	Timestamp   int64  `json:"timestamp,omitempty"` // applied time
+}
+
+// Migrator execute steps of migration, only log the steps if dry run
+type Migrator struct {
+	DryRun  bool
+	version int
+}
+
+// append new migrations to the end, never modify or remove applied ones
+var migrations = []*Migration{
+	{
+		Version:     1,
+		Description: "create initial indexes",
+		Migrate: func(m *Migrator) error {
+			return m.createIndexes([]*indexSpec{
+				{collRouterSwap, []string{"inittime", "status", "fromChainID"}},
+				{collRouterSwap, []string{"txid"}},
+				{collRouterSwapResult, []string{"inittime", "status", "fromChainID"}},
+				{collRouterSwapResult, []string{"txid"}},
+				{collRouterSwapResult, []string{"from", "fromChainID"}},
+				{collRouterSwapResult, []string{"toChainID", "swapnonce"}},
+				{collRouterSwapResult, []string{"timestamp"}},
+				{collConfigSnapshot, []string{"timestamp"}},
+				{collNonceGapFill, []string{"chainID", "mpc", "nonce"}},
+			})
+		},
+	},
+	{
+		Version:     2,
+		Description: "backfill version field of swaps and swap results",
+		Migrate: func(m *Migrator) error {
+			filter := bson.M{"version": bson.M{"$exists": false}}
+			update := bson.M{"$set": bson.M{"version": 0}}
+			if err := m.UpdateMany(collRouterSwap, filter, update); err != nil {
+				return err
+			}
+			return m.UpdateMany(collRouterSwapResult, filter, update)
+		},
+	},
+	{
+		Version:     3,
+		Description: "create indexes of archive collections",
+		Migrate: func(m *Migrator) error {
+			return m.createIndexes([]*indexSpec{
+				{collRouterSwapArchive, []string{"inittime", "status", "fromChainID"}},
+				{collRouterSwapArchive, []string{"txid"}},
+				{collRouterSwapResultArchive, []string{"inittime", "status", "fromChainID"}},
+				{collRouterSwapResultArchive, []string{"txid"}},
+				{collRouterSwapResultArchive, []string{"from", "fromChainID"}},
+				{collRouterSwapResultArchive, []string{"toChainID", "swapnonce"}},
+			})
+		},
+	},
+	{
+		Version:     4,
+		Description: "create indexes of swap txs for reverse lookup",
+		Migrate: func(m *Migrator) error {
+			return m.createIndexes([]*indexSpec{
+				{collRouterSwapResult, []string{"swaptx"}},
+				{collRouterSwapResult, []string{"oldswaptxs"}},
+				{collRouterSwapResultArchive, []string{"swaptx"}},
+				{collRouterSwapResultArchive, []string{"oldswaptxs"}},
+			})
+		},
+	},
+}
+
+// GetMigrationStatus get status of all migrations
+func GetMigrationStatus() ([]*MigrationStatus, error) {
+	applied, err := getAppliedMigrations()
+	if err != nil {
+		return nil, err
+	}
+	result := make([]*MigrationStatus, 0, len(migrations))
+	for _, mig := range migrations {
+		status := &MigrationStatus{
+			Version:     mig.Version,
+			Description: mig.Description,
+		}
+		if record, exist := applied[mig.Version]; exist {
+			status.Applied = true
+			status.Timestamp = record.Timestamp
+		}
+		result = append(result, status)
+	}
+	return result, nil
+}
+
+// GetPendingMigrations get migrations not applied yet
+func GetPendingMigrations() ([]*Migration, error) {
+	applied, err := getAppliedMigrations()
+	if err != nil {
+		return nil, err
+	}
+	var pending []*Migration
+	for _, mig := range migrations {
+		if _, exist := applied[mig.Version]; !exist {
+			pending = append(pending, mig)
+		}
+	}
+	return pending, nil
+}
+
+// RunMigrations apply pending migrations in order and record them.
+// if `dryRun` is true, only log the steps without modifying anything.
+func RunMigrations(dryRun bool) (applied []*Migration, err error) {
+	pending, err := GetPendingMigrations()
+	if err != nil {
+		return nil, err
+	}
+	for _, mig := range pending {
+		log.Info("[migrate] start migration", "version", mig.Version, "description", mig.Description, "dryRun", dryRun)
+		m := &Migrator{DryRun: dryRun, version: mig.Version}
+		if err = mig.Migrate(m); err != nil {
+			return applied, fmt.Errorf("migration %v failed: %w", mig.Version, err)
+		}
+		if !dryRun {
+			record := &MgoMigration{
+				Version:     mig.Version,
+				Description: mig.Description,
+				Timestamp:   time.Now().Unix(),
+			}
+			if _, err = collMigration.InsertOne(clientCtx, record); err != nil {
+				return applied, fmt.Errorf("record migration %v failed: %w", mig.Version, mgoError(err))
+			}
+		}
+		log.Info("[migrate] finish migration", "version", mig.Version, "dryRun", dryRun)
+		applied = append(applied, mig)
+	}
+	return applied, nil
+}
+
+func getAppliedMigrations() (map[int]*MgoMigration, error) {
+	cur, err := collMigration.Find(clientCtx, bson.M{})
+	if err != nil {
+		return nil, mgoError(err)
+	}
+	records := make([]*MgoMigration, 0, len(migrations))
+	err = cur.All(clientCtx, &records)
+	if err != nil {
+		return nil, mgoError(err)
+	}
+	applied := make(map[int]*MgoMigration, len(records))
+	for _, record := range records {
+		applied[record.Version] = record
+	}
+	return applied, nil
+}
+
+func getIndexKeysAndName(keys []string) (bson.D, string) {
+	indexKeys := make(bson.D, len(keys))
+	names := make([]string, len(keys))
+	for i, key := range keys {
+		indexKeys[i] = bson.E{Key: key, Value: 1}
+		names[i] = key + "_1"
+	}
+	return indexKeys, strings.Join(names, "_")
+}
+
+type indexSpec struct {
+	coll *mongo.Collection
+	keys []string
+}
+
+func (m *Migrator) createIndexes(indexes []*indexSpec) error {
+	for _, index := range indexes {
+		if err := m.CreateIndex(index.coll, index.keys...); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// CreateIndex create ascending index of `keys`, do nothing if exist
+func (m *Migrator) CreateIndex(coll *mongo.Collection, keys ...string) error {
+	indexKeys, name := getIndexKeysAndName(keys)
+	log.Info("[migrate] create index", "version", m.version, "collection", coll.Name(), "index", name, "dryRun", m.DryRun)
+	if m.DryRun {
+		return nil
+	}
+	_, err := coll.Indexes().CreateOne(clientCtx, mongo.IndexModel{Keys: indexKeys})
+	return mgoError(err)
+}
+
+// DropIndex drop ascending index of `keys`, do nothing if not exist
+func (m *Migrator) DropIndex(coll *mongo.Collection, keys ...string) error {
+	_, name := getIndexKeysAndName(keys)
+	log.Info("[migrate] drop index", "version", m.version, "collection", coll.Name(), "index", name, "dryRun", m.DryRun)
+	if m.DryRun {
+		return nil
+	}
+	_, err := coll.Indexes().DropOne(clientCtx, name)
+	var cmdErr mongo.CommandError
+	if errors.As(err, &cmdErr) && cmdErr.Name == "IndexNotFound" {
+		return nil
+	}
+	return mgoError(err)
+}
+
+// UpdateMany update documents matching `filter`, only count them if dry run
+func (m *Migrator) UpdateMany(coll *mongo.Collection, filter, update interface{}) error {
+	if m.DryRun {
+		count, err := coll.CountDocuments(clientCtx, filter)
+		if err != nil {
+			return mgoError(err)
+		}
+		log.Info("[migrate] update documents", "version", m.version, "collection", coll.Name(), "matched", count, "dryRun", m.DryRun)
+		return nil
+	}
+	res, err := coll.UpdateMany(clientCtx, filter, update)
+	if err != nil {
+		return mgoError(err)
+	}
+	log.Info("[migrate] update documents", "version", m.version, "collection", coll.Name(), "matched", res.MatchedCount, "modified", res.ModifiedCount)
+	return nil
+}
@@ -0,0 +1,14 @@
+package mongodb
+
+import "testing"
+
+func TestMigrationsOrder(t *testing.T) {
+	for i, mig := range migrations {
+		if mig.Version != i+1 {
+			t.Errorf("migration at index %v has version %v, want %v", i, mig.Version, i+1)
+		}
+		if mig.Description == "" || mig.Migrate == nil {
+			t.Errorf("migration %v has no description or migrate function", mig.Version)
+		}
+	}
+}
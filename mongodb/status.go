@@ -10,21 +10,30 @@ import (
 // -----------------------------------------------
 // swap status change graph
 // symbol '--->' mean transfer only under checked condition (eg. manual process)
+// the graph is enforced by `swapStatusTransitions` and `swapResultStatusTransitions`
 //
 // -----------------------------------------------
 // 1. swap register status change graph
 //
-// TxNotStable -> |- TxVerifyFailed    -> manual
-//                |- TxWithWrongValue  -> manual
-//                |- SwapInBlacklist   -> manual
-//                |- TxWithBigValue    ---> TxNotSwapped
-//                |- TxNotSwapped -> |- TxProcessed (->MatchTxNotStable)
+// TxNotStable -> |- TxVerifyFailed    ---> TxNotStable (re-register)
+//                |- TxWithWrongValue  ---> TxNotStable (re-register)
+//                |- TxWithWrongPath   ---> TxNotStable (re-register)
+//                |- MissTokenConfig   ---> TxNotStable (re-register)
+//                |- NoUnderlyingToken ---> TxNotStable (re-register)
+//                |- SwapInBlacklist   ---> TxNotStable (re-register)
+//                |- TxWithBigValue    ---> TxNotSwapped or TxNotStable (re-register)
+//                |- TxNotSwapped -> |- TxProcessed (->MatchTxNotStable) ---> TxNotSwapped (reswap)
+//                                   |- TxSimulateFailed ---> TxNotSwapped
+//                                   |- SwapInBlacklist
 // -----------------------------------------------
 // 2. swap result status change graph
 //
 // TxWithBigValue ---> MatchTxEmpty
 // MatchTxEmpty   -> | MatchTxNotStable -> |- MatchTxStable
-//                                         |- MatchTxFailed -> manual
+//                                         |- MatchTxFailed -> |- MatchTxNotStable
+//                                                             |- MatchTxStable
+//                                                             |- Reswapping ---> MatchTxEmpty or MatchTxNotStable
+// MatchTxEmpty, MatchTxNotStable, Reswapping -> TxNotStable (hold by reverify)
 // -----------------------------------------------
 
 // SwapStatus swap status
@@ -47,11 +56,63 @@ const (
 	TxWithWrongPath   SwapStatus = 19
 	MissTokenConfig   SwapStatus = 20
 	NoUnderlyingToken SwapStatus = 21
+	TxSimulateFailed  SwapStatus = 22
 
 	KeepStatus SwapStatus = 255
 	Reswapping SwapStatus = 256
 )
 
+var swapStatusTransitions = map[SwapStatus][]SwapStatus{
+	TxNotStable: {
+		TxVerifyFailed, TxWithWrongValue, TxWithWrongPath, MissTokenConfig, NoUnderlyingToken,
+		SwapInBlacklist, TxWithBigValue, TxNotSwapped, ManualMakeFail,
+	},
+	TxVerifyFailed:    reregisterStatuses,
+	TxWithWrongValue:  reregisterStatuses,
+	TxWithWrongPath:   reregisterStatuses,
+	MissTokenConfig:   reregisterStatuses,
+	NoUnderlyingToken: reregisterStatuses,
+	SwapInBlacklist:   reregisterStatuses,
+	TxWithBigValue:    append([]SwapStatus{TxNotSwapped, ManualMakeFail}, reregisterStatuses...),
+	TxNotSwapped:      {TxProcessed, TxSimulateFailed, SwapInBlacklist, ManualMakeFail},
+	TxSimulateFailed:  {TxNotSwapped, ManualMakeFail},
+	TxProcessed:       {TxNotSwapped},
+}
+
+var reregisterStatuses = []SwapStatus{
+	TxNotStable, TxVerifyFailed, TxWithWrongValue, TxWithWrongPath, MissTokenConfig, NoUnderlyingToken,
+}
+
+var swapResultStatusTransitions = map[SwapStatus][]SwapStatus{
+	TxWithBigValue:   {MatchTxEmpty},
+	MatchTxEmpty:     {MatchTxNotStable, TxNotStable, TxVerifyFailed},
+	MatchTxNotStable: {MatchTxStable, MatchTxFailed, TxNotStable},
+	MatchTxFailed:    {MatchTxNotStable, MatchTxStable, Reswapping},
+	Reswapping:       {MatchTxEmpty, MatchTxNotStable, TxNotStable},
+}
+
+func checkStatusTransition(transitions map[SwapStatus][]SwapStatus, from, to SwapStatus) error {
+	if from == to {
+		return nil
+	}
+	for _, status := range transitions[from] {
+		if status == to {
+			return nil
+		}
+	}
+	return fmt.Errorf("%w: from %v to %v", ErrIllegalStatusTransition, from.String(), to.String())
+}
+
+// CheckSwapStatusTransition check swap register status transition
+func CheckSwapStatusTransition(from, to SwapStatus) error {
+	return checkStatusTransition(swapStatusTransitions, from, to)
+}
+
+// CheckSwapResultStatusTransition check swap result status transition
+func CheckSwapResultStatusTransition(from, to SwapStatus) error {
+	return checkStatusTransition(swapResultStatusTransitions, from, to)
+}
+
 // IsResultStatus is swap result status
 func (status SwapStatus) IsResultStatus() bool {
 	switch status {
@@ -105,6 +166,8 @@ func (status SwapStatus) String() string {
 		return "MissTokenConfig"
 	case NoUnderlyingToken:
 		return "NoUnderlyingToken"
+	case TxSimulateFailed:
+		return "TxSimulateFailed"
 
 	case KeepStatus:
 		return "KeepStatus"
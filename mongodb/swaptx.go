@@ -0,0 +1,55 @@
+package mongodb
+
+import (
+	"errors"
+	"strings"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// swap txs of eth-like chains are saved in lower case,
+// and those of other chains may be case sensitive.
+func getSwapTxCandidates(swapTx string) []string {
+	if lower := strings.ToLower(swapTx); lower != swapTx {
+		return []string{swapTx, lower}
+	}
+	return []string{swapTx}
+}
+
+// MatchSwapTx check whether `swapTx` is the current or an old (replaced) swap tx of swap result
+func MatchSwapTx(res *MgoSwapResult, swapTx string) (matched, isCurrent bool) {
+	for _, tx := range getSwapTxCandidates(swapTx) {
+		if res.SwapTx == tx {
+			return true, true
+		}
+	}
+	for _, tx := range getSwapTxCandidates(swapTx) {
+		for _, oldTx := range res.OldSwapTxs {
+			if oldTx == tx {
+				return true, false
+			}
+		}
+	}
+	return false, false
+}
+
+// FindRouterSwapResultBySwapTx find router swap result by its current or old swap tx (include archived ones)
+func FindRouterSwapResultBySwapTx(swapTx string) (*MgoSwapResult, error) {
+	result, err := findRouterSwapResultBySwapTx(collRouterSwapResult, swapTx)
+	if errors.Is(err, ErrItemNotFound) {
+		return findRouterSwapResultBySwapTx(collRouterSwapResultArchive, swapTx)
+	}
+	return result, err
+}
+
+func findRouterSwapResultBySwapTx(coll *mongo.Collection, swapTx string) (*MgoSwapResult, error) {
+	txs := bson.M{"$in": getSwapTxCandidates(swapTx)}
+	query := bson.M{"$or": []bson.M{{"swaptx": txs}, {"oldswaptxs": txs}}}
+	result := &MgoSwapResult{}
+	err := coll.FindOne(clientCtx, query).Decode(result)
+	if err != nil {
+		return nil, mgoError(err)
+	}
+	return result, nil
+}
@@ -1,9 +1,6 @@
 package mongodb
 
 import (
-	"github.com/anyswap/CrossChain-Router/v3/log"
-
-	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/mongo"
 )
 
@@ -11,12 +8,26 @@ const (
 	tbRouterSwaps       string = "RouterSwaps"
 	tbRouterSwapResults string = "RouterSwapResults"
 	tbUsedRValues       string = "UsedRValues"
+	tbConfigSnapshots   string = "ConfigSnapshots"
+	tbNonceGapFills     string = "NonceGapFills"
+	tbSignGroupStats    string = "SignGroupStats"
+	tbMigrations        string = "Migrations"
+
+	tbRouterSwapsArchive       string = "RouterSwapsArchive"
+	tbRouterSwapResultsArchive string = "RouterSwapResultsArchive"
 )
 
 var (
 	collRouterSwap       *mongo.Collection
 	collRouterSwapResult *mongo.Collection
 	collUsedRValue       *mongo.Collection
+	collConfigSnapshot   *mongo.Collection
+	collNonceGapFill     *mongo.Collection
+	collSignGroupStat    *mongo.Collection
+	collMigration        *mongo.Collection
+
+	collRouterSwapArchive       *mongo.Collection
+	collRouterSwapResultArchive *mongo.Collection
 )
 
 func initCollections() {
@@ -25,25 +36,11 @@ func initCollections() {
 	collRouterSwap = database.Collection(tbRouterSwaps)
 	collRouterSwapResult = database.Collection(tbRouterSwapResults)
 	collUsedRValue = database.Collection(tbUsedRValues)
+	collConfigSnapshot = database.Collection(tbConfigSnapshots)
+	collNonceGapFill = database.Collection(tbNonceGapFills)
+	collSignGroupStat = database.Collection(tbSignGroupStats)
+	collMigration = database.Collection(tbMigrations)
 
-	createOneIndex(collRouterSwap, "inittime", "status", "fromChainID")
-	createOneIndex(collRouterSwap, "txid")
-
-	createOneIndex(collRouterSwapResult, "inittime", "status", "fromChainID")
-	createOneIndex(collRouterSwapResult, "txid")
-	createOneIndex(collRouterSwapResult, "from", "fromChainID")
-
-	log.Info("[mongodb] create indexes finished")
-}
-
-func createOneIndex(coll *mongo.Collection, indexes ...string) {
-	keys := make([]bson.E, len(indexes))
-	for i, index := range indexes {
-		keys[i] = bson.E{Key: index, Value: 1}
-	}
-	model := mongo.IndexModel{Keys: keys}
-	_, err := coll.Indexes().CreateOne(clientCtx, model)
-	if err != nil {
-		log.Error("[mongodb] create indexes failed", "collection", coll.Name(), "indexes", indexes, "err", err)
-	}
+	collRouterSwapArchive = database.Collection(tbRouterSwapsArchive)
+	collRouterSwapResultArchive = database.Collection(tbRouterSwapResultsArchive)
 }
@@ -17,6 +17,14 @@ type MgoSwap struct {
 	InitTime    int64      `bson:"inittime"`
 	Timestamp   int64      `bson:"timestamp"`
 	Memo        string     `bson:"memo"`
+
+	StatusHistory []*StatusChange `bson:"statushistory,omitempty" json:"statushistory,omitempty"`
+
+	// restored from archive by admin, will not be archived again
+	Restored bool `bson:"restored,omitempty" json:"restored,omitempty"`
+
+	// increased on each conditional update (optimistic concurrency control)
+	Version uint64 `bson:"version" json:"version"`
 }
 
 // ToSwapResult converts
@@ -37,6 +45,7 @@ func (swap *MgoSwap) ToSwapResult() *MgoSwapResult {
 		InitTime:    swap.InitTime,
 		Timestamp:   swap.Timestamp,
 		Memo:        swap.Memo,
+		Restored:    swap.Restored,
 	}
 }
 
@@ -67,6 +76,54 @@ type MgoSwapResult struct {
 	Timestamp   int64      `bson:"timestamp"`
 	Memo        string     `bson:"memo"`
 	MPC         string     `bson:"mpc"`
+
+	SwapGasLimit uint64 `bson:"swapgaslimit,omitempty" json:"swapgaslimit,omitempty"`
+	SwapGasUsed  uint64 `bson:"swapgasused,omitempty" json:"swapgasused,omitempty"`
+
+	// fee params of swaptx and oldswaptxs
+	SwapTxAttempts []*SwapTxAttempt `bson:"swaptxattempts,omitempty" json:"swaptxattempts,omitempty"`
+
+	// disagree reasons reported by oracles
+	Disagrees []*DisagreeReason `bson:"disagrees,omitempty" json:"disagrees,omitempty"`
+
+	StatusHistory []*StatusChange `bson:"statushistory,omitempty" json:"statushistory,omitempty"`
+
+	// restored from archive by admin, will not be archived again
+	Restored bool `bson:"restored,omitempty" json:"restored,omitempty"`
+
+	// increased on each conditional update (optimistic concurrency control)
+	Version uint64 `bson:"version" json:"version"`
+}
+
+// StatusChange status transition record
+type StatusChange struct {
+	From      SwapStatus `bson:"from" json:"from"`
+	To        SwapStatus `bson:"to" json:"to"`
+	Actor     string     `bson:"actor" json:"actor"` // worker job or admin
+	Memo      string     `bson:"memo,omitempty" json:"memo,omitempty"`
+	Timestamp int64      `bson:"timestamp" json:"timestamp"`
+}
+
+// DisagreeReason reason of oracle disagreeing to sign swap
+type DisagreeReason struct {
+	Oracle     string `bson:"oracle" json:"oracle"` // enode ID
+	KeyID      string `bson:"keyID" json:"keyID"`
+	Code       string `bson:"code" json:"code"`
+	Reason     string `bson:"reason" json:"reason"`
+	Timestamp  int64  `bson:"timestamp" json:"timestamp"` // disagree time of oracle
+	ReportTime int64  `bson:"reporttime" json:"reporttime"`
+}
+
+// SwapTxAttempt fee params of the sent swap tx (including replacements)
+type SwapTxAttempt struct {
+	SwapTx     string `bson:"swaptx" json:"swaptx"`
+	Nonce      uint64 `bson:"nonce" json:"nonce"`
+	ReplaceNum uint64 `bson:"replacenum,omitempty" json:"replacenum,omitempty"`
+	GasLimit   uint64 `bson:"gaslimit,omitempty" json:"gaslimit,omitempty"`
+	GasPrice   string `bson:"gasprice,omitempty" json:"gasprice,omitempty"`
+	GasTipCap  string `bson:"gastipcap,omitempty" json:"gastipcap,omitempty"`
+	GasFeeCap  string `bson:"gasfeecap,omitempty" json:"gasfeecap,omitempty"`
+	Timestamp  int64  `bson:"timestamp" json:"timestamp"`
 }
 
 // MgoUsedRValue security enhancement
@@ -75,6 +132,42 @@ type MgoUsedRValue struct {
 	Timestamp int64  `bson:"timestamp"`
 }
 
+// MgoConfigSnapshot chain and token configs snapshot saved on reload
+type MgoConfigSnapshot struct {
+	Key       string `bson:"_id"` // timestamp in nanoseconds
+	Timestamp int64  `bson:"timestamp"`
+	Config    string `bson:"config"` // json of config snapshot
+	Diff      string `bson:"diff"`   // json of config diff with previous snapshot
+}
+
+// MgoNonceGapFill zero value self transfer of mpc to fill nonce gap
+type MgoNonceGapFill struct {
+	Key       string `bson:"_id" json:"-"` // chainID + mpc + nonce
+	ChainID   string `bson:"chainID" json:"chainid"`
+	MPC       string `bson:"mpc" json:"mpc"`
+	Nonce     uint64 `bson:"nonce" json:"nonce"`
+	TxHash    string `bson:"txhash" json:"txhash"`
+	Timestamp int64  `bson:"timestamp" json:"timestamp"`
+}
+
+// MgoSignGroupStat mpc sign group health statistics
+type MgoSignGroupStat struct {
+	Key                 string `bson:"_id" json:"-"` // mpc kind + groupID
+	FastMPC             bool   `bson:"fastmpc" json:"fastmpc"`
+	GroupID             string `bson:"groupID" json:"groupID"`
+	Disabled            bool   `bson:"disabled" json:"disabled"` // manually disabled
+	SignCount           uint64 `bson:"signCount" json:"signCount"`
+	SuccessCount        uint64 `bson:"successCount" json:"successCount"`
+	TimeoutCount        uint64 `bson:"timeoutCount" json:"timeoutCount"`
+	DisagreeCount       uint64 `bson:"disagreeCount" json:"disagreeCount"`
+	FailureCount        uint64 `bson:"failureCount" json:"failureCount"` // other failures
+	ConsecutiveFailures uint64 `bson:"consecutiveFailures" json:"consecutiveFailures"`
+	TotalLatency        int64  `bson:"totalLatency" json:"totalLatency"` // milliseconds of success signs
+	LastSignTime        int64  `bson:"lastSignTime" json:"lastSignTime"`
+	LastSuccessTime     int64  `bson:"lastSuccessTime" json:"lastSuccessTime"`
+	LastError           string `bson:"lastError" json:"lastError,omitempty"`
+}
+
 // SwapResultUpdateItems swap update items
 type SwapResultUpdateItems struct {
 	MPC        string
@@ -86,6 +179,15 @@ type SwapResultUpdateItems struct {
 	Status     SwapStatus
 	Timestamp  int64
 	Memo       string
+
+	SwapGasLimit uint64
+	SwapGasUsed  uint64
+
+	Actor string // who changes the status
+
+	// if not nil, update only when the current swaptx is this one,
+	// to not overwrite the swaptx set by others concurrently
+	ExpectedSwapTx *string
 }
 
 // SwapInfo struct
@@ -156,3 +258,10 @@ func (s *SwapInfo) GetTokenID() string {
 	}
 	return ""
 }
+
+// MgoMigration applied schema migration
+type MgoMigration struct {
+	Version     int    `bson:"_id" json:"version"`
+	Description string `bson:"description" json:"description"`
+	Timestamp   int64  `bson:"timestamp" json:"timestamp"`
+}
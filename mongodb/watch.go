@@ -0,0 +1,72 @@
+package mongodb
+
+import (
+	"context"
+
+	"github.com/anyswap/CrossChain-Router/v3/log"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// SwapEvent change event of swap or swap result
+type SwapEvent struct {
+	Key         string     `bson:"_id"`
+	FromChainID string     `bson:"fromChainID"`
+	ToChainID   string     `bson:"toChainID"`
+	Status      SwapStatus `bson:"status"`
+}
+
+type changeEvent struct {
+	FullDocument *SwapEvent `bson:"fullDocument"`
+}
+
+// WatchRouterSwaps watch changes of router swaps in `statuses` by change streams.
+// it blocks until `ctx` is done or error occurs (eg. mongodb is not a replica set).
+func WatchRouterSwaps(ctx context.Context, statuses []SwapStatus, onEvent func(*SwapEvent)) error {
+	return watchCollection(ctx, collRouterSwap, statuses, onEvent)
+}
+
+// WatchRouterSwapResults watch changes of router swap results in `statuses` by change streams.
+// it blocks until `ctx` is done or error occurs (eg. mongodb is not a replica set).
+func WatchRouterSwapResults(ctx context.Context, statuses []SwapStatus, onEvent func(*SwapEvent)) error {
+	return watchCollection(ctx, collRouterSwapResult, statuses, onEvent)
+}
+
+func watchCollection(ctx context.Context, coll *mongo.Collection, statuses []SwapStatus, onEvent func(*SwapEvent)) error {
+	pipeline := mongo.Pipeline{
+		{{Key: "$match", Value: bson.M{
+			"operationType":       bson.M{"$in": bson.A{"insert", "update", "replace"}},
+			"fullDocument.status": bson.M{"$in": statuses},
+		}}},
+		{{Key: "$project", Value: bson.M{
+			"fullDocument._id":         1,
+			"fullDocument.fromChainID": 1,
+			"fullDocument.toChainID":   1,
+			"fullDocument.status":      1,
+		}}},
+	}
+	opts := options.ChangeStream().SetFullDocument(options.UpdateLookup)
+	stream, err := coll.Watch(ctx, pipeline, opts)
+	if err != nil {
+		return mgoError(err)
+	}
+	defer stream.Close(clientCtx)
+
+	log.Info("[mongodb] start watching collection", "collection", coll.Name(), "statuses", statuses)
+	for stream.Next(ctx) {
+		var event changeEvent
+		if err = stream.Decode(&event); err != nil {
+			log.Warn("[mongodb] decode change event failed", "collection", coll.Name(), "err", err)
+			continue
+		}
+		if event.FullDocument != nil {
+			onEvent(event.FullDocument)
+		}
+	}
+	if ctx.Err() != nil {
+		return nil
+	}
+	return mgoError(stream.Err())
+}
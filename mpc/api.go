@@ -52,6 +52,12 @@ func (c *Config) GetEnode(rpcAddr string) (string, error) {
 	return result.Data.Enode, nil
 }
 
+// CheckMPCNodeConnection check connection to the default mpc node
+func (c *Config) CheckMPCNodeConnection() error {
+	_, err := c.GetEnode(c.defaultMPCNode.mpcRPCAddress)
+	return err
+}
+
 // GetSignNonce call getSignNonce
 func (c *Config) GetSignNonce(mpcUser, rpcAddr string) (uint64, error) {
 	var result DataResultResp
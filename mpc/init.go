@@ -27,13 +27,15 @@ var (
 	mpcSigner = types.MakeSigner("EIP155", big.NewInt(mpcWalletServiceID))
 	mpcToAddr = common.HexToAddress(mpcToAddress)
 
-	mpcConfig     *Config
-	fastmpcConfig *Config
+	mpcConfig          *Config
+	fastmpcConfig      *Config
+	highValueMPCConfig *Config
 )
 
 // Config mpc config
 type Config struct {
-	IsFastMPC bool
+	IsFastMPC      bool
+	IsHighValueMPC bool
 
 	mpcAPIPrefix     string
 	mpcGroupID       string
@@ -64,6 +66,11 @@ type Config struct {
 	maxSignGroupFailures      int
 	minIntervalToAddSignGroup int64                   // seconds
 	signGroupFailuresMap      map[string]signFailures // key is groupID
+
+	signGroupStats         map[string]*SignGroupStat // key is groupID
+	signGroupStatsLock     sync.RWMutex
+	changedSignGroupStats  map[string]struct{} // key is groupID, not saved yet
+	signGroupStatChangedCh chan struct{}
 }
 
 type signFailures struct {
@@ -86,6 +93,9 @@ func newConfig() *Config {
 		maxSignGroupFailures:      0,
 		minIntervalToAddSignGroup: int64(3600),
 		signGroupFailuresMap:      make(map[string]signFailures),
+		signGroupStats:            make(map[string]*SignGroupStat),
+		changedSignGroupStats:     make(map[string]struct{}),
+		signGroupStatChangedCh:    make(chan struct{}, 1),
 	}
 }
 
@@ -97,6 +107,11 @@ func GetMPCConfig(isFastMPC bool) *Config {
 	return mpcConfig
 }
 
+// GetHighValueMPCConfig get mpc config of high value swaps
+func GetHighValueMPCConfig() *Config {
+	return highValueMPCConfig
+}
+
 func isEC(signType string) bool {
 	return strings.HasPrefix(signType, "EC")
 }
@@ -122,6 +137,21 @@ func Init(isServer bool) {
 		fastmpcConfig = initConfig(params.GetRouterConfig().FastMPC, isServer)
 		fastmpcConfig.IsFastMPC = true
 	}
+
+	if params.GetRouterConfig().HighValueMPC != nil {
+		highValueMPCConfig = initConfig(params.GetRouterConfig().HighValueMPC, isServer)
+		highValueMPCConfig.IsHighValueMPC = true
+	}
+
+	if isServer {
+		mpcConfig.loadSignGroupStats()
+		if fastmpcConfig != nil {
+			fastmpcConfig.loadSignGroupStats()
+		}
+		if highValueMPCConfig != nil {
+			highValueMPCConfig.loadSignGroupStats()
+		}
+	}
 }
 
 func initConfig(mpcParams *params.MPCConfig, isServer bool) *Config {
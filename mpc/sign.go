@@ -1,7 +1,6 @@
 package mpc
 
 import (
-	"crypto/rand"
 	"encoding/json"
 	"errors"
 	"math/big"
@@ -9,7 +8,7 @@ import (
 
 	"github.com/anyswap/CrossChain-Router/v3/common"
 	"github.com/anyswap/CrossChain-Router/v3/log"
-	"github.com/anyswap/CrossChain-Router/v3/mongodb"
+	"github.com/anyswap/CrossChain-Router/v3/storage"
 	"github.com/anyswap/CrossChain-Router/v3/tools/crypto"
 	"github.com/anyswap/CrossChain-Router/v3/tools/keystore"
 	"github.com/anyswap/CrossChain-Router/v3/tools/rlp"
@@ -49,6 +48,11 @@ func (c *Config) DoSignOneEC(signPubkey, msgHash, msgContext string) (keyID stri
 	return c.DoSign(c.signTypeEC256K1, signPubkey, []string{msgHash}, []string{msgContext})
 }
 
+// DoSignEC mpc sign multiple msgHash with context msgContext in one sign round
+func (c *Config) DoSignEC(signPubkey string, msgHash, msgContext []string) (keyID string, rsvs []string, err error) {
+	return c.DoSign(c.signTypeEC256K1, signPubkey, msgHash, msgContext)
+}
+
 // DoSignOneED mpc sign single msgHash with context msgContext
 func (c *Config) DoSignOneED(signPubkey, msgHash, msgContext string) (keyID string, rsvs []string, err error) {
 	return c.DoSign(signTypeED25519, signPubkey, []string{msgHash}, []string{msgContext})
@@ -70,25 +74,17 @@ func (c *Config) DoSign(signType, signPubkey string, msgHash, msgContext []strin
 			if err = c.pingMPCNode(mpcNode); err != nil {
 				continue
 			}
-			signGroupIndexes := mpcNode.getUsableSignGroupIndexes()
-			signGroupsCount := int64(len(signGroupIndexes))
-			if signGroupsCount == 0 {
+			// healthy sign groups are more likely to be selected first
+			signGroupIndexes := c.orderSignGroupsByWeight(mpcNode, mpcNode.getUsableSignGroupIndexes())
+			if len(signGroupIndexes) == 0 {
 				err = errNoUsableSignGroups
 				continue
 			}
-			// randomly pick first subgroup to sign
-			randIndex, _ := rand.Int(rand.Reader, big.NewInt(signGroupsCount))
-			startIndex := randIndex.Int64()
-			i := startIndex
-			for {
-				keyID, rsvs, err = c.doSignImpl(mpcNode, signGroupIndexes[i], signType, signPubkey, msgHash, msgContext)
+			for _, signGroupIndex := range signGroupIndexes {
+				keyID, rsvs, err = c.doSignImpl(mpcNode, signGroupIndex, signType, signPubkey, msgHash, msgContext)
 				if err == nil {
 					return keyID, rsvs, nil
 				}
-				i = (i + 1) % signGroupsCount
-				if i == startIndex {
-					break
-				}
 			}
 		}
 		time.Sleep(2 * time.Second)
@@ -135,13 +131,17 @@ func (c *Config) doSignImpl(mpcNode *NodeInfo, signGroupIndex int, signType, sig
 	}
 
 	rpcAddr := mpcNode.mpcRPCAddress
+	startTime := time.Now()
 	keyID, err = c.Sign(rawTX, rpcAddr)
 	if err != nil {
+		c.recordSignGroupResult(signGroup, startTime, err)
 		return "", nil, err
 	}
 
-	rsvs, err = c.getSignResult(keyID, rpcAddr)
+	rsvs, err = c.getSignResultWithCause(keyID, rpcAddr)
+	c.recordSignGroupResult(signGroup, startTime, err)
 	if err != nil {
+		err = errGetSignResultFailed
 		if c.maxSignGroupFailures > 0 {
 			old := c.signGroupFailuresMap[signGroup]
 			c.signGroupFailuresMap[signGroup] = signFailures{
@@ -169,7 +169,7 @@ func (c *Config) doSignImpl(mpcNode *NodeInfo, signGroupIndex int, signType, sig
 				return "", nil, errWrongSignatureLength
 			}
 			r := common.ToHex(signature[:32])
-			err = mongodb.AddUsedRValue(signPubkey, r)
+			err = storage.Get().AddUsedRValue(signPubkey, r)
 			if err != nil {
 				return "", nil, errRValueIsUsed
 			}
@@ -184,6 +184,15 @@ func (c *Config) GetSignStatusByKeyID(keyID string) (rsvs []string, err error) {
 }
 
 func (c *Config) getSignResult(keyID, rpcAddr string) (rsvs []string, err error) {
+	rsvs, err = c.getSignResultWithCause(keyID, rpcAddr)
+	if err != nil {
+		return nil, errGetSignResultFailed
+	}
+	return rsvs, nil
+}
+
+// getSignResultWithCause get sign result and keep the failure cause
+func (c *Config) getSignResultWithCause(keyID, rpcAddr string) (rsvs []string, err error) {
 	log.Info("start get sign status", "keyID", keyID)
 	var signStatus *SignStatus
 	i := 0
@@ -215,7 +224,10 @@ LOOP_GET_SIGN_STATUS:
 	}
 	if len(rsvs) == 0 || err != nil {
 		log.Info("get sign status failed", "keyID", keyID, "retryCount", i, "err", err)
-		return nil, errGetSignResultFailed
+		if err == nil {
+			err = errGetSignResultFailed
+		}
+		return nil, err
 	}
 	log.Info("get sign status success", "keyID", keyID, "retryCount", i)
 	return rsvs, nil
@@ -250,7 +262,7 @@ func BuildMPCRawTx(nonce uint64, payload []byte, keyWrapper *keystore.Key) (stri
 // HasValidSignature has valid signature
 func (s *SignInfoData) HasValidSignature() bool {
 	msgContextLen := len(s.MsgContext)
-	if msgContextLen == 1 {
+	if msgContextLen <= len(s.MsgHash) {
 		return true
 	}
 	msgContext := s.MsgContext[:msgContextLen-1]
@@ -0,0 +1,300 @@
+package mpc
+
+import (
+	"crypto/rand"
+	"errors"
+	"fmt"
+	"math/big"
+	"time"
+
+	"github.com/anyswap/CrossChain-Router/v3/log"
+)
+
+const (
+	maxSignGroupWeight   = int64(1000)
+	signGroupLatencyBase = int64(30000) // milliseconds
+)
+
+var signGroupStatStore SignGroupStatStore
+
+// SignGroupStat mpc sign group health statistics
+type SignGroupStat struct {
+	Key                 string `json:"-"` // mpc kind + groupID
+	FastMPC             bool   `json:"fastmpc"`
+	GroupID             string `json:"groupID"`
+	Disabled            bool   `json:"disabled"` // manually disabled
+	SignCount           uint64 `json:"signCount"`
+	SuccessCount        uint64 `json:"successCount"`
+	TimeoutCount        uint64 `json:"timeoutCount"`
+	DisagreeCount       uint64 `json:"disagreeCount"`
+	FailureCount        uint64 `json:"failureCount"` // other failures
+	ConsecutiveFailures uint64 `json:"consecutiveFailures"`
+	TotalLatency        int64  `json:"totalLatency"` // milliseconds of success signs
+	LastSignTime        int64  `json:"lastSignTime"`
+	LastSuccessTime     int64  `json:"lastSuccessTime"`
+	LastError           string `json:"lastError,omitempty"`
+}
+
+// SignGroupStatStore persistent store of sign group stats
+type SignGroupStatStore interface {
+	FindSignGroupStats(isFastMPC bool) ([]*SignGroupStat, error)
+	UpdateSignGroupStat(stat *SignGroupStat) error
+}
+
+// SetSignGroupStatStore set store of sign group stats (call before Init)
+func SetSignGroupStatStore(store SignGroupStatStore) {
+	signGroupStatStore = store
+}
+
+// SignGroupHealth sign group health
+type SignGroupHealth struct {
+	*SignGroupStat
+	Usable      bool    `json:"usable"` // not deleted by consecutive failures
+	SuccessRate float64 `json:"successRate"`
+	AvgLatency  int64   `json:"avgLatency"` // milliseconds
+	Weight      int64   `json:"weight"`
+}
+
+func (c *Config) getSignGroupStatKey(groupID string) string {
+	switch {
+	case c.IsFastMPC:
+		return "fastmpc:" + groupID
+	case c.IsHighValueMPC:
+		return "highvaluempc:" + groupID
+	default:
+		return "mpc:" + groupID
+	}
+}
+
+// getSignGroupStat get or create stat of sign group (must hold lock)
+func (c *Config) getSignGroupStat(groupID string) *SignGroupStat {
+	stat, exist := c.signGroupStats[groupID]
+	if !exist {
+		stat = &SignGroupStat{
+			Key:     c.getSignGroupStatKey(groupID),
+			FastMPC: c.IsFastMPC,
+			GroupID: groupID,
+		}
+		c.signGroupStats[groupID] = stat
+	}
+	return stat
+}
+
+func (c *Config) isSignGroup(groupID string) bool {
+	for _, mpcNode := range c.allInitiatorNodes {
+		for _, signGroup := range mpcNode.originSignGroups {
+			if signGroup == groupID {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// loadSignGroupStats load sign group stats saved in store (server only)
+func (c *Config) loadSignGroupStats() {
+	if signGroupStatStore == nil {
+		return
+	}
+	go c.saveSignGroupStatsLoop()
+	stats, err := signGroupStatStore.FindSignGroupStats(c.IsFastMPC)
+	if err != nil {
+		log.Warn("load sign group stats failed", "isFastMPC", c.IsFastMPC, "isHighValueMPC", c.IsHighValueMPC, "err", err)
+		return
+	}
+	c.signGroupStatsLock.Lock()
+	defer c.signGroupStatsLock.Unlock()
+	for _, stat := range stats {
+		// stats of mpc and high value mpc are both not fast mpc
+		if stat.Key != c.getSignGroupStatKey(stat.GroupID) || !c.isSignGroup(stat.GroupID) {
+			continue
+		}
+		c.signGroupStats[stat.GroupID] = stat
+	}
+	log.Info("load sign group stats success", "isFastMPC", c.IsFastMPC, "isHighValueMPC", c.IsHighValueMPC, "count", len(c.signGroupStats))
+}
+
+// setSignGroupStatChanged mark stat changed and notify saving (must hold lock)
+func (c *Config) setSignGroupStatChanged(groupID string) {
+	c.changedSignGroupStats[groupID] = struct{}{}
+	select {
+	case c.signGroupStatChangedCh <- struct{}{}:
+	default: // saving is already notified
+	}
+}
+
+// saveSignGroupStatsLoop the single writer of sign group stats.
+// the latest stats are copied when saving, so the saved ones are never older.
+func (c *Config) saveSignGroupStatsLoop() {
+	for range c.signGroupStatChangedCh {
+		c.signGroupStatsLock.Lock()
+		stats := make([]*SignGroupStat, 0, len(c.changedSignGroupStats))
+		for groupID := range c.changedSignGroupStats {
+			statCopy := *c.getSignGroupStat(groupID)
+			stats = append(stats, &statCopy)
+		}
+		c.changedSignGroupStats = make(map[string]struct{})
+		c.signGroupStatsLock.Unlock()
+
+		for _, stat := range stats {
+			if err := signGroupStatStore.UpdateSignGroupStat(stat); err != nil {
+				log.Warn("save sign group stat failed", "key", stat.Key, "err", err)
+			}
+		}
+	}
+}
+
+// recordSignGroupResult record sign result of sign group
+func (c *Config) recordSignGroupResult(groupID string, startTime time.Time, err error) {
+	c.signGroupStatsLock.Lock()
+	defer c.signGroupStatsLock.Unlock()
+
+	stat := c.getSignGroupStat(groupID)
+	stat.SignCount++
+	stat.LastSignTime = time.Now().Unix()
+	if err == nil {
+		stat.SuccessCount++
+		stat.ConsecutiveFailures = 0
+		stat.TotalLatency += time.Since(startTime).Milliseconds()
+		stat.LastSuccessTime = stat.LastSignTime
+	} else {
+		switch {
+		case errors.Is(err, errSignTimerTimeout),
+			errors.Is(err, ErrGetSignStatusTimeout):
+			stat.TimeoutCount++
+		case errors.Is(err, ErrGetSignStatusHasDisagree):
+			stat.DisagreeCount++
+		default:
+			stat.FailureCount++
+		}
+		stat.ConsecutiveFailures++
+		stat.LastError = err.Error()
+	}
+	c.setSignGroupStatChanged(groupID)
+}
+
+// SetSignGroupDisabled manually disable or enable sign group
+func (c *Config) SetSignGroupDisabled(groupID string, disabled bool) error {
+	if !c.isSignGroup(groupID) {
+		return fmt.Errorf("unknown sign group '%v'", groupID)
+	}
+	c.signGroupStatsLock.Lock()
+	stat := c.getSignGroupStat(groupID)
+	stat.Disabled = disabled
+	c.setSignGroupStatChanged(groupID)
+	c.signGroupStatsLock.Unlock()
+
+	log.Info("set sign group disabled", "isFastMPC", c.IsFastMPC, "isHighValueMPC", c.IsHighValueMPC, "groupID", groupID, "disabled", disabled)
+	return nil
+}
+
+// GetSignGroupsHealth get health of all sign groups
+func (c *Config) GetSignGroupsHealth() []*SignGroupHealth {
+	usableGroups := make(map[string]bool)
+	allGroups := make([]string, 0)
+	for _, mpcNode := range c.allInitiatorNodes {
+		for _, i := range mpcNode.getUsableSignGroupIndexes() {
+			usableGroups[mpcNode.originSignGroups[i]] = true
+		}
+		for _, signGroup := range mpcNode.originSignGroups {
+			if _, exist := usableGroups[signGroup]; !exist {
+				usableGroups[signGroup] = false
+			}
+			allGroups = append(allGroups, signGroup)
+		}
+	}
+
+	c.signGroupStatsLock.Lock()
+	defer c.signGroupStatsLock.Unlock()
+
+	result := make([]*SignGroupHealth, 0, len(allGroups))
+	added := make(map[string]struct{}, len(allGroups))
+	for _, signGroup := range allGroups {
+		if _, exist := added[signGroup]; exist {
+			continue
+		}
+		added[signGroup] = struct{}{}
+		statCopy := *c.getSignGroupStat(signGroup)
+		result = append(result, &SignGroupHealth{
+			SignGroupStat: &statCopy,
+			Usable:        usableGroups[signGroup],
+			SuccessRate:   getSignGroupSuccessRate(&statCopy),
+			AvgLatency:    getSignGroupAvgLatency(&statCopy),
+			Weight:        getSignGroupWeight(&statCopy),
+		})
+	}
+	return result
+}
+
+func getSignGroupSuccessRate(stat *SignGroupStat) float64 {
+	if stat.SignCount == 0 {
+		return 0
+	}
+	return float64(stat.SuccessCount) / float64(stat.SignCount)
+}
+
+func getSignGroupAvgLatency(stat *SignGroupStat) int64 {
+	if stat.SuccessCount == 0 {
+		return 0
+	}
+	return stat.TotalLatency / int64(stat.SuccessCount)
+}
+
+// getSignGroupWeight healthy groups (high success rate and low latency)
+// have higher weight in selecting. the weight is always positive.
+func getSignGroupWeight(stat *SignGroupStat) int64 {
+	// smoothed success rate, new groups start at half
+	rate := int64(stat.SuccessCount+1) * maxSignGroupWeight / int64(stat.SignCount+2)
+	weight := rate * rate / maxSignGroupWeight
+	if avgLatency := getSignGroupAvgLatency(stat); avgLatency > 0 {
+		weight = weight * signGroupLatencyBase / (signGroupLatencyBase + avgLatency)
+	}
+	failures := stat.ConsecutiveFailures
+	if failures > 5 {
+		failures = 5
+	}
+	weight >>= failures
+	if weight < 1 {
+		weight = 1
+	}
+	return weight
+}
+
+// orderSignGroupsByWeight order enabled sign groups by weighted random selection
+func (c *Config) orderSignGroupsByWeight(mpcNode *NodeInfo, signGroupIndexes []int) []int {
+	indexes := make([]int, 0, len(signGroupIndexes))
+	weights := make([]int64, 0, len(signGroupIndexes))
+	totalWeight := int64(0)
+
+	c.signGroupStatsLock.Lock()
+	for _, i := range signGroupIndexes {
+		stat := c.getSignGroupStat(mpcNode.originSignGroups[i])
+		if stat.Disabled {
+			continue
+		}
+		weight := getSignGroupWeight(stat)
+		indexes = append(indexes, i)
+		weights = append(weights, weight)
+		totalWeight += weight
+	}
+	c.signGroupStatsLock.Unlock()
+
+	result := make([]int, 0, len(indexes))
+	for len(indexes) > 0 {
+		randWeight, _ := rand.Int(rand.Reader, big.NewInt(totalWeight))
+		pick := randWeight.Int64()
+		selected := len(indexes) - 1
+		for i, weight := range weights {
+			if pick < weight {
+				selected = i
+				break
+			}
+			pick -= weight
+		}
+		result = append(result, indexes[selected])
+		totalWeight -= weights[selected]
+		indexes = append(indexes[:selected], indexes[selected+1:]...)
+		weights = append(weights[:selected], weights[selected+1:]...)
+	}
+	return result
+}
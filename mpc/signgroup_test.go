@@ -0,0 +1,137 @@
+package mpc
+
+import (
+	"errors"
+	"sort"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestGetSignGroupWeight(t *testing.T) {
+	tests := []struct {
+		stat *SignGroupStat
+		want int64
+	}{
+		{&SignGroupStat{}, 250}, // new group starts at half rate
+		{&SignGroupStat{SignCount: 98, SuccessCount: 98}, 980},
+		{&SignGroupStat{SignCount: 8, SuccessCount: 8, TotalLatency: 240000}, 405},                        // 30s avg latency
+		{&SignGroupStat{SignCount: 10, SuccessCount: 5, TotalLatency: 50000, ConsecutiveFailures: 2}, 46}, // 10s avg latency
+		{&SignGroupStat{SignCount: 10, ConsecutiveFailures: 10}, 1},                                       // always positive
+	}
+	for i, test := range tests {
+		if have := getSignGroupWeight(test.stat); have != test.want {
+			t.Errorf("test %v: weight mismatch, have %v want %v", i, have, test.want)
+		}
+	}
+}
+
+func newTestSignGroupConfig(stats map[string]*SignGroupStat) (*Config, *NodeInfo) {
+	c := newConfig()
+	mpcNode := &NodeInfo{parent: c}
+	for groupID, stat := range stats {
+		stat.Key = c.getSignGroupStatKey(groupID)
+		stat.GroupID = groupID
+		c.signGroupStats[groupID] = stat
+		mpcNode.originSignGroups = append(mpcNode.originSignGroups, groupID)
+	}
+	sort.Strings(mpcNode.originSignGroups)
+	c.allInitiatorNodes = []*NodeInfo{mpcNode}
+	return c, mpcNode
+}
+
+func TestOrderSignGroupsByWeight(t *testing.T) {
+	c, mpcNode := newTestSignGroupConfig(map[string]*SignGroupStat{
+		"g0": {SignCount: 98, SuccessCount: 98},
+		"g1": {SignCount: 10, ConsecutiveFailures: 10},
+		"g2": {Disabled: true},
+		"g3": {},
+	})
+
+	tests := []struct {
+		indexes []int
+		want    []int // sorted
+	}{
+		{[]int{0, 1, 2, 3}, []int{0, 1, 3}},
+		{[]int{1, 2}, []int{1}},
+		{[]int{2}, []int{}},
+		{[]int{}, []int{}},
+	}
+	for i, test := range tests {
+		have := c.orderSignGroupsByWeight(mpcNode, test.indexes)
+		sort.Ints(have)
+		if len(have) != len(test.want) {
+			t.Errorf("test %v: order mismatch, have %v want %v", i, have, test.want)
+			continue
+		}
+		for j := range have {
+			if have[j] != test.want[j] {
+				t.Errorf("test %v: order mismatch, have %v want %v", i, have, test.want)
+				break
+			}
+		}
+	}
+
+	// the healthy group is almost always selected before the failing one
+	healthyFirst := 0
+	for i := 0; i < 1000; i++ {
+		if c.orderSignGroupsByWeight(mpcNode, []int{0, 1})[0] == 0 {
+			healthyFirst++
+		}
+	}
+	if healthyFirst < 950 {
+		t.Errorf("healthy group is selected first only %v of 1000 times", healthyFirst)
+	}
+}
+
+type testSignGroupStatStore struct {
+	lock  sync.Mutex
+	saved map[string]SignGroupStat
+}
+
+func (s *testSignGroupStatStore) FindSignGroupStats(isFastMPC bool) ([]*SignGroupStat, error) {
+	return nil, nil
+}
+
+func (s *testSignGroupStatStore) UpdateSignGroupStat(stat *SignGroupStat) error {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	s.saved[stat.Key] = *stat
+	return nil
+}
+
+func (s *testSignGroupStatStore) get(key string) SignGroupStat {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	return s.saved[key]
+}
+
+func TestSaveSignGroupStatsInOrder(t *testing.T) {
+	store := &testSignGroupStatStore{saved: make(map[string]SignGroupStat)}
+	SetSignGroupStatStore(store)
+	defer SetSignGroupStatStore(nil)
+
+	c, _ := newTestSignGroupConfig(map[string]*SignGroupStat{"g0": {}})
+	c.loadSignGroupStats()
+
+	count := 100
+	for i := 0; i < count; i++ {
+		var err error
+		if i%3 == 0 {
+			err = errors.New("sign failed")
+		}
+		c.recordSignGroupResult("g0", time.Now(), err)
+	}
+	_ = c.SetSignGroupDisabled("g0", true)
+
+	key := c.getSignGroupStatKey("g0")
+	deadline := time.Now().Add(5 * time.Second)
+	for time.Now().Before(deadline) {
+		saved := store.get(key)
+		if saved.SignCount == uint64(count) && saved.Disabled {
+			return
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	t.Fatalf("latest stat is not saved, have %+v", store.get(key))
+}
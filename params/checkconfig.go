@@ -16,6 +16,8 @@ import (
 
 var blankOrCommaSepRegexp = regexp.MustCompile(`[\s,]+`) // blank or comma separated
 
+const maxBatchSignSize = 20
+
 func splitStringByBlankOrComma(str string) []string {
 	return blankOrCommaSepRegexp.Split(strings.TrimSpace(str), -1)
 }
@@ -53,6 +55,10 @@ func (config *RouterConfig) CheckConfig(isServer bool) (err error) {
 
 	if isServer {
 		err = config.Server.CheckConfig()
+		if err == nil && len(config.Server.BatchSignSize) > 0 &&
+			(config.Extra == nil || !config.Extra.EnableParallelSwap) {
+			log.Warn("'BatchSignSize' only works if 'EnableParallelSwap' is true")
+		}
 	} else {
 		err = config.Oracle.CheckConfig()
 	}
@@ -75,10 +81,30 @@ func (config *RouterConfig) CheckConfig(isServer bool) (err error) {
 		}
 	}
 
-	if config.Onchain == nil {
-		return errors.New("server must config 'Onchain'")
+	if config.HighValueMPC != nil {
+		err = config.HighValueMPC.CheckConfig(isServer)
+		if err != nil {
+			return err
+		}
+	}
+	if config.HighValueMPC == nil && config.Extra != nil && len(config.Extra.HighValueMPCThresholds) > 0 {
+		return errors.New("must config 'HighValueMPC' to use 'Extra.HighValueMPCThresholds'")
+	}
+
+	switch strings.ToLower(config.ConfigSource) {
+	case "", OnchainConfigSource:
+		if config.Onchain == nil {
+			return errors.New("server must config 'Onchain'")
+		}
+		err = config.Onchain.CheckConfig()
+	case FileConfigSource:
+		if config.FileConfig == nil {
+			return errors.New("server must config 'FileConfig'")
+		}
+		err = config.FileConfig.CheckConfig()
+	default:
+		err = fmt.Errorf("unknown config source '%v'", config.ConfigSource)
 	}
-	err = config.Onchain.CheckConfig()
 	if err != nil {
 		return err
 	}
@@ -94,6 +120,15 @@ func (c *RouterOracleConfig) CheckConfig() (err error) {
 	if c.ServerAPIAddress == "" {
 		return errors.New("oracle must config 'ServerAPIAddress'")
 	}
+	if c.LocalAPIPort < 0 || c.LocalAPIPort > 65535 {
+		return errors.New("wrong 'LocalAPIPort'")
+	}
+	if c.PolicyFile != "" {
+		c.policy, err = LoadOraclePolicy(c.PolicyFile)
+		if err != nil {
+			return err
+		}
+	}
 	if c.NoCheckServerConnection {
 		return nil
 	}
@@ -121,11 +156,25 @@ func (s *RouterServerConfig) CheckConfig() error {
 	if s.APIServer == nil {
 		return errors.New("server must config 'APIServer'")
 	}
-	if s.MongoDB == nil {
-		return errors.New("server must config 'MongoDB'")
+	if s.EmbeddedDBPath != "" {
+		if s.MongoDB != nil {
+			return errors.New("server forbid config both 'MongoDB' and 'EmbeddedDBPath'")
+		}
+	} else {
+		if s.MongoDB == nil {
+			return errors.New("server must config 'MongoDB' or 'EmbeddedDBPath'")
+		}
+		if err := s.MongoDB.CheckConfig(); err != nil {
+			return err
+		}
 	}
-	if err := s.MongoDB.CheckConfig(); err != nil {
-		return err
+	for reporter, enode := range s.OracleReporters {
+		if !common.IsHexAddress(reporter) {
+			return fmt.Errorf("wrong oracle reporter address '%v'", reporter)
+		}
+		if !strings.HasPrefix(enode, "enode://") {
+			return fmt.Errorf("wrong enode '%v' of oracle reporter '%v'", enode, reporter)
+		}
 	}
 	initAutoSwapNonceEnabledChains()
 	for _, chainID := range s.ChainIDBlackList {
@@ -202,6 +251,51 @@ func (s *RouterServerConfig) CheckConfig() error {
 	if err != nil {
 		return err
 	}
+	err = s.CheckGasLimitConfig()
+	if err != nil {
+		return err
+	}
+	for chainID, c := range s.GasPriceStrategy {
+		if _, err = common.GetBigIntFromStr(chainID); err != nil {
+			return fmt.Errorf("wrong chain id '%v' in 'GasPriceStrategy'", chainID)
+		}
+		if c == nil {
+			return fmt.Errorf("empty 'GasPriceStrategy' config of chain %v", chainID)
+		}
+		if err = c.CheckConfig(); err != nil {
+			return fmt.Errorf("chain %v 'GasPriceStrategy': %w", chainID, err)
+		}
+	}
+	for chainID, c := range s.ReplacePolicy {
+		if _, err = common.GetBigIntFromStr(chainID); err != nil {
+			return fmt.Errorf("wrong chain id '%v' in 'ReplacePolicy'", chainID)
+		}
+		if c == nil {
+			return fmt.Errorf("empty 'ReplacePolicy' config of chain %v", chainID)
+		}
+		if err = c.CheckConfig(); err != nil {
+			return fmt.Errorf("chain %v 'ReplacePolicy': %w", chainID, err)
+		}
+	}
+	for chainID, size := range s.BatchSignSize {
+		if _, err = common.GetBigIntFromStr(chainID); err != nil {
+			return fmt.Errorf("wrong chain id '%v' in 'BatchSignSize'", chainID)
+		}
+		if size < 0 || size > maxBatchSignSize {
+			return fmt.Errorf("chain %v 'BatchSignSize' must be in range [0, %v]", chainID, maxBatchSignSize)
+		}
+	}
+	for chainID, c := range s.Signer {
+		if _, err = common.GetBigIntFromStr(chainID); err != nil {
+			return fmt.Errorf("wrong chain id '%v' in 'Signer'", chainID)
+		}
+		if c == nil {
+			return fmt.Errorf("empty 'Signer' config of chain %v", chainID)
+		}
+		if err = c.CheckConfig(); err != nil {
+			return fmt.Errorf("chain %v 'Signer': %w", chainID, err)
+		}
+	}
 	err = s.CheckExtra()
 	if err != nil {
 		return err
@@ -261,6 +355,117 @@ func (s *RouterServerConfig) CheckDynamicFeeTxConfig() error {
 	return nil
 }
 
+// CheckGasLimitConfig check gas limit estimation config
+func (s *RouterServerConfig) CheckGasLimitConfig() error {
+	for chainID, configs := range s.GasLimit {
+		if _, err := common.GetBigIntFromStr(chainID); err != nil {
+			return fmt.Errorf("wrong chain id '%v' in 'GasLimit'", chainID)
+		}
+		for swapType, c := range configs {
+			switch swapType {
+			case "erc20swap", "nftswap", "nftbatchswap", "anycallswap", DefaultGasLimitSwapType:
+			default:
+				return fmt.Errorf("unknown swap type '%v' in 'GasLimit' of chain %v", swapType, chainID)
+			}
+			if c == nil {
+				return fmt.Errorf("empty 'GasLimit' config of chain %v swap type %v", chainID, swapType)
+			}
+			if c.PlusGasLimitPercent > 500 {
+				return fmt.Errorf("too large 'PlusGasLimitPercent' of chain %v swap type %v", chainID, swapType)
+			}
+			if c.MaxGasLimit > 0 && c.MaxGasLimit < c.MinGasLimit {
+				return fmt.Errorf("must satisfy 'MinGasLimit <= MaxGasLimit' of chain %v swap type %v", chainID, swapType)
+			}
+		}
+	}
+	return nil
+}
+
+// CheckConfig check gas price strategy config
+func (c *GasPriceStrategyConfig) CheckConfig() error {
+	isBaseStrategy := func(strategy string) bool {
+		switch strategy {
+		case "", GasPriceStrategyFirst, GasPriceStrategyMax, GasPriceStrategyMedian, GasPriceStrategyPercentile:
+			return true
+		default:
+			return false
+		}
+	}
+	switch c.Strategy {
+	case GasPriceStrategyUrgency:
+		if !isBaseStrategy(c.BaseStrategy) {
+			return fmt.Errorf("wrong base strategy '%v' of urgency strategy", c.BaseStrategy)
+		}
+		if len(c.UrgencyTiers) == 0 {
+			return errors.New("urgency strategy without 'UrgencyTiers'")
+		}
+		for _, tier := range c.UrgencyTiers {
+			if tier == nil || tier.MinAge < 0 {
+				return errors.New("wrong urgency tier")
+			}
+			if tier.PlusPercent > 500 {
+				return errors.New("too large urgency tier 'PlusPercent'")
+			}
+		}
+	case GasPriceStrategyEWMA:
+		if !isBaseStrategy(c.BaseStrategy) {
+			return fmt.Errorf("wrong base strategy '%v' of ewma strategy", c.BaseStrategy)
+		}
+		if c.EWMAWeightPercent > 100 {
+			return errors.New("too large 'EWMAWeightPercent'")
+		}
+	default:
+		if !isBaseStrategy(c.Strategy) {
+			return fmt.Errorf("unknown gas price strategy '%v'", c.Strategy)
+		}
+	}
+	if c.BlockCount < 0 || c.BlockCount > 1024 {
+		return errors.New("'BlockCount' must be in range [0, 1024]")
+	}
+	if c.Percentile < 0 || c.Percentile > 100 {
+		return errors.New("'Percentile' must be in range [0, 100]")
+	}
+	return nil
+}
+
+// CheckConfig check replace policy config
+func (c *ReplacePolicyConfig) CheckConfig() error {
+	if c.MinBumpPercent > 100 {
+		return errors.New("too large 'MinBumpPercent'")
+	}
+	if c.InPoolBumpPercent > 100 {
+		return errors.New("too large 'InPoolBumpPercent'")
+	}
+	if c.BaseFeeRisePlusPercent > 100 {
+		return errors.New("too large 'BaseFeeRisePlusPercent'")
+	}
+	if c.BlockCountFeeHistory < 0 || c.BlockCountFeeHistory > 1024 {
+		return errors.New("'BlockCountFeeHistory' must be in range [0, 1024]")
+	}
+	return nil
+}
+
+// CheckConfig check signer config
+func (c *SignerConfig) CheckConfig() error {
+	switch c.Type {
+	case "", SignerTypeMPC:
+	case SignerTypeKeystore:
+		if c.KeystoreFile == "" || c.PasswordFile == "" {
+			return errors.New("keystore signer must config 'KeystoreFile' and 'PasswordFile'")
+		}
+	case SignerTypeRemote:
+		if c.RemoteURL == "" {
+			return errors.New("remote signer must config 'RemoteURL'")
+		}
+		if c.RemoteTimeout < 0 {
+			return errors.New("negative 'RemoteTimeout'")
+		}
+	default:
+		return fmt.Errorf("unknown signer type '%v'", c.Type)
+	}
+	return nil
+}
+
 // CheckExtra check extra server config
 func (s *RouterServerConfig) CheckExtra() error {
 	if s.MaxPlusGasPricePercentage == 0 {
@@ -272,6 +477,9 @@ func (s *RouterServerConfig) CheckExtra() error {
 	if s.MaxGasPriceFluctPercent > 100 {
 		return errors.New("too large 'MaxGasPriceFluctPercent' value")
 	}
+	if s.ArchiveSwapDays < 0 {
+		return errors.New("negative 'ArchiveSwapDays' value")
+	}
 	return nil
 }
 
@@ -321,6 +529,26 @@ func (c *OnchainConfig) CheckConfig() error {
 	return errors.New("check onchain config connection failed")
 }
 
+// CheckConfig check local file storing chain and token configs
+func (c *FileConfig) CheckConfig() error {
+	if c.Path == "" {
+		return errors.New("file config must config 'Path'")
+	}
+	if c.ReloadCycle > 0 && c.ReloadCycle < 60 {
+		return errors.New("file config wrong 'ReloadCycle' value (must be 0 or >= 60)")
+	}
+	currDir, err := common.CurrentDir()
+	if err != nil {
+		return err
+	}
+	c.Path = common.AbsolutePath(currDir, c.Path)
+	if !common.FileExist(c.Path) {
+		return fmt.Errorf("file config '%v' not exist", c.Path)
+	}
+	log.Info("check file config success", "path", c.Path)
+	return nil
+}
+
 // CheckConfig check mpc config
 //nolint:funlen,gocyclo // ok
 func (c *MPCConfig) CheckConfig(isServer bool) (err error) {
@@ -382,10 +610,13 @@ func (c *ExtraConfig) CheckConfig() (err error) {
 	initCallByContractCodeHashWhitelist()
 	initBigValueWhitelist()
 	initDynamicFeeTxEnabledChains()
+	initAccessListTxEnabledChains()
 	initEnableCheckTxBlockHashChains()
 	initEnableCheckTxBlockIndexChains()
 	initDisableUseFromChainIDInReceiptChains()
+	initDisableSimulateSwapTxChains()
 	initUseFastMPCChains()
+	initHighValueMPCThresholds()
 	initDontCheckReceivedTokenIDs()
 
 	if c.UsePendingBalance {
@@ -408,9 +639,11 @@ func (c *ExtraConfig) CheckConfig() (err error) {
 		"callByContractCodeHashWhitelist", c.CallByContractCodeHashWhitelist,
 		"bigValueWhitelist", c.BigValueWhitelist,
 		"dynamicFeeTxEnabledChains", c.DynamicFeeTxEnabledChains,
+		"accessListTxEnabledChains", c.AccessListTxEnabledChains,
 		"enableCheckTxBlockHashChains", c.EnableCheckTxBlockHashChains,
 		"enableCheckTxBlockIndexChains", c.EnableCheckTxBlockIndexChains,
 		"initDisableUseFromChainIDInReceiptChains", c.DisableUseFromChainIDInReceiptChains,
+		"disableSimulateSwapTxChains", c.DisableSimulateSwapTxChains,
 		"baseFeePercent", c.BaseFeePercent,
 		"usePendingBalance", c.UsePendingBalance,
 		"customs", c.Customs,
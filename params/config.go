@@ -15,6 +15,15 @@ const (
 	RouterSwapPrefixID = "routerswap"
 )
 
+// router config sources
+const (
+	OnchainConfigSource = "onchain"
+	FileConfigSource    = "file"
+)
+
+// DefaultGasLimitSwapType the fallback swap type key in 'GasLimit' config
+const DefaultGasLimitSwapType = "default"
+
 // IsTestMode used for testing
 var IsTestMode bool
 
@@ -37,12 +46,16 @@ var (
 	callByContractCodeHashWhitelist map[string]map[string]struct{} // chainID -> codehash
 	bigValueWhitelist               map[string]map[string]struct{} // tokenID -> caller
 
+	highValueMPCThresholds map[string]*big.Int // tokenID -> threshold
+
 	autoSwapNonceEnabledChains map[string]struct{}
 
 	dynamicFeeTxEnabledChains            map[string]struct{}
+	accessListTxEnabledChains            map[string]struct{}
 	enableCheckTxBlockHashChains         map[string]struct{}
 	enableCheckTxBlockIndexChains        map[string]struct{}
 	disableUseFromChainIDInReceiptChains map[string]struct{}
+	disableSimulateSwapTxChains          map[string]struct{}
 	useFastMPCChains                     map[string]struct{}
 	dontCheckReceivedTokenIDs            map[string]struct{}
 
@@ -64,12 +77,24 @@ type RouterServerConfig struct {
 	MongoDB    *MongoDBConfig
 	APIServer  *APIServerConfig
 
+	// oracles who can report disagree reasons, key is mpc user address, value is enode
+	OracleReporters map[string]string `toml:",omitempty" json:",omitempty"`
+
 	ChainIDBlackList []string `toml:",omitempty" json:",omitempty"`
 	TokenIDBlackList []string `toml:",omitempty" json:",omitempty"`
 	AccountBlackList []string `toml:",omitempty" json:",omitempty"`
 
 	AutoSwapNonceEnabledChains []string `toml:",omitempty" json:",omitempty"`
 
+	// use embedded database in this directory instead of MongoDB (single node deployment)
+	EmbeddedDBPath string `toml:",omitempty" json:",omitempty"`
+
+	// drive verify/swap/stable/replace jobs by database change events (polling is still kept as fallback)
+	EnableEventDrivenJobs bool `toml:",omitempty" json:",omitempty"`
+
+	// archive swaps in terminal statuses not updated for this days (0 means disabled, mongodb only)
+	ArchiveSwapDays int64 `toml:",omitempty" json:",omitempty"`
+
 	// extras
 	EnableReplaceSwap          bool
 	EnablePassBigValueSwap     bool
@@ -88,14 +113,33 @@ type RouterServerConfig struct {
 	RetrySendTxLoopCount       map[string]int    `toml:",omitempty" json:",omitempty"` // key is chain ID
 	SendTxLoopCount            map[string]int    `toml:",omitempty" json:",omitempty"` // key is chain ID
 	SendTxLoopInterval         map[string]int    `toml:",omitempty" json:",omitempty"` // key is chain ID
+	BatchSignSize              map[string]int    `toml:",omitempty" json:",omitempty"` // key is chain ID, only works in parallel swap
 
 	DynamicFeeTx map[string]*DynamicFeeTxConfig `toml:",omitempty" json:",omitempty"` // key is chain ID
+	// chainID -> swap type (erc20swap, nftswap, nftbatchswap, anycallswap, default) -> config
+	GasLimit map[string]map[string]*GasLimitConfig `toml:",omitempty" json:",omitempty"`
+	// gas price strategy of legacy tx, overwrite 'CalcGasPriceMethod' if exist
+	GasPriceStrategy map[string]*GasPriceStrategyConfig `toml:",omitempty" json:",omitempty"` // key is chain ID
+	// fee bump policy of replacing swap tx
+	ReplacePolicy map[string]*ReplacePolicyConfig `toml:",omitempty" json:",omitempty"` // key is chain ID
+	// signer backend of swap tx, use mpc if not configed
+	Signer map[string]*SignerConfig `toml:",omitempty" json:",omitempty"` // key is chain ID
 }
 
 // RouterOracleConfig only for oracle
 type RouterOracleConfig struct {
 	ServerAPIAddress        string
 	NoCheckServerConnection bool
+
+	// local policy file of accepting sign
+	PolicyFile string `toml:",omitempty" json:",omitempty"`
+
+	// accept ledger and reconciliation with server
+	LedgerRetentionDays uint64 `toml:",omitempty" json:",omitempty"` // default 90
+	ReconcileInterval   uint64 `toml:",omitempty" json:",omitempty"` // seconds, 0 to disable
+	LocalAPIPort        int    `toml:",omitempty" json:",omitempty"` // listen on localhost, 0 to disable
+
+	policy *OraclePolicy
 }
 
 // RouterConfig config
@@ -103,15 +147,18 @@ type RouterConfig struct {
 	Server *RouterServerConfig `toml:",omitempty" json:",omitempty"`
 	Oracle *RouterOracleConfig `toml:",omitempty" json:",omitempty"`
 
-	Identifier  string
-	SwapType    string
-	SwapSubType string
-	Onchain     *OnchainConfig
-	Gateways    map[string][]string // key is chain ID
-	GatewaysExt map[string][]string `toml:",omitempty" json:",omitempty"` // key is chain ID
-	MPC         *MPCConfig
-	FastMPC     *MPCConfig   `toml:",omitempty" json:",omitempty"`
-	Extra       *ExtraConfig `toml:",omitempty" json:",omitempty"`
+	Identifier   string
+	SwapType     string
+	SwapSubType  string
+	ConfigSource string              `toml:",omitempty" json:",omitempty"` // onchain (default) or file
+	Onchain      *OnchainConfig      `toml:",omitempty" json:",omitempty"`
+	FileConfig   *FileConfig         `toml:",omitempty" json:",omitempty"`
+	Gateways     map[string][]string // key is chain ID
+	GatewaysExt  map[string][]string `toml:",omitempty" json:",omitempty"` // key is chain ID
+	MPC          *MPCConfig
+	FastMPC      *MPCConfig   `toml:",omitempty" json:",omitempty"`
+	HighValueMPC *MPCConfig   `toml:",omitempty" json:",omitempty"` // sign swaps reached 'Extra.HighValueMPCThresholds'
+	Extra        *ExtraConfig `toml:",omitempty" json:",omitempty"`
 }
 
 // ExtraConfig extra config
@@ -137,13 +184,18 @@ type ExtraConfig struct {
 	BigValueWhitelist               map[string][]string `toml:",omitempty" json:",omitempty"` // tokenID -> whitelist
 
 	DynamicFeeTxEnabledChains            []string `toml:",omitempty" json:",omitempty"`
+	AccessListTxEnabledChains            []string `toml:",omitempty" json:",omitempty"`
 	EnableCheckTxBlockHashChains         []string `toml:",omitempty" json:",omitempty"`
 	EnableCheckTxBlockIndexChains        []string `toml:",omitempty" json:",omitempty"`
 	DisableUseFromChainIDInReceiptChains []string `toml:",omitempty" json:",omitempty"`
+	DisableSimulateSwapTxChains          []string `toml:",omitempty" json:",omitempty"`
 	UseFastMPCChains                     []string `toml:",omitempty" json:",omitempty"`
 	DontCheckReceivedTokenIDs            []string `toml:",omitempty" json:",omitempty"`
 
 	RPCClientTimeout map[string]int `toml:",omitempty" json:",omitempty"` // key is chainID
+	// tokenID -> value threshold (unit is 18 decimals). swaps reached the threshold
+	// are signed by 'HighValueMPC' (higher threshold) instead of 'MPC' or 'FastMPC'
+	HighValueMPCThresholds map[string]string `toml:",omitempty" json:",omitempty"`
 	// chainID,customKey => customValue
 	Customs map[string]map[string]string `toml:",omitempty" json:",omitempty"`
 }
@@ -156,6 +208,12 @@ type OnchainConfig struct {
 	ReloadCycle uint64 // seconds
 }
 
+// FileConfig struct (read chain and token configs from local file)
+type FileConfig struct {
+	Path        string // toml or json file
+	ReloadCycle uint64 // seconds
+}
+
 // MPCConfig mpc related config
 type MPCConfig struct {
 	SignTypeEC256K1 string `toml:",omitempty" json:",omitempty"`
@@ -207,6 +265,9 @@ type MongoDBConfig struct {
 	DBName   string
 	UserName string `json:"-"`
 	Password string `json:"-"`
+
+	// do not run schema migrations on startup (run 'swaprouter migrate' manually)
+	SkipMigration bool `toml:",omitempty" json:",omitempty"`
 }
 
 // DynamicFeeTxConfig dynamic fee tx config
@@ -232,6 +293,79 @@ func (c *DynamicFeeTxConfig) GetMaxGasFeeCap() *big.Int {
 	return c.maxGasFeeCap
 }
 
+// GasLimitConfig gas limit estimation config
+type GasLimitConfig struct {
+	PlusGasLimitPercent uint64 // safety margin added to estimated gas, default is 30
+	MinGasLimit         uint64 `toml:",omitempty" json:",omitempty"` // floor, default is 'DefaultGasLimit'
+	MaxGasLimit         uint64 `toml:",omitempty" json:",omitempty"` // ceiling, 0 means no limit
+}
+
+// gas price strategies
+const (
+	GasPriceStrategyFirst      = "first"
+	GasPriceStrategyMax        = "max"
+	GasPriceStrategyMedian     = "median"
+	GasPriceStrategyPercentile = "percentile"
+	GasPriceStrategyUrgency    = "urgency"
+	GasPriceStrategyEWMA       = "ewma"
+)
+
+// GasPriceStrategyConfig gas price strategy config
+type GasPriceStrategyConfig struct {
+	Strategy     string // first, max, median (default), percentile, urgency, ewma
+	BaseStrategy string `toml:",omitempty" json:",omitempty"` // base of urgency and ewma strategy
+
+	// percentile strategy: base fee + percentile of recent block rewards
+	BlockCount int     `toml:",omitempty" json:",omitempty"` // default 20
+	Percentile float64 `toml:",omitempty" json:",omitempty"` // default 50
+
+	// urgency strategy: plus percent of the max matched tier
+	UrgencyTiers []*UrgencyTierConfig `toml:",omitempty" json:",omitempty"`
+
+	// ewma strategy: weight of the newest sample
+	EWMAWeightPercent uint64 `toml:",omitempty" json:",omitempty"` // default 30
+}
+
+// UrgencyTierConfig urgency tier config
+type UrgencyTierConfig struct {
+	MinAge      int64  // seconds since the swap is registered
+	BigValue    bool   `toml:",omitempty" json:",omitempty"` // only match swaps reached big value threshold
+	PlusPercent uint64 // plus percent of gas price
+}
+
+// ReplacePolicyConfig replace swap tx policy config
+type ReplacePolicyConfig struct {
+	// min percent to raise gas price (legacy tx), or both gas tip cap and
+	// gas fee cap (dynamic fee tx) to replace the tx in pool, default 10
+	MinBumpPercent uint64 `toml:",omitempty" json:",omitempty"`
+	// extra bump percent if the replaced tx is still in pool
+	InPoolBumpPercent uint64 `toml:",omitempty" json:",omitempty"`
+	// plus percent of gas price or gas fee cap if base fee is rising
+	BaseFeeRisePlusPercent uint64 `toml:",omitempty" json:",omitempty"`
+	// blocks of fee history to detect base fee trend, default 10
+	BlockCountFeeHistory int `toml:",omitempty" json:",omitempty"`
+}
+
+// signer types
+const (
+	SignerTypeMPC      = "mpc"
+	SignerTypeKeystore = "keystore"
+	SignerTypeRemote   = "remote"
+)
+
+// SignerConfig signer backend config
+type SignerConfig struct {
+	Type string // mpc (default), keystore, remote
+
+	// keystore signer
+	KeystoreFile string `toml:",omitempty" json:"-"`
+	PasswordFile string `toml:",omitempty" json:"-"`
+
+	// remote signer (web3signer compatible 'eth_signTransaction' api)
+	RemoteURL     string `toml:",omitempty" json:"-"`
+	RemoteTimeout int    `toml:",omitempty" json:",omitempty"` // seconds, default 10
+}
+
 // GetIdentifier get identifier (to distiguish in mpc accept)
 func GetIdentifier() string {
 	return GetRouterConfig().Identifier
@@ -301,6 +435,42 @@ func GetNoncePassedConfirmInterval(chainID string) int64 {
 	return 0
 }
 
+// GetGasPriceStrategyConfig get gas price strategy config
+func GetGasPriceStrategyConfig(chainID string) *GasPriceStrategyConfig {
+	serverCfg := GetRouterServerConfig()
+	if serverCfg == nil {
+		return nil
+	}
+	return serverCfg.GasPriceStrategy[chainID]
+}
+
+// GetReplacePolicyConfig get replace policy config
+func GetReplacePolicyConfig(chainID string) *ReplacePolicyConfig {
+	serverCfg := GetRouterServerConfig()
+	if serverCfg == nil {
+		return nil
+	}
+	return serverCfg.ReplacePolicy[chainID]
+}
+
+// GetBatchSignSize get max count of swaps signed in one mpc sign round
+func GetBatchSignSize(chainID string) int {
+	serverCfg := GetRouterServerConfig()
+	if serverCfg == nil {
+		return 0
+	}
+	return serverCfg.BatchSignSize[chainID]
+}
+
+// GetSignerConfig get signer backend config
+func GetSignerConfig(chainID string) *SignerConfig {
+	serverCfg := GetRouterServerConfig()
+	if serverCfg == nil {
+		return nil
+	}
+	return serverCfg.Signer[chainID]
+}
+
 // GetCalcGasPriceMethod get calc gas price method eg. median (default), first, max, etc.
 func GetCalcGasPriceMethod(chainID string) string {
 	serverCfg := GetRouterServerConfig()
@@ -601,6 +771,26 @@ func initBigValueWhitelist() {
 	log.Info("initBigValueWhitelist success")
 }
 
+func initHighValueMPCThresholds() {
+	highValueMPCThresholds = make(map[string]*big.Int)
+	if GetExtraConfig() == nil || len(GetExtraConfig().HighValueMPCThresholds) == 0 {
+		return
+	}
+	for tid, thresholdStr := range GetExtraConfig().HighValueMPCThresholds {
+		threshold, err := common.GetBigIntFromStr(thresholdStr)
+		if err != nil || threshold.Sign() <= 0 {
+			log.Fatal("initHighValueMPCThresholds wrong threshold", "tokenID", tid, "threshold", thresholdStr)
+		}
+		highValueMPCThresholds[tid] = threshold
+	}
+	log.Info("initHighValueMPCThresholds success")
+}
+
+// GetHighValueMPCThreshold get value threshold (unit is 18 decimals) to use high value mpc
+func GetHighValueMPCThreshold(tokenID string) *big.Int {
+	return highValueMPCThresholds[tokenID]
+}
+
 // IsInBigValueWhitelist is in call by contract whitelist
 func IsInBigValueWhitelist(tokenID, caller string) bool {
 	whitelist, exist := bigValueWhitelist[tokenID]
@@ -666,11 +856,46 @@ func GetMPCConfig(isFastMPC bool) *MPCConfig {
 	return routerConfig.MPC
 }
 
+// GetHighValueMPCConfig get mpc config of high value swaps
+func GetHighValueMPCConfig() *MPCConfig {
+	return routerConfig.HighValueMPC
+}
+
 // GetOnchainContract get onchain config contract address
 func GetOnchainContract() string {
+	if routerConfig.Onchain == nil {
+		return ""
+	}
 	return routerConfig.Onchain.Contract
 }
 
+// IsFileConfigSource is chain and token configs read from local file
+func IsFileConfigSource() bool {
+	return strings.EqualFold(routerConfig.ConfigSource, FileConfigSource)
+}
+
+// GetFileConfigPath get local file path of chain and token configs
+func GetFileConfigPath() string {
+	if routerConfig.FileConfig == nil {
+		return ""
+	}
+	return routerConfig.FileConfig.Path
+}
+
+// GetConfigReloadCycle get reload cycle of chain and token configs
+func GetConfigReloadCycle() uint64 {
+	if IsFileConfigSource() {
+		if routerConfig.FileConfig == nil {
+			return 0
+		}
+		return routerConfig.FileConfig.ReloadCycle
+	}
+	if routerConfig.Onchain == nil {
+		return 0
+	}
+	return routerConfig.Onchain.ReloadCycle
+}
+
 // GetExtraConfig get extra config
 func GetExtraConfig() *ExtraConfig {
 	return routerConfig.Extra
@@ -710,6 +935,16 @@ func IsRouterAssistant(account string) bool {
 	return false
 }
 
+// GetOracleReporterEnode get enode of oracle reporter (empty if not configed)
+func GetOracleReporterEnode(account string) string {
+	for reporter, enode := range routerConfig.Server.OracleReporters {
+		if strings.EqualFold(account, reporter) {
+			return enode
+		}
+	}
+	return ""
+}
+
 // IsChainIDInBlackList is chain id in black list
 func IsChainIDInBlackList(chainID string) bool {
 	_, exist := chainIDBlacklistMap[chainID]
@@ -825,6 +1060,26 @@ func IsDynamicFeeTxEnabled(chainID string) bool {
 	return exist
 }
 
+func initAccessListTxEnabledChains() {
+	accessListTxEnabledChains = make(map[string]struct{})
+	if GetExtraConfig() == nil || len(GetExtraConfig().AccessListTxEnabledChains) == 0 {
+		return
+	}
+	for _, cid := range GetExtraConfig().AccessListTxEnabledChains {
+		if _, err := common.GetBigIntFromStr(cid); err != nil {
+			log.Fatal("initAccessListTxEnabledChains wrong chainID", "chainID", cid, "err", err)
+		}
+		accessListTxEnabledChains[cid] = struct{}{}
+	}
+	log.Info("initAccessListTxEnabledChains success")
+}
+
+// IsAccessListTxEnabled is access list tx enabled (EIP-2930)
+func IsAccessListTxEnabled(chainID string) bool {
+	_, exist := accessListTxEnabledChains[chainID]
+	return exist
+}
+
 func initEnableCheckTxBlockHashChains() {
 	enableCheckTxBlockHashChains = make(map[string]struct{})
 	if GetExtraConfig() == nil || len(GetExtraConfig().EnableCheckTxBlockHashChains) == 0 {
@@ -885,6 +1140,26 @@ func IsUseFromChainIDInReceiptDisabled(chainID string) bool {
 	return exist
 }
 
+func initDisableSimulateSwapTxChains() {
+	disableSimulateSwapTxChains = make(map[string]struct{})
+	if GetExtraConfig() == nil || len(GetExtraConfig().DisableSimulateSwapTxChains) == 0 {
+		return
+	}
+	for _, cid := range GetExtraConfig().DisableSimulateSwapTxChains {
+		if _, err := common.GetBigIntFromStr(cid); err != nil {
+			log.Fatal("initDisableSimulateSwapTxChains wrong chainID", "chainID", cid, "err", err)
+		}
+		disableSimulateSwapTxChains[cid] = struct{}{}
+	}
+	log.Info("initDisableSimulateSwapTxChains success")
+}
+
+// IsSimulateSwapTxDisabled is simulate swap tx with eth_call disabled
+func IsSimulateSwapTxDisabled(chainID string) bool {
+	_, exist := disableSimulateSwapTxChains[chainID]
+	return exist
+}
+
 func initUseFastMPCChains() {
 	useFastMPCChains = make(map[string]struct{})
 	if GetExtraConfig() == nil || len(GetExtraConfig().UseFastMPCChains) == 0 {
@@ -919,6 +1194,25 @@ func DontCheckTokenReceived(tokenID string) bool {
 	return exist
 }
 
+// GetGasLimitConfig get gas limit config of the first matched swap type
+// (fallback to the 'default' one)
+func GetGasLimitConfig(chainID string, swapTypes ...string) *GasLimitConfig {
+	serverCfg := GetRouterServerConfig()
+	if serverCfg == nil {
+		return nil
+	}
+	configs, exist := serverCfg.GasLimit[chainID]
+	if !exist {
+		return nil
+	}
+	for _, swapType := range swapTypes {
+		if cfg, exist := configs[swapType]; exist {
+			return cfg
+		}
+	}
+	return configs[DefaultGasLimitSwapType]
+}
+
 // GetDynamicFeeTxConfig get dynamic fee tx config (EIP-1559)
 func GetDynamicFeeTxConfig(chainID string) *DynamicFeeTxConfig {
 	if !IsDynamicFeeTxEnabled(chainID) {
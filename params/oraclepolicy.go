@@ -0,0 +1,194 @@
+package params
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+
+	"github.com/BurntSushi/toml"
+	"github.com/anyswap/CrossChain-Router/v3/common"
+	"github.com/anyswap/CrossChain-Router/v3/log"
+)
+
+// OraclePolicy oracle local policy of accepting sign.
+// it is evaluated by each oracle independently before agreeing to sign,
+// to enforce limits even if the server is compromised.
+type OraclePolicy struct {
+	// disagree swaps from or to these chains
+	PausedChains []string `toml:",omitempty" json:",omitempty"`
+	// disagree swaps to these receivers
+	DenyReceivers []string `toml:",omitempty" json:",omitempty"`
+	// required confirmations of source tx beyond the chain config's
+	ExtraConfirmations map[string]uint64 `toml:",omitempty" json:",omitempty"` // key is chain ID
+	// value limits (unit is 18 decimals)
+	TokenLimits map[string]*AcceptLimitConfig `toml:",omitempty" json:",omitempty"` // key is tokenID
+	RouteLimits map[string]*AcceptLimitConfig `toml:",omitempty" json:",omitempty"` // key is tokenID:fromChainID:toChainID
+
+	pausedChains  map[string]struct{}
+	denyReceivers map[string]struct{}
+}
+
+// AcceptLimitConfig value limit config (unit is 18 decimals)
+type AcceptLimitConfig struct {
+	MaxValue string `toml:",omitempty" json:",omitempty"` // per swap
+	DailyCap string `toml:",omitempty" json:",omitempty"` // total of one UTC day
+
+	maxValue *big.Int
+	dailyCap *big.Int
+}
+
+// GetRouteLimitKey get key of route limit
+func GetRouteLimitKey(tokenID, fromChainID, toChainID string) string {
+	return fmt.Sprintf("%s:%s:%s", tokenID, fromChainID, toChainID)
+}
+
+// LoadOraclePolicy load oracle policy file
+func LoadOraclePolicy(policyFile string) (*OraclePolicy, error) {
+	if !common.FileExist(policyFile) {
+		return nil, fmt.Errorf("oracle policy file '%v' not exist", policyFile)
+	}
+	policy := &OraclePolicy{}
+	if _, err := toml.DecodeFile(policyFile, policy); err != nil {
+		return nil, fmt.Errorf("decode oracle policy file failed: %w", err)
+	}
+	if err := policy.CheckConfig(); err != nil {
+		return nil, fmt.Errorf("check oracle policy failed: %w", err)
+	}
+	log.Info("load oracle policy success", "policyFile", policyFile)
+	return policy, nil
+}
+
+// CheckConfig check oracle policy
+func (p *OraclePolicy) CheckConfig() error {
+	p.pausedChains = make(map[string]struct{}, len(p.PausedChains))
+	for _, chainID := range p.PausedChains {
+		if _, err := common.GetBigIntFromStr(chainID); err != nil || chainID == "" {
+			return fmt.Errorf("wrong paused chain id '%v'", chainID)
+		}
+		p.pausedChains[chainID] = struct{}{}
+	}
+	p.denyReceivers = make(map[string]struct{}, len(p.DenyReceivers))
+	for _, receiver := range p.DenyReceivers {
+		if receiver == "" {
+			return errors.New("empty deny receiver")
+		}
+		p.denyReceivers[strings.ToLower(receiver)] = struct{}{}
+	}
+	for chainID := range p.ExtraConfirmations {
+		if _, err := common.GetBigIntFromStr(chainID); err != nil {
+			return fmt.Errorf("wrong chain id '%v' in 'ExtraConfirmations'", chainID)
+		}
+	}
+	for tokenID, c := range p.TokenLimits {
+		if c == nil {
+			return fmt.Errorf("token %v 'TokenLimits' is empty", tokenID)
+		}
+		if err := c.CheckConfig(); err != nil {
+			return fmt.Errorf("token %v 'TokenLimits': %w", tokenID, err)
+		}
+	}
+	for route, c := range p.RouteLimits {
+		if parts := strings.Split(route, ":"); len(parts) != 3 {
+			return fmt.Errorf("wrong route '%v' in 'RouteLimits', want tokenID:fromChainID:toChainID", route)
+		}
+		if c == nil {
+			return fmt.Errorf("route %v 'RouteLimits' is empty", route)
+		}
+		if err := c.CheckConfig(); err != nil {
+			return fmt.Errorf("route %v 'RouteLimits': %w", route, err)
+		}
+	}
+	return nil
+}
+
+// CheckConfig check accept limit config
+func (c *AcceptLimitConfig) CheckConfig() (err error) {
+	if c.MaxValue != "" {
+		c.maxValue, err = common.GetBigIntFromStr(c.MaxValue)
+		if err != nil || c.maxValue.Sign() < 0 {
+			return fmt.Errorf("wrong 'MaxValue' '%v'", c.MaxValue)
+		}
+	}
+	if c.DailyCap != "" {
+		c.dailyCap, err = common.GetBigIntFromStr(c.DailyCap)
+		if err != nil || c.dailyCap.Sign() < 0 {
+			return fmt.Errorf("wrong 'DailyCap' '%v'", c.DailyCap)
+		}
+	}
+	return nil
+}
+
+// GetMaxValue get max value of one swap (nil means no limit)
+func (c *AcceptLimitConfig) GetMaxValue() *big.Int {
+	return c.maxValue
+}
+
+// GetDailyCap get daily cap (nil means no limit)
+func (c *AcceptLimitConfig) GetDailyCap() *big.Int {
+	return c.dailyCap
+}
+
+// IsChainPaused is chain paused
+func (p *OraclePolicy) IsChainPaused(chainID string) bool {
+	_, exist := p.pausedChains[chainID]
+	return exist
+}
+
+// IsReceiverDenied is receiver denied
+func (p *OraclePolicy) IsReceiverDenied(receiver string) bool {
+	_, exist := p.denyReceivers[strings.ToLower(receiver)]
+	return exist
+}
+
+// GetExtraConfirmations get extra confirmations of chain
+func (p *OraclePolicy) GetExtraConfirmations(chainID string) uint64 {
+	return p.ExtraConfirmations[chainID]
+}
+
+// GetTokenLimit get limit of tokenID
+func (p *OraclePolicy) GetTokenLimit(tokenID string) *AcceptLimitConfig {
+	return p.TokenLimits[tokenID]
+}
+
+// GetRouteLimit get limit of route
+func (p *OraclePolicy) GetRouteLimit(tokenID, fromChainID, toChainID string) *AcceptLimitConfig {
+	return p.RouteLimits[GetRouteLimitKey(tokenID, fromChainID, toChainID)]
+}
+
+// HasDailyCap has any daily cap
+func (p *OraclePolicy) HasDailyCap() bool {
+	for _, c := range p.TokenLimits {
+		if c.dailyCap != nil {
+			return true
+		}
+	}
+	for _, c := range p.RouteLimits {
+		if c.dailyCap != nil {
+			return true
+		}
+	}
+	return false
+}
+
+// GetOraclePolicy get oracle policy (nil if not configed)
+func GetOraclePolicy() *OraclePolicy {
+	if routerConfig.Oracle == nil {
+		return nil
+	}
+	return routerConfig.Oracle.policy
+}
+
+// SetOraclePolicy set oracle policy (used by testing)
+func SetOraclePolicy(policy *OraclePolicy) error {
+	if policy != nil {
+		if err := policy.CheckConfig(); err != nil {
+			return err
+		}
+	}
+	if routerConfig.Oracle == nil {
+		routerConfig.Oracle = &RouterOracleConfig{}
+	}
+	routerConfig.Oracle.policy = policy
+	return nil
+}
@@ -0,0 +1,225 @@
+package bridge
+
+import (
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"strings"
+	"sync"
+
+	"github.com/anyswap/CrossChain-Router/v3/log"
+	"github.com/anyswap/CrossChain-Router/v3/mongodb"
+	"github.com/anyswap/CrossChain-Router/v3/router"
+	"github.com/anyswap/CrossChain-Router/v3/tokens"
+)
+
+// DryRunReloadRouterConfig compute what a reload would change without applying it
+func DryRunReloadRouterConfig() (*router.ConfigDiff, error) {
+	reloadRouterConfigLock.Lock()
+	defer reloadRouterConfigLock.Unlock()
+
+	reader, err := router.NewConfigReader()
+	if err != nil {
+		log.Warn("[dryrun] new config reader failed", "err", err)
+		return nil, err
+	}
+	newSnapshot, err := router.FetchConfigSnapshot(reader)
+	if err != nil {
+		log.Warn("[dryrun] fetch config snapshot failed", "err", err)
+		return nil, err
+	}
+	oldSnapshot := router.GetCurrentConfigSnapshot()
+	diff := router.CompareConfigSnapshots(oldSnapshot, newSnapshot)
+	log.Info("[dryrun] reload router config", "changes", len(diff.Changes),
+		"addedChainIDs", diff.AddedChainIDs, "removedChainIDs", diff.RemovedChainIDs,
+		"addedTokenIDs", diff.AddedTokenIDs, "removedTokenIDs", diff.RemovedTokenIDs)
+	return diff, nil
+}
+
+// log the diff of reload and save the new snapshot to mongodb (if has)
+func logAndSaveConfigDiff(oldSnapshot, newSnapshot *router.ConfigSnapshot) {
+	diff := router.CompareConfigSnapshots(oldSnapshot, newSnapshot)
+	log.Info("[reload] router config diff", "changes", len(diff.Changes),
+		"addedChainIDs", diff.AddedChainIDs, "removedChainIDs", diff.RemovedChainIDs,
+		"addedTokenIDs", diff.AddedTokenIDs, "removedTokenIDs", diff.RemovedTokenIDs)
+	for _, change := range diff.Changes {
+		log.Info("[reload] router config changed", "item", change.Item, "key", change.Key,
+			"field", change.Field, "old", change.Old, "new", change.New)
+	}
+
+	if !mongodb.HasClient() {
+		return
+	}
+	config, err := json.Marshal(newSnapshot)
+	if err != nil {
+		log.Warn("[reload] marshal config snapshot failed", "err", err)
+		return
+	}
+	diffData, err := json.Marshal(diff)
+	if err != nil {
+		log.Warn("[reload] marshal config diff failed", "err", err)
+		return
+	}
+	_ = mongodb.AddConfigSnapshot(&mongodb.MgoConfigSnapshot{
+		Timestamp: newSnapshot.Timestamp,
+		Config:    string(config),
+		Diff:      string(diffData),
+	})
+}
+
+// GetConfigSnapshot get config snapshot saved in mongodb by key
+func GetConfigSnapshot(key string) (*router.ConfigSnapshot, error) {
+	if !mongodb.HasClient() {
+		return nil, fmt.Errorf("config snapshots are not saved without mongodb")
+	}
+	cs, err := mongodb.FindConfigSnapshot(key)
+	if err != nil {
+		return nil, err
+	}
+	snapshot := &router.ConfigSnapshot{}
+	err = json.Unmarshal([]byte(cs.Config), snapshot)
+	if err != nil {
+		return nil, err
+	}
+	return snapshot, nil
+}
+
+func checkConfigSnapshot(snapshot *router.ConfigSnapshot) error {
+	for _, chainID := range snapshot.ChainIDs {
+		chainCfg := snapshot.Chains[chainID]
+		if chainCfg == nil || chainCfg.ChainID != chainID {
+			return fmt.Errorf("chain config of %v mismatch", chainID)
+		}
+		if err := chainCfg.CheckConfig(); err != nil {
+			return fmt.Errorf("check chain config of %v failed: %w", chainID, err)
+		}
+	}
+	for tokenID, m := range snapshot.Tokens {
+		for chainID, tokenCfg := range m {
+			if tokenCfg.TokenID != tokenID {
+				return fmt.Errorf("token config of %v on %v mismatch", tokenID, chainID)
+			}
+			if err := tokenCfg.CheckConfig(); err != nil {
+				return fmt.Errorf("check token config of %v on %v failed: %w", tokenID, chainID, err)
+			}
+		}
+	}
+	for key, swapCfg := range snapshot.SwapConfigs {
+		if err := swapCfg.CheckConfig(); err != nil {
+			return fmt.Errorf("check swap config of %v failed: %w", key, err)
+		}
+	}
+	for key, feeCfg := range snapshot.FeeConfigs {
+		if err := feeCfg.CheckConfig(); err != nil {
+			return fmt.Errorf("check fee config of %v failed: %w", key, err)
+		}
+	}
+	return nil
+}
+
+// store config of key 'tokenID:fromChainID:toChainID' into nested maps
+func storeSnapshotConfig(m *sync.Map, key string, cfg interface{}) {
+	parts := strings.Split(key, ":")
+	if len(parts) != 3 {
+		return
+	}
+	tmap, _ := m.LoadOrStore(parts[0], new(sync.Map))
+	fmap, _ := tmap.(*sync.Map).LoadOrStore(parts[1], new(sync.Map))
+	fmap.(*sync.Map).Store(parts[2], cfg)
+}
+
+// ApplyConfigSnapshot apply chain and token configs in snapshot (rollback)
+// the applied configs will be overwritten by the next reload from config source
+//nolint:funlen // ok
+func ApplyConfigSnapshot(snapshot *router.ConfigSnapshot) error {
+	if err := checkConfigSnapshot(snapshot); err != nil {
+		log.Warn("[apply] check config snapshot failed", "err", err)
+		return err
+	}
+
+	log.Info("[apply] start apply config snapshot", "timestamp", snapshot.Timestamp)
+	reloadRouterConfigLock.Lock()
+	router.IsReloading = true
+	defer func() {
+		router.IsReloading = false
+		routerInfoIsLoaded = new(sync.Map)
+		reloadRouterConfigLock.Unlock()
+	}()
+
+	oldSnapshot := router.GetCurrentConfigSnapshot()
+
+	chainIDs := make([]*big.Int, 0, len(snapshot.ChainIDs))
+	for _, chainID := range snapshot.ChainIDs {
+		bigChainID, ok := new(big.Int).SetString(chainID, 10)
+		if !ok {
+			return fmt.Errorf("wrong chain ID '%v'", chainID)
+		}
+		chainIDs = append(chainIDs, bigChainID)
+	}
+
+	for _, chainID := range chainIDs {
+		isNewBridge := false
+		bridge := router.GetBridgeByChainID(chainID.String())
+		if bridge == nil {
+			log.Info("[apply] add new bridge", "chainID", chainID)
+			bridge = NewCrossChainBridge(chainID)
+			InitGatewayConfig(bridge, chainID)
+			isNewBridge = true
+		}
+
+		chainCfg := snapshot.Chains[chainID.String()]
+		bridge.SetChainConfig(chainCfg)
+		initRouterInfo(bridge, chainID.String(), chainCfg.RouterContract)
+
+		if isNewBridge {
+			bridge.InitAfterConfig()
+			router.SetBridge(chainID.String(), bridge)
+		}
+
+		for _, tokenID := range snapshot.TokenIDs {
+			tokenCfg := snapshot.Tokens[tokenID][chainID.String()]
+			if tokenCfg == nil {
+				continue
+			}
+			bridge.SetTokenConfig(tokenCfg.ContractAddress, tokenCfg)
+			router.SetMultichainToken(tokenID, chainID.String(), tokenCfg.ContractAddress)
+			initRouterInfo(bridge, chainID.String(), tokenCfg.RouterContract)
+		}
+	}
+
+	oldChainIDs := router.AllChainIDs
+	router.AllChainIDs = chainIDs
+
+	oldTokenIDs := router.AllTokenIDs
+	router.AllTokenIDs = snapshot.TokenIDs
+
+	if tokens.IsERC20Router() {
+		swapConfigs := new(sync.Map)
+		for key, swapCfg := range snapshot.SwapConfigs {
+			storeSnapshotConfig(swapConfigs, key, swapCfg)
+		}
+		feeConfigs := new(sync.Map)
+		for key, feeCfg := range snapshot.FeeConfigs {
+			storeSnapshotConfig(feeConfigs, key, feeCfg)
+		}
+		tokens.SetSwapConfigs(swapConfigs)
+		tokens.SetFeeConfigs(feeConfigs)
+	}
+
+	removeObsoleteConfigs(oldChainIDs, chainIDs, oldTokenIDs, snapshot.TokenIDs)
+
+	logAndSaveConfigDiff(oldSnapshot, router.GetCurrentConfigSnapshot())
+	log.Info("[apply] apply config snapshot success", "timestamp", snapshot.Timestamp)
+	return nil
+}
+
+func initRouterInfo(bridge tokens.IBridge, chainID, routerContract string) {
+	if routerContract == "" || isRouterInfoLoaded(chainID, routerContract) {
+		return
+	}
+	if err := bridge.InitRouterInfo(routerContract); err != nil {
+		log.Warn("[apply] init router info failed", "chainID", chainID, "routerContract", routerContract, "err", err)
+		return
+	}
+	setRouterInfoLoaded(chainID, routerContract)
+}
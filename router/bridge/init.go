@@ -9,6 +9,7 @@ import (
 	"time"
 
 	"github.com/anyswap/CrossChain-Router/v3/log"
+	"github.com/anyswap/CrossChain-Router/v3/mongodb"
 	"github.com/anyswap/CrossChain-Router/v3/mpc"
 	"github.com/anyswap/CrossChain-Router/v3/params"
 	"github.com/anyswap/CrossChain-Router/v3/router"
@@ -128,6 +129,9 @@ func InitRouterBridges(isServer bool) {
 
 	loadSwapAndFeeConfigs()
 
+	if isServer && mongodb.HasClient() {
+		mpc.SetSignGroupStatStore(signGroupStatStore{})
+	}
 	mpc.Init(isServer)
 
 	success = true
@@ -29,7 +29,7 @@ func StartReloadRouterConfigTask() {
 }
 
 func doReloadRouterConfigPeriodly() {
-	reloadCycle := params.GetRouterConfig().Onchain.ReloadCycle
+	reloadCycle := params.GetConfigReloadCycle()
 	if reloadCycle == 0 {
 		return
 	}
@@ -79,9 +79,19 @@ func ReloadRouterConfig() (success bool) {
 		reloadRouterConfigLock.Unlock()
 	}()
 
+	oldSnapshot := router.GetCurrentConfigSnapshot()
+
 	// reload local config
 	params.ReloadRouterConfig()
 
+	// reload chain and token configs from local file
+	if params.IsFileConfigSource() {
+		if err := router.LoadFileRouterConfig(); err != nil {
+			log.Error("[reload] load file router config failed", "err", err)
+			return false
+		}
+	}
+
 	allChainIDs, err := router.GetAllChainIDs()
 	if err != nil {
 		log.Error("[reload] call GetAllChainIDs failed", "err", err)
@@ -168,6 +178,16 @@ func ReloadRouterConfig() (success bool) {
 
 	loadSwapAndFeeConfigs()
 
+	removeObsoleteConfigs(oldChainIDs, chainIDs, oldTokenIDs, tokenIDs)
+
+	logAndSaveConfigDiff(oldSnapshot, router.GetCurrentConfigSnapshot())
+
+	success = true
+	return success
+}
+
+// get rid of configs of chainIDs and tokenIDs which are removed
+func removeObsoleteConfigs(oldChainIDs, chainIDs []*big.Int, oldTokenIDs, tokenIDs []string) {
 	removedChainIDs := make([]string, 0)
 	for _, chainID := range oldChainIDs {
 		exist := false
@@ -227,7 +247,4 @@ func ReloadRouterConfig() (success bool) {
 	for _, chainID := range removedChainIDs {
 		router.SetBridge(chainID, nil)
 	}
-
-	success = true
-	return success
 }
@@ -0,0 +1,25 @@
+package bridge
+
+import (
+	"github.com/anyswap/CrossChain-Router/v3/mongodb"
+	"github.com/anyswap/CrossChain-Router/v3/mpc"
+)
+
+// mongodb store of mpc sign group stats
+type signGroupStatStore struct{}
+
+func (s signGroupStatStore) FindSignGroupStats(isFastMPC bool) ([]*mpc.SignGroupStat, error) {
+	stats, err := mongodb.FindSignGroupStats(isFastMPC)
+	if err != nil {
+		return nil, err
+	}
+	result := make([]*mpc.SignGroupStat, len(stats))
+	for i, stat := range stats {
+		result[i] = (*mpc.SignGroupStat)(stat)
+	}
+	return result, nil
+}
+
+func (s signGroupStatStore) UpdateSignGroupStat(stat *mpc.SignGroupStat) error {
+	return mongodb.UpdateSignGroupStat((*mongodb.MgoSignGroupStat)(stat))
+}
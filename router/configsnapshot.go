@@ -0,0 +1,522 @@
+package router
+
+import (
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/anyswap/CrossChain-Router/v3/common"
+	"github.com/anyswap/CrossChain-Router/v3/params"
+	"github.com/anyswap/CrossChain-Router/v3/tokens"
+)
+
+// config item types in config diff
+const (
+	ConfigItemChain = "chain"
+	ConfigItemToken = "token"
+	ConfigItemSwap  = "swap"
+	ConfigItemFee   = "fee"
+)
+
+// ConfigReader read chain and token configs from config source
+type ConfigReader interface {
+	GetAllChainIDs() ([]*big.Int, error)
+	GetAllTokenIDs() ([]string, error)
+	GetChainConfig(chainID *big.Int) (*tokens.ChainConfig, error)
+	GetTokenConfig(chainID *big.Int, tokenID string) (*tokens.TokenConfig, error)
+	GetMultichainToken(tokenID string, chainID *big.Int) (string, error)
+	GetActualSwapConfig(tokenID string, fromChainID, toChainID *big.Int) (*tokens.SwapConfig, error)
+	GetActualFeeConfig(tokenID string, fromChainID, toChainID *big.Int) (*tokens.FeeConfig, error)
+}
+
+// onchainConfigReader read configs from onchain config contract
+type onchainConfigReader struct{}
+
+func (r onchainConfigReader) GetAllChainIDs() ([]*big.Int, error) { return GetAllChainIDs() }
+func (r onchainConfigReader) GetAllTokenIDs() ([]string, error)   { return GetAllTokenIDs() }
+
+func (r onchainConfigReader) GetChainConfig(chainID *big.Int) (*tokens.ChainConfig, error) {
+	return GetChainConfig(chainID)
+}
+
+func (r onchainConfigReader) GetTokenConfig(chainID *big.Int, tokenID string) (*tokens.TokenConfig, error) {
+	return GetTokenConfig(chainID, tokenID)
+}
+
+func (r onchainConfigReader) GetMultichainToken(tokenID string, chainID *big.Int) (string, error) {
+	return GetMultichainToken(tokenID, chainID)
+}
+
+func (r onchainConfigReader) GetActualSwapConfig(tokenID string, fromChainID, toChainID *big.Int) (*tokens.SwapConfig, error) {
+	return GetActualSwapConfig(tokenID, fromChainID, toChainID)
+}
+
+func (r onchainConfigReader) GetActualFeeConfig(tokenID string, fromChainID, toChainID *big.Int) (*tokens.FeeConfig, error) {
+	return GetActualFeeConfig(tokenID, fromChainID, toChainID)
+}
+
+// fileConfigReader read configs from file router config which is not applied
+type fileConfigReader struct {
+	config *FileRouterConfig
+}
+
+func (r fileConfigReader) GetAllChainIDs() ([]*big.Int, error) {
+	chainIDs := make([]*big.Int, 0, len(r.config.Chains))
+	for chainID := range r.config.Chains {
+		bi, err := common.GetBigIntFromStr(chainID)
+		if err != nil {
+			return nil, err
+		}
+		chainIDs = append(chainIDs, bi)
+	}
+	sort.Slice(chainIDs, func(i, j int) bool {
+		return chainIDs[i].Cmp(chainIDs[j]) < 0
+	})
+	return chainIDs, nil
+}
+
+func (r fileConfigReader) GetAllTokenIDs() ([]string, error) {
+	tokenIDs := make([]string, 0, len(r.config.Tokens))
+	for tokenID := range r.config.Tokens {
+		tokenIDs = append(tokenIDs, tokenID)
+	}
+	sort.Strings(tokenIDs)
+	return tokenIDs, nil
+}
+
+func (r fileConfigReader) GetChainConfig(chainID *big.Int) (*tokens.ChainConfig, error) {
+	chainCfg, exist := r.config.Chains[chainID.String()]
+	if !exist {
+		return nil, fmt.Errorf("chain config of %v not found", chainID)
+	}
+	cfg := *chainCfg // copy
+	return &cfg, nil
+}
+
+func (r fileConfigReader) GetTokenConfig(chainID *big.Int, tokenID string) (*tokens.TokenConfig, error) {
+	tokenCfg, exist := r.config.Tokens[tokenID][chainID.String()]
+	if !exist {
+		return nil, nil
+	}
+	cfg := *tokenCfg // copy
+	return &cfg, nil
+}
+
+func (r fileConfigReader) GetMultichainToken(tokenID string, chainID *big.Int) (string, error) {
+	if tokenCfg, exist := r.config.Tokens[tokenID][chainID.String()]; exist {
+		return tokenCfg.ContractAddress, nil
+	}
+	return "", nil
+}
+
+func (r fileConfigReader) GetActualSwapConfig(tokenID string, fromChainID, toChainID *big.Int) (*tokens.SwapConfig, error) {
+	for _, keys := range getConfigLookupKeys(fromChainID, toChainID, true) {
+		if swapCfg, exist := r.config.SwapConfigs[tokenID][keys[0]][keys[1]]; exist {
+			cfg := *swapCfg // copy
+			return &cfg, nil
+		}
+	}
+	return nil, fmt.Errorf("swap config of %v from %v to %v not found", tokenID, fromChainID, toChainID)
+}
+
+func (r fileConfigReader) GetActualFeeConfig(tokenID string, fromChainID, toChainID *big.Int) (*tokens.FeeConfig, error) {
+	for _, keys := range getConfigLookupKeys(fromChainID, toChainID, true) {
+		if feeCfg, exist := r.config.FeeConfigs[tokenID][keys[0]][keys[1]]; exist {
+			cfg := *feeCfg // copy
+			return &cfg, nil
+		}
+	}
+	return nil, fmt.Errorf("fee config of %v from %v to %v not found", tokenID, fromChainID, toChainID)
+}
+
+// NewConfigReader new config reader of the latest configs in config source
+// the local config file is read again but not applied
+func NewConfigReader() (ConfigReader, error) {
+	if params.IsFileConfigSource() {
+		config, err := ReadFileRouterConfig(params.GetFileConfigPath())
+		if err != nil {
+			return nil, err
+		}
+		return fileConfigReader{config: config}, nil
+	}
+	return onchainConfigReader{}, nil
+}
+
+// ConfigSnapshot snapshot of chain and token configs
+type ConfigSnapshot struct {
+	Timestamp   int64
+	ChainIDs    []string
+	TokenIDs    []string
+	Chains      map[string]*tokens.ChainConfig            // key is chainID
+	Tokens      map[string]map[string]*tokens.TokenConfig // tokenID -> chainID -> config
+	SwapConfigs map[string]*tokens.SwapConfig             // key is tokenID:fromChainID:toChainID
+	FeeConfigs  map[string]*tokens.FeeConfig              // key is tokenID:fromChainID:toChainID
+}
+
+func newConfigSnapshot() *ConfigSnapshot {
+	return &ConfigSnapshot{
+		Timestamp:   time.Now().Unix(),
+		Chains:      make(map[string]*tokens.ChainConfig),
+		Tokens:      make(map[string]map[string]*tokens.TokenConfig),
+		SwapConfigs: make(map[string]*tokens.SwapConfig),
+		FeeConfigs:  make(map[string]*tokens.FeeConfig),
+	}
+}
+
+func getSwapConfigKey(tokenID, fromChainID, toChainID string) string {
+	return fmt.Sprintf("%v:%v:%v", tokenID, fromChainID, toChainID)
+}
+
+func (s *ConfigSnapshot) setTokenConfig(tokenID, chainID string, tokenCfg *tokens.TokenConfig) {
+	m, exist := s.Tokens[tokenID]
+	if !exist {
+		m = make(map[string]*tokens.TokenConfig)
+		s.Tokens[tokenID] = m
+	}
+	m[chainID] = tokenCfg
+}
+
+// supported chains of tokenID which have multichain token
+func (s *ConfigSnapshot) getSupportChainIDs(tokenID string) []string {
+	supportChainIDs := make([]string, 0, len(s.Tokens[tokenID]))
+	for _, chainID := range s.ChainIDs {
+		if _, exist := s.Tokens[tokenID][chainID]; exist {
+			supportChainIDs = append(supportChainIDs, chainID)
+		}
+	}
+	return supportChainIDs
+}
+
+// GetCurrentConfigSnapshot get snapshot of configs in use
+func GetCurrentConfigSnapshot() *ConfigSnapshot {
+	snapshot := newConfigSnapshot()
+	for _, chainID := range AllChainIDs {
+		snapshot.ChainIDs = append(snapshot.ChainIDs, chainID.String())
+	}
+	snapshot.TokenIDs = append(snapshot.TokenIDs, AllTokenIDs...)
+	for _, chainID := range snapshot.ChainIDs {
+		bridge := GetBridgeByChainID(chainID)
+		if bridge == nil {
+			continue
+		}
+		snapshot.Chains[chainID] = bridge.GetChainConfig()
+		for _, tokenID := range snapshot.TokenIDs {
+			tokenAddr := GetCachedMultichainToken(tokenID, chainID)
+			if tokenAddr == "" {
+				continue
+			}
+			if tokenCfg := bridge.GetTokenConfig(tokenAddr); tokenCfg != nil {
+				snapshot.setTokenConfig(tokenID, chainID, tokenCfg)
+			}
+		}
+	}
+	if !tokens.IsERC20Router() {
+		return snapshot
+	}
+	for _, tokenID := range snapshot.TokenIDs {
+		supportChainIDs := snapshot.getSupportChainIDs(tokenID)
+		for _, fromChainID := range supportChainIDs {
+			for _, toChainID := range supportChainIDs {
+				if fromChainID == toChainID {
+					continue
+				}
+				key := getSwapConfigKey(tokenID, fromChainID, toChainID)
+				if swapCfg := tokens.GetSwapConfig(tokenID, fromChainID, toChainID); swapCfg != nil {
+					snapshot.SwapConfigs[key] = swapCfg
+				}
+				if feeCfg := tokens.GetFeeConfig(tokenID, fromChainID, toChainID); feeCfg != nil {
+					snapshot.FeeConfigs[key] = feeCfg
+				}
+			}
+		}
+	}
+	return snapshot
+}
+
+// Hash hash of configs in snapshot (timestamp excluded)
+func (s *ConfigSnapshot) Hash() string {
+	snapshot := *s
+	snapshot.Timestamp = 0
+	data, err := json.Marshal(&snapshot)
+	if err != nil {
+		return ""
+	}
+	return common.Keccak256Hash(data).String()
+}
+
+// FetchConfigSnapshot fetch snapshot of configs from config source (without applying)
+//nolint:funlen,gocyclo // ok
+func FetchConfigSnapshot(reader ConfigReader) (*ConfigSnapshot, error) {
+	snapshot := newConfigSnapshot()
+
+	allChainIDs, err := reader.GetAllChainIDs()
+	if err != nil {
+		return nil, err
+	}
+	for _, chainID := range allChainIDs {
+		if params.IsChainIDInBlackList(chainID.String()) {
+			continue
+		}
+		snapshot.ChainIDs = append(snapshot.ChainIDs, chainID.String())
+	}
+
+	allTokenIDs, err := reader.GetAllTokenIDs()
+	if err != nil {
+		return nil, err
+	}
+	for _, tokenID := range allTokenIDs {
+		if params.IsTokenIDInBlackList(tokenID) {
+			continue
+		}
+		snapshot.TokenIDs = append(snapshot.TokenIDs, tokenID)
+	}
+
+	bigChainIDs := make(map[string]*big.Int, len(snapshot.ChainIDs))
+	for _, chainID := range snapshot.ChainIDs {
+		bigChainID, _ := new(big.Int).SetString(chainID, 10)
+		bigChainIDs[chainID] = bigChainID
+
+		chainCfg, errf := reader.GetChainConfig(bigChainID)
+		if errf != nil {
+			return nil, fmt.Errorf("get chain config of %v failed: %w", chainID, errf)
+		}
+		snapshot.Chains[chainID] = chainCfg
+
+		for _, tokenID := range snapshot.TokenIDs {
+			tokenAddr, errf := reader.GetMultichainToken(tokenID, bigChainID)
+			if errf != nil {
+				return nil, fmt.Errorf("get multichain token of %v on %v failed: %w", tokenID, chainID, errf)
+			}
+			if tokenAddr == "" {
+				continue
+			}
+			tokenCfg, errf := reader.GetTokenConfig(bigChainID, tokenID)
+			if errf != nil {
+				return nil, fmt.Errorf("get token config of %v on %v failed: %w", tokenID, chainID, errf)
+			}
+			if tokenCfg != nil {
+				snapshot.setTokenConfig(tokenID, chainID, tokenCfg)
+			}
+		}
+	}
+	if !tokens.IsERC20Router() {
+		return snapshot, nil
+	}
+	for _, tokenID := range snapshot.TokenIDs {
+		supportChainIDs := snapshot.getSupportChainIDs(tokenID)
+		for _, fromChainID := range supportChainIDs {
+			for _, toChainID := range supportChainIDs {
+				if fromChainID == toChainID {
+					continue
+				}
+				key := getSwapConfigKey(tokenID, fromChainID, toChainID)
+				swapCfg, errf := reader.GetActualSwapConfig(tokenID, bigChainIDs[fromChainID], bigChainIDs[toChainID])
+				if errf == nil {
+					snapshot.SwapConfigs[key] = swapCfg
+				}
+				feeCfg, errf := reader.GetActualFeeConfig(tokenID, bigChainIDs[fromChainID], bigChainIDs[toChainID])
+				if errf == nil {
+					snapshot.FeeConfigs[key] = feeCfg
+				}
+			}
+		}
+	}
+	return snapshot, nil
+}
+
+// ConfigChange changed field of config item
+type ConfigChange struct {
+	Item  string // chain, token, swap, fee
+	Key   string // chainID, tokenID:chainID, tokenID:fromChainID:toChainID
+	Field string
+	Old   string
+	New   string
+}
+
+// ConfigDiff difference between two config snapshots
+type ConfigDiff struct {
+	AddedChainIDs   []string        `json:",omitempty"`
+	RemovedChainIDs []string        `json:",omitempty"`
+	AddedTokenIDs   []string        `json:",omitempty"`
+	RemovedTokenIDs []string        `json:",omitempty"`
+	Changes         []*ConfigChange `json:",omitempty"`
+}
+
+// IsEmpty is nothing changed
+func (d *ConfigDiff) IsEmpty() bool {
+	return len(d.AddedChainIDs) == 0 && len(d.RemovedChainIDs) == 0 &&
+		len(d.AddedTokenIDs) == 0 && len(d.RemovedTokenIDs) == 0 &&
+		len(d.Changes) == 0
+}
+
+// CompareConfigSnapshots compare config snapshots
+func CompareConfigSnapshots(oldSnapshot, newSnapshot *ConfigSnapshot) *ConfigDiff {
+	diff := &ConfigDiff{}
+	diff.AddedChainIDs, diff.RemovedChainIDs = compareStringSlices(oldSnapshot.ChainIDs, newSnapshot.ChainIDs)
+	diff.AddedTokenIDs, diff.RemovedTokenIDs = compareStringSlices(oldSnapshot.TokenIDs, newSnapshot.TokenIDs)
+
+	for _, chainID := range unionKeys(oldSnapshot.Chains, newSnapshot.Chains) {
+		diff.addChanges(ConfigItemChain, chainID,
+			chainConfigFields(oldSnapshot.Chains[chainID]),
+			chainConfigFields(newSnapshot.Chains[chainID]))
+	}
+
+	tokenKeys := make(map[string]struct{})
+	for _, snapshot := range []*ConfigSnapshot{oldSnapshot, newSnapshot} {
+		for tokenID, m := range snapshot.Tokens {
+			for chainID := range m {
+				tokenKeys[tokenID+":"+chainID] = struct{}{}
+			}
+		}
+	}
+	for _, key := range sortedKeys(tokenKeys) {
+		parts := strings.SplitN(key, ":", 2)
+		tokenID, chainID := parts[0], parts[1]
+		diff.addChanges(ConfigItemToken, key,
+			tokenConfigFields(oldSnapshot.Tokens[tokenID][chainID]),
+			tokenConfigFields(newSnapshot.Tokens[tokenID][chainID]))
+	}
+
+	for _, key := range unionKeys(oldSnapshot.SwapConfigs, newSnapshot.SwapConfigs) {
+		diff.addChanges(ConfigItemSwap, key,
+			swapConfigFields(oldSnapshot.SwapConfigs[key]),
+			swapConfigFields(newSnapshot.SwapConfigs[key]))
+	}
+
+	for _, key := range unionKeys(oldSnapshot.FeeConfigs, newSnapshot.FeeConfigs) {
+		diff.addChanges(ConfigItemFee, key,
+			feeConfigFields(oldSnapshot.FeeConfigs[key]),
+			feeConfigFields(newSnapshot.FeeConfigs[key]))
+	}
+	return diff
+}
+
+func (d *ConfigDiff) addChanges(item, key string, oldFields, newFields [][2]string) {
+	for i, field := range newFields {
+		if oldFields[i][1] != field[1] {
+			d.Changes = append(d.Changes, &ConfigChange{
+				Item:  item,
+				Key:   key,
+				Field: field[0],
+				Old:   oldFields[i][1],
+				New:   field[1],
+			})
+		}
+	}
+}
+
+func bigIntString(bi *big.Int) string {
+	if bi == nil {
+		return ""
+	}
+	return bi.String()
+}
+
+// fields of nil config are all empty
+func blankFields(fields [][2]string) [][2]string {
+	for i := range fields {
+		fields[i][1] = ""
+	}
+	return fields
+}
+
+func chainConfigFields(c *tokens.ChainConfig) [][2]string {
+	if c == nil {
+		return blankFields(chainConfigFields(&tokens.ChainConfig{}))
+	}
+	return [][2]string{
+		{"BlockChain", c.BlockChain},
+		{"RouterContract", c.RouterContract},
+		{"Confirmations", fmt.Sprint(c.Confirmations)},
+		{"InitialHeight", fmt.Sprint(c.InitialHeight)},
+		{"Extra", c.Extra},
+	}
+}
+
+func tokenConfigFields(c *tokens.TokenConfig) [][2]string {
+	if c == nil {
+		return blankFields(tokenConfigFields(&tokens.TokenConfig{}))
+	}
+	return [][2]string{
+		{"ContractAddress", c.ContractAddress},
+		{"Decimals", fmt.Sprint(c.Decimals)},
+		{"ContractVersion", fmt.Sprint(c.ContractVersion)},
+		{"RouterContract", c.RouterContract},
+		{"Extra", c.Extra},
+	}
+}
+
+func swapConfigFields(c *tokens.SwapConfig) [][2]string {
+	if c == nil {
+		return blankFields(swapConfigFields(&tokens.SwapConfig{}))
+	}
+	return [][2]string{
+		{"MaximumSwap", bigIntString(c.MaximumSwap)},
+		{"MinimumSwap", bigIntString(c.MinimumSwap)},
+		{"BigValueThreshold", bigIntString(c.BigValueThreshold)},
+	}
+}
+
+func feeConfigFields(c *tokens.FeeConfig) [][2]string {
+	if c == nil {
+		return blankFields(feeConfigFields(&tokens.FeeConfig{}))
+	}
+	return [][2]string{
+		{"SwapFeeRatePerMillion", fmt.Sprint(c.SwapFeeRatePerMillion)},
+		{"MaximumSwapFee", bigIntString(c.MaximumSwapFee)},
+		{"MinimumSwapFee", bigIntString(c.MinimumSwapFee)},
+	}
+}
+
+func compareStringSlices(oldSlice, newSlice []string) (added, removed []string) {
+	oldSet := make(map[string]struct{}, len(oldSlice))
+	for _, s := range oldSlice {
+		oldSet[s] = struct{}{}
+	}
+	newSet := make(map[string]struct{}, len(newSlice))
+	for _, s := range newSlice {
+		newSet[s] = struct{}{}
+		if _, exist := oldSet[s]; !exist {
+			added = append(added, s)
+		}
+	}
+	for _, s := range oldSlice {
+		if _, exist := newSet[s]; !exist {
+			removed = append(removed, s)
+		}
+	}
+	return added, removed
+}
+
+func unionKeys(maps ...interface{}) []string {
+	keys := make(map[string]struct{})
+	for _, m := range maps {
+		switch mm := m.(type) {
+		case map[string]*tokens.ChainConfig:
+			for k := range mm {
+				keys[k] = struct{}{}
+			}
+		case map[string]*tokens.SwapConfig:
+			for k := range mm {
+				keys[k] = struct{}{}
+			}
+		case map[string]*tokens.FeeConfig:
+			for k := range mm {
+				keys[k] = struct{}{}
+			}
+		}
+	}
+	return sortedKeys(keys)
+}
+
+func sortedKeys(m map[string]struct{}) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,61 @@
+package router
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/anyswap/CrossChain-Router/v3/tokens"
+)
+
+func TestCompareConfigSnapshots(t *testing.T) {
+	oldSnapshot := newConfigSnapshot()
+	oldSnapshot.ChainIDs = []string{"1", "56"}
+	oldSnapshot.TokenIDs = []string{"USDC"}
+	oldSnapshot.Chains["1"] = &tokens.ChainConfig{ChainID: "1", RouterContract: "0x1111", Confirmations: 12}
+	oldSnapshot.Chains["56"] = &tokens.ChainConfig{ChainID: "56", RouterContract: "0x5656", Confirmations: 15}
+	oldSnapshot.setTokenConfig("USDC", "1", &tokens.TokenConfig{TokenID: "USDC", Decimals: 6, ContractAddress: "0xaaaa"})
+	oldSnapshot.FeeConfigs["USDC:1:56"] = &tokens.FeeConfig{SwapFeeRatePerMillion: 1000, MaximumSwapFee: big.NewInt(10), MinimumSwapFee: big.NewInt(1)}
+
+	newSnapshot := newConfigSnapshot()
+	newSnapshot.ChainIDs = []string{"1", "250"}
+	newSnapshot.TokenIDs = []string{"USDC"}
+	newSnapshot.Chains["1"] = &tokens.ChainConfig{ChainID: "1", RouterContract: "0x2222", Confirmations: 12}
+	newSnapshot.Chains["250"] = &tokens.ChainConfig{ChainID: "250", RouterContract: "0x2500", Confirmations: 5}
+	newSnapshot.setTokenConfig("USDC", "1", &tokens.TokenConfig{TokenID: "USDC", Decimals: 18, ContractAddress: "0xaaaa"})
+	newSnapshot.FeeConfigs["USDC:1:56"] = &tokens.FeeConfig{SwapFeeRatePerMillion: 2000, MaximumSwapFee: big.NewInt(10), MinimumSwapFee: big.NewInt(1)}
+
+	diff := CompareConfigSnapshots(oldSnapshot, newSnapshot)
+	if len(diff.AddedChainIDs) != 1 || diff.AddedChainIDs[0] != "250" {
+		t.Errorf("wrong added chainIDs %v", diff.AddedChainIDs)
+	}
+	if len(diff.RemovedChainIDs) != 1 || diff.RemovedChainIDs[0] != "56" {
+		t.Errorf("wrong removed chainIDs %v", diff.RemovedChainIDs)
+	}
+	if len(diff.AddedTokenIDs) != 0 || len(diff.RemovedTokenIDs) != 0 {
+		t.Errorf("wrong token IDs diff %v %v", diff.AddedTokenIDs, diff.RemovedTokenIDs)
+	}
+
+	expects := map[string][2]string{
+		"chain:1:RouterContract":              {"0x1111", "0x2222"},
+		"chain:250:RouterContract":            {"", "0x2500"},
+		"chain:56:RouterContract":             {"0x5656", ""},
+		"token:USDC:1:Decimals":               {"6", "18"},
+		"fee:USDC:1:56:SwapFeeRatePerMillion": {"1000", "2000"},
+	}
+	changes := make(map[string][2]string)
+	for _, change := range diff.Changes {
+		changes[change.Item+":"+change.Key+":"+change.Field] = [2]string{change.Old, change.New}
+	}
+	for key, expect := range expects {
+		if changes[key] != expect {
+			t.Errorf("change of %v mismatch, have %v want %v", key, changes[key], expect)
+		}
+	}
+	if _, exist := changes["chain:1:Confirmations"]; exist {
+		t.Errorf("unchanged field is reported")
+	}
+
+	if !CompareConfigSnapshots(newSnapshot, newSnapshot).IsEmpty() {
+		t.Errorf("compare same snapshot should be empty")
+	}
+}
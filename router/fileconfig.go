@@ -0,0 +1,307 @@
+package router
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"math/big"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/BurntSushi/toml"
+	"github.com/anyswap/CrossChain-Router/v3/common"
+	"github.com/anyswap/CrossChain-Router/v3/log"
+	"github.com/anyswap/CrossChain-Router/v3/params"
+	"github.com/anyswap/CrossChain-Router/v3/tokens"
+)
+
+// anyChainID in swap and fee configs matches any chain
+const anyChainID = "0"
+
+var (
+	fileRouterConfig *FileRouterConfig
+
+	errFileConfigNotLoaded = errors.New("file router config is not loaded")
+)
+
+// FileRouterConfig chain and token configs stored in local file
+// (an alternative of the onchain config contract)
+type FileRouterConfig struct {
+	Chains      map[string]*tokens.ChainConfig                      // key is chain ID
+	Tokens      map[string]map[string]*tokens.TokenConfig           // tokenID -> chainID -> config
+	SwapConfigs map[string]map[string]map[string]*tokens.SwapConfig `toml:",omitempty" json:",omitempty"` // tokenID -> fromChainID -> toChainID -> config
+	FeeConfigs  map[string]map[string]map[string]*tokens.FeeConfig  `toml:",omitempty" json:",omitempty"` // tokenID -> fromChainID -> toChainID -> config
+	MPCPubkeys  map[string]string                                   // mpc address -> public key
+	Customs     map[string]map[string]string                        `toml:",omitempty" json:",omitempty"` // chainID -> key -> value
+}
+
+// LoadFileRouterConfig load and check chain and token configs from local file
+func LoadFileRouterConfig() error {
+	path := params.GetFileConfigPath()
+	config, err := ReadFileRouterConfig(path)
+	if err != nil {
+		log.Error("load file router config failed", "path", path, "err", err)
+		return err
+	}
+	fileRouterConfig = config
+	log.Info("load file router config success", "path", path,
+		"chains", len(config.Chains), "tokens", len(config.Tokens))
+	return nil
+}
+
+// ReadFileRouterConfig read and check file router config (toml or json)
+func ReadFileRouterConfig(path string) (*FileRouterConfig, error) {
+	if path == "" {
+		return nil, errors.New("empty file config path")
+	}
+	config := &FileRouterConfig{}
+	if strings.EqualFold(filepath.Ext(path), ".json") {
+		data, err := ioutil.ReadFile(path)
+		if err != nil {
+			return nil, err
+		}
+		if err = json.Unmarshal(data, config); err != nil {
+			return nil, err
+		}
+	} else if _, err := toml.DecodeFile(path, config); err != nil {
+		return nil, err
+	}
+	if err := config.CheckConfig(); err != nil {
+		return nil, err
+	}
+	return config, nil
+}
+
+// CheckConfig check file router config with the same rules as onchain configs
+//nolint:gocyclo // ok
+func (c *FileRouterConfig) CheckConfig() error {
+	if len(c.Chains) == 0 {
+		return errors.New("file config must config 'Chains'")
+	}
+	for chainID, chainCfg := range c.Chains {
+		if chainCfg == nil {
+			return fmt.Errorf("chain %v has empty config", chainID)
+		}
+		if chainCfg.ChainID == "" {
+			chainCfg.ChainID = chainID
+		} else if chainCfg.ChainID != chainID {
+			return fmt.Errorf("chain ID mismatch, key %v config %v", chainID, chainCfg.ChainID)
+		}
+		if err := chainCfg.CheckConfig(); err != nil {
+			return fmt.Errorf("chain %v: %w", chainID, err)
+		}
+	}
+	for tokenID, tokenCfgs := range c.Tokens {
+		for chainID, tokenCfg := range tokenCfgs {
+			if _, exist := c.Chains[chainID]; !exist {
+				return fmt.Errorf("token %v config on unknown chain %v", tokenID, chainID)
+			}
+			if tokenCfg == nil {
+				return fmt.Errorf("token %v on chain %v has empty config", tokenID, chainID)
+			}
+			if tokenCfg.TokenID == "" {
+				tokenCfg.TokenID = tokenID
+			} else if tokenCfg.TokenID != tokenID {
+				return fmt.Errorf("token ID mismatch, key %v config %v", tokenID, tokenCfg.TokenID)
+			}
+			if err := tokenCfg.CheckConfig(); err != nil {
+				return fmt.Errorf("token %v on chain %v: %w", tokenID, chainID, err)
+			}
+		}
+	}
+	for tokenID, fromMap := range c.SwapConfigs {
+		for fromChainID, toMap := range fromMap {
+			for toChainID, swapCfg := range toMap {
+				if swapCfg == nil {
+					return fmt.Errorf("swap config %v:%v:%v is empty", tokenID, fromChainID, toChainID)
+				}
+				if err := swapCfg.CheckConfig(); err != nil {
+					return fmt.Errorf("swap config %v:%v:%v: %w", tokenID, fromChainID, toChainID, err)
+				}
+			}
+		}
+	}
+	for tokenID, fromMap := range c.FeeConfigs {
+		for fromChainID, toMap := range fromMap {
+			for toChainID, feeCfg := range toMap {
+				if feeCfg == nil {
+					return fmt.Errorf("fee config %v:%v:%v is empty", tokenID, fromChainID, toChainID)
+				}
+				if err := feeCfg.CheckConfig(); err != nil {
+					return fmt.Errorf("fee config %v:%v:%v: %w", tokenID, fromChainID, toChainID, err)
+				}
+			}
+		}
+	}
+	mpcPubkeys := make(map[string]string, len(c.MPCPubkeys))
+	for mpcAddr, pubkey := range c.MPCPubkeys {
+		mpcPubkeys[strings.ToLower(mpcAddr)] = pubkey
+	}
+	c.MPCPubkeys = mpcPubkeys
+	return nil
+}
+
+func getFileRouterConfig() (*FileRouterConfig, error) {
+	config := fileRouterConfig
+	if config == nil {
+		return nil, errFileConfigNotLoaded
+	}
+	return config, nil
+}
+
+func getChainConfigFromFile(chainID *big.Int) (*tokens.ChainConfig, error) {
+	config, err := getFileRouterConfig()
+	if err != nil {
+		return nil, err
+	}
+	chainCfg, exist := config.Chains[chainID.String()]
+	if !exist {
+		return nil, fmt.Errorf("chain config of %v not found", chainID)
+	}
+	cfg := *chainCfg // copy
+	return &cfg, nil
+}
+
+func getTokenConfigFromFile(chainID *big.Int, tokenID string) (*tokens.TokenConfig, error) {
+	config, err := getFileRouterConfig()
+	if err != nil {
+		return nil, err
+	}
+	tokenCfg, exist := config.Tokens[tokenID][chainID.String()]
+	if !exist {
+		return nil, nil
+	}
+	cfg := *tokenCfg // copy
+	return &cfg, nil
+}
+
+// swap and fee configs fallback to the ones with any chain (ie. chain ID is 0)
+func getSwapConfigFromFile(tokenID string, fromChainID, toChainID *big.Int, actual bool) (*tokens.SwapConfig, error) {
+	config, err := getFileRouterConfig()
+	if err != nil {
+		return nil, err
+	}
+	for _, keys := range getConfigLookupKeys(fromChainID, toChainID, actual) {
+		if swapCfg, exist := config.SwapConfigs[tokenID][keys[0]][keys[1]]; exist {
+			cfg := *swapCfg // copy
+			return &cfg, nil
+		}
+	}
+	return nil, fmt.Errorf("swap config of %v from %v to %v not found", tokenID, fromChainID, toChainID)
+}
+
+func getFeeConfigFromFile(tokenID string, fromChainID, toChainID *big.Int, actual bool) (*tokens.FeeConfig, error) {
+	config, err := getFileRouterConfig()
+	if err != nil {
+		return nil, err
+	}
+	for _, keys := range getConfigLookupKeys(fromChainID, toChainID, actual) {
+		if feeCfg, exist := config.FeeConfigs[tokenID][keys[0]][keys[1]]; exist {
+			cfg := *feeCfg // copy
+			return &cfg, nil
+		}
+	}
+	return nil, fmt.Errorf("fee config of %v from %v to %v not found", tokenID, fromChainID, toChainID)
+}
+
+func getConfigLookupKeys(fromChainID, toChainID *big.Int, actual bool) [][2]string {
+	from, to := fromChainID.String(), toChainID.String()
+	if !actual {
+		return [][2]string{{from, to}}
+	}
+	return [][2]string{
+		{from, to},
+		{from, anyChainID},
+		{anyChainID, to},
+		{anyChainID, anyChainID},
+	}
+}
+
+func getCustomConfigFromFile(chainID *big.Int, key string) (string, error) {
+	config, err := getFileRouterConfig()
+	if err != nil {
+		return "", err
+	}
+	return config.Customs[chainID.String()][key], nil
+}
+
+func getMPCPubkeyFromFile(mpcAddress string) (string, error) {
+	config, err := getFileRouterConfig()
+	if err != nil {
+		return "", err
+	}
+	pubkey, exist := config.MPCPubkeys[strings.ToLower(mpcAddress)]
+	if !exist {
+		return "", fmt.Errorf("mpc public key of %v not found", mpcAddress)
+	}
+	return pubkey, nil
+}
+
+func getAllChainIDsFromFile() ([]*big.Int, error) {
+	config, err := getFileRouterConfig()
+	if err != nil {
+		return nil, err
+	}
+	chainIDs := make([]*big.Int, 0, len(config.Chains))
+	for chainID := range config.Chains {
+		bi, err := common.GetBigIntFromStr(chainID)
+		if err != nil {
+			return nil, err
+		}
+		chainIDs = append(chainIDs, bi)
+	}
+	sort.Slice(chainIDs, func(i, j int) bool {
+		return chainIDs[i].Cmp(chainIDs[j]) < 0
+	})
+	return chainIDs, nil
+}
+
+func getAllTokenIDsFromFile() ([]string, error) {
+	config, err := getFileRouterConfig()
+	if err != nil {
+		return nil, err
+	}
+	tokenIDs := make([]string, 0, len(config.Tokens))
+	for tokenID := range config.Tokens {
+		tokenIDs = append(tokenIDs, tokenID)
+	}
+	sort.Strings(tokenIDs)
+	return tokenIDs, nil
+}
+
+func getMultichainTokenFromFile(tokenID string, chainID *big.Int) (string, error) {
+	config, err := getFileRouterConfig()
+	if err != nil {
+		return "", err
+	}
+	tokenCfg, exist := config.Tokens[tokenID][chainID.String()]
+	if !exist {
+		return "", nil
+	}
+	return tokenCfg.ContractAddress, nil
+}
+
+func getAllMultichainTokensFromFile(tokenID string) ([]MultichainToken, error) {
+	config, err := getFileRouterConfig()
+	if err != nil {
+		return nil, err
+	}
+	tokenCfgs := config.Tokens[tokenID]
+	mcTokens := make([]MultichainToken, 0, len(tokenCfgs))
+	for chainID, tokenCfg := range tokenCfgs {
+		bi, err := common.GetBigIntFromStr(chainID)
+		if err != nil {
+			return nil, err
+		}
+		mcTokens = append(mcTokens, MultichainToken{
+			ChainID:      bi,
+			TokenAddress: tokenCfg.ContractAddress,
+		})
+	}
+	sort.Slice(mcTokens, func(i, j int) bool {
+		return mcTokens[i].ChainID.Cmp(mcTokens[j].ChainID) < 0
+	})
+	return mcTokens, nil
+}
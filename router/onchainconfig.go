@@ -38,6 +38,12 @@ var (
 
 // InitRouterConfigClients init router config clients
 func InitRouterConfigClients() {
+	if params.IsFileConfigSource() {
+		if err := LoadFileRouterConfig(); err != nil {
+			log.Fatal("init file router config failed", "err", err)
+		}
+		return
+	}
 	onchainCfg := params.GetRouterConfig().Onchain
 	InitRouterConfigClientsWithArgs(onchainCfg.Contract, onchainCfg.APIAddress)
 	routerWebSocketClients = InitWebSocketClients(onchainCfg.WSServers)
@@ -179,6 +185,9 @@ func GetChainConfig(chainID *big.Int) (*tokens.ChainConfig, error) {
 	if chainID == nil || chainID.Sign() == 0 {
 		return nil, errors.New("chainID is zero")
 	}
+	if params.IsFileConfigSource() {
+		return getChainConfigFromFile(chainID)
+	}
 	funcHash := common.FromHex("0x19ed16dc")
 	data := abicoder.PackDataWithFuncHash(funcHash, chainID)
 	res, err := CallOnchainContract(data, "latest")
@@ -229,6 +238,9 @@ func parseTokenConfig(data []byte) (config *tokens.TokenConfig, err error) {
 
 // GetTokenConfig abi
 func GetTokenConfig(chainID *big.Int, token string) (tokenCfg *tokens.TokenConfig, err error) {
+	if params.IsFileConfigSource() {
+		return getTokenConfigFromFile(chainID, token)
+	}
 	funcHash := common.FromHex("0x459511d1")
 	data := abicoder.PackDataWithFuncHash(funcHash, token, chainID)
 	res, err := CallOnchainContract(data, "latest")
@@ -272,6 +284,9 @@ func callAndParseSwapConfigResult(data []byte) (*tokens.SwapConfig, error) {
 
 // GetSwapConfig abi
 func GetSwapConfig(tokenID string, fromChainID, toChainID *big.Int) (*tokens.SwapConfig, error) {
+	if params.IsFileConfigSource() {
+		return getSwapConfigFromFile(tokenID, fromChainID, toChainID, false)
+	}
 	funcHash := common.FromHex("0x4da7163c")
 	data := abicoder.PackDataWithFuncHash(funcHash, tokenID, fromChainID, toChainID)
 	return callAndParseSwapConfigResult(data)
@@ -279,6 +294,9 @@ func GetSwapConfig(tokenID string, fromChainID, toChainID *big.Int) (*tokens.Swa
 
 // GetActualSwapConfig abi
 func GetActualSwapConfig(tokenID string, fromChainID, toChainID *big.Int) (*tokens.SwapConfig, error) {
+	if params.IsFileConfigSource() {
+		return getSwapConfigFromFile(tokenID, fromChainID, toChainID, true)
+	}
 	funcHash := common.FromHex("0xd5637235")
 	data := abicoder.PackDataWithFuncHash(funcHash, tokenID, fromChainID, toChainID)
 	return callAndParseSwapConfigResult(data)
@@ -313,6 +331,9 @@ func callAndParseFeeConfigResult(data []byte) (*tokens.FeeConfig, error) {
 
 // GetFeeConfig abi
 func GetFeeConfig(tokenID string, fromChainID, toChainID *big.Int) (*tokens.FeeConfig, error) {
+	if params.IsFileConfigSource() {
+		return getFeeConfigFromFile(tokenID, fromChainID, toChainID, false)
+	}
 	funcHash := common.FromHex("0x1aed1c97")
 	data := abicoder.PackDataWithFuncHash(funcHash, tokenID, fromChainID, toChainID)
 	return callAndParseFeeConfigResult(data)
@@ -320,6 +341,9 @@ func GetFeeConfig(tokenID string, fromChainID, toChainID *big.Int) (*tokens.FeeC
 
 // GetActualFeeConfig abi
 func GetActualFeeConfig(tokenID string, fromChainID, toChainID *big.Int) (*tokens.FeeConfig, error) {
+	if params.IsFileConfigSource() {
+		return getFeeConfigFromFile(tokenID, fromChainID, toChainID, true)
+	}
 	funcHash := common.FromHex("0xae409e9a")
 	data := abicoder.PackDataWithFuncHash(funcHash, tokenID, fromChainID, toChainID)
 	return callAndParseFeeConfigResult(data)
@@ -327,6 +351,9 @@ func GetActualFeeConfig(tokenID string, fromChainID, toChainID *big.Int) (*token
 
 // GetCustomConfig abi
 func GetCustomConfig(chainID *big.Int, key string) (string, error) {
+	if params.IsFileConfigSource() {
+		return getCustomConfigFromFile(chainID, key)
+	}
 	funcHash := common.FromHex("0x61387d61")
 	data := abicoder.PackDataWithFuncHash(funcHash, chainID, key)
 	res, err := CallOnchainContract(data, "latest")
@@ -341,6 +368,9 @@ func GetCustomConfig(chainID *big.Int, key string) (string, error) {
 
 // GetMPCPubkey abi
 func GetMPCPubkey(mpcAddress string) (pubkey string, err error) {
+	if params.IsFileConfigSource() {
+		return getMPCPubkeyFromFile(mpcAddress)
+	}
 	funcHash := common.FromHex("0x9f1cdedd")
 	data := abicoder.PackDataWithFuncHash(funcHash, mpcAddress)
 	res, err := CallOnchainContract(data, "latest")
@@ -352,6 +382,10 @@ func GetMPCPubkey(mpcAddress string) (pubkey string, err error) {
 
 // IsChainIDExist abi
 func IsChainIDExist(chainID *big.Int) (exist bool, err error) {
+	if params.IsFileConfigSource() {
+		_, err = getChainConfigFromFile(chainID)
+		return err == nil, nil
+	}
 	funcHash := common.FromHex("0xfd15ea70")
 	data := abicoder.PackDataWithFuncHash(funcHash, chainID)
 	res, err := CallOnchainContract(data, "latest")
@@ -363,6 +397,14 @@ func IsChainIDExist(chainID *big.Int) (exist bool, err error) {
 
 // IsTokenIDExist abi
 func IsTokenIDExist(tokenID string) (exist bool, err error) {
+	if params.IsFileConfigSource() {
+		config, err := getFileRouterConfig()
+		if err != nil {
+			return false, err
+		}
+		_, exist = config.Tokens[tokenID]
+		return exist, nil
+	}
 	funcHash := common.FromHex("0xaf611ca0")
 	data := abicoder.PackDataWithFuncHash(funcHash, tokenID)
 	res, err := CallOnchainContract(data, "latest")
@@ -374,6 +416,9 @@ func IsTokenIDExist(tokenID string) (exist bool, err error) {
 
 // GetAllChainIDs abi
 func GetAllChainIDs() (chainIDs []*big.Int, err error) {
+	if params.IsFileConfigSource() {
+		return getAllChainIDsFromFile()
+	}
 	funcHash := common.FromHex("0xe27112d5")
 	res, err := CallOnchainContract(funcHash, "latest")
 	if err != nil {
@@ -384,6 +429,9 @@ func GetAllChainIDs() (chainIDs []*big.Int, err error) {
 
 // GetAllTokenIDs abi
 func GetAllTokenIDs() (tokenIDs []string, err error) {
+	if params.IsFileConfigSource() {
+		return getAllTokenIDsFromFile()
+	}
 	funcHash := common.FromHex("0x684a10b3")
 	res, err := CallOnchainContract(funcHash, "latest")
 	if err != nil {
@@ -394,6 +442,9 @@ func GetAllTokenIDs() (tokenIDs []string, err error) {
 
 // GetMultichainToken abi
 func GetMultichainToken(tokenID string, chainID *big.Int) (tokenAddr string, err error) {
+	if params.IsFileConfigSource() {
+		return getMultichainTokenFromFile(tokenID, chainID)
+	}
 	funcHash := common.FromHex("0xb735ab5a")
 	data := abicoder.PackDataWithFuncHash(funcHash, tokenID, chainID)
 	res, err := CallOnchainContract(data, "latest")
@@ -443,6 +494,9 @@ func parseMultichainTokens(data []byte) (mcTokens []MultichainToken, err error)
 
 // GetAllMultichainTokens abi
 func GetAllMultichainTokens(tokenID string) ([]MultichainToken, error) {
+	if params.IsFileConfigSource() {
+		return getAllMultichainTokensFromFile(tokenID)
+	}
 	funcHash := common.FromHex("0x8fcb62a3")
 	data := abicoder.PackDataWithFuncHash(funcHash, tokenID)
 	res, err := CallOnchainContract(data, "latest")
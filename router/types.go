@@ -120,6 +120,18 @@ func GetRouterMPC(tokenID, chainID string) (string, error) {
 	return routerInfo.RouterMPC, nil
 }
 
+// IsRouterMPC is router mpc of any router contract
+func IsRouterMPC(mpc string) (exist bool) {
+	RouterInfos.Range(func(k, v interface{}) bool {
+		if info, ok := v.(*SwapRouterInfo); ok && common.IsEqualIgnoreCase(info.RouterMPC, mpc) {
+			exist = true
+			return false
+		}
+		return true
+	})
+	return exist
+}
+
 // SetMPCPublicKey set router mpc public key
 func SetMPCPublicKey(mpc, pubkey string) {
 	key := strings.ToLower(mpc)
@@ -222,6 +234,27 @@ func IsBigValueSwap(swapInfo *tokens.SwapTxInfo) bool {
 	return swapInfo.Value.Cmp(bigValueThreshold) > 0
 }
 
+// IsHighValueMPCSwap is swap reached the high value mpc threshold of its tokenID
+func IsHighValueMPCSwap(args *tokens.BuildTxArgs) bool {
+	if args.ERC20SwapInfo == nil || args.OriginValue == nil {
+		return false
+	}
+	threshold := params.GetHighValueMPCThreshold(args.ERC20SwapInfo.TokenID)
+	if threshold == nil {
+		return false
+	}
+	bridge := GetBridgeByChainID(args.FromChainID.String())
+	if bridge == nil {
+		return true
+	}
+	tokenCfg := bridge.GetTokenConfig(args.ERC20SwapInfo.Token)
+	if tokenCfg == nil {
+		return true // unknown decimals, use high value mpc for safety
+	}
+	threshold = tokens.ConvertTokenValue(threshold, 18, tokenCfg.Decimals)
+	return args.OriginValue.Cmp(threshold) >= 0
+}
+
 // IsBlacklistSwap is swap blacked
 func IsBlacklistSwap(swapInfo *tokens.SwapTxInfo) bool {
 	return params.IsChainIDInBlackList(swapInfo.FromChainID.String()) ||
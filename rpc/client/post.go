@@ -96,6 +96,21 @@ func (err *jsonError) Error() string {
 	return fmt.Sprintf("json-rpc error %d, %s", err.Code, err.Message)
 }
 
+func (err *jsonError) ErrorCode() int {
+	return err.Code
+}
+
+func (err *jsonError) ErrorData() interface{} {
+	return err.Data
+}
+
+// DataError json-rpc error with code and data (eg. revert data of eth_call)
+type DataError interface {
+	Error() string
+	ErrorCode() int
+	ErrorData() interface{}
+}
+
 type jsonrpcResponse struct {
 	Version string          `json:"jsonrpc,omitempty"`
 	ID      json.RawMessage `json:"id,omitempty"`
@@ -5,6 +5,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"net/http"
+	"strconv"
 
 	"github.com/anyswap/CrossChain-Router/v3/common"
 	"github.com/anyswap/CrossChain-Router/v3/internal/swapapi"
@@ -123,6 +124,14 @@ func GetRouterSwapHandler(w http.ResponseWriter, r *http.Request) {
 	writeResponse(w, res, err)
 }
 
+// GetRouterSwapBySwapTxHandler handler
+func GetRouterSwapBySwapTxHandler(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	swapTx := vars["swaptx"]
+	res, err := swapapi.GetRouterSwapBySwapTx(swapTx)
+	writeResponse(w, res, err)
+}
+
 func getHistoryRequestVaules(r *http.Request) (offset, limit int, status string, err error) {
 	vals := r.URL.Query()
 
@@ -164,6 +173,27 @@ func GetRouterSwapHistoryHandler(w http.ResponseWriter, r *http.Request) {
 	}
 }
 
+// GetSwapResultsSinceHandler handler
+func GetSwapResultsSinceHandler(w http.ResponseWriter, r *http.Request) {
+	vals := r.URL.Query()
+	var since int64
+	var limit int
+	var err error
+	if sinceStr, exist := vals["since"]; exist {
+		since, err = strconv.ParseInt(sinceStr[0], 10, 64)
+	}
+	if limitStr, exist := vals["limit"]; exist && err == nil {
+		limit, err = common.GetIntFromStr(limitStr[0])
+	}
+	afterKey := vals.Get("afterKey")
+	if err != nil {
+		writeResponse(w, nil, err)
+	} else {
+		res, err := swapapi.GetSwapResultsSince(since, afterKey, limit)
+		writeResponse(w, res, err)
+	}
+}
+
 // GetAllChainIDsHandler handler
 func GetAllChainIDsHandler(w http.ResponseWriter, r *http.Request) {
 	allChainIDs := router.AllChainIDs
@@ -1,6 +1,7 @@
 package rpcapi
 
 import (
+	"encoding/json"
 	"fmt"
 	"math/big"
 	"net/http"
@@ -8,10 +9,14 @@ import (
 
 	"github.com/anyswap/CrossChain-Router/v3/admin"
 	"github.com/anyswap/CrossChain-Router/v3/common"
+	"github.com/anyswap/CrossChain-Router/v3/internal/swapapi"
 	"github.com/anyswap/CrossChain-Router/v3/log"
 	"github.com/anyswap/CrossChain-Router/v3/mongodb"
+	"github.com/anyswap/CrossChain-Router/v3/mpc"
 	"github.com/anyswap/CrossChain-Router/v3/params"
 	"github.com/anyswap/CrossChain-Router/v3/router"
+	"github.com/anyswap/CrossChain-Router/v3/router/bridge"
+	"github.com/anyswap/CrossChain-Router/v3/storage"
 	"github.com/anyswap/CrossChain-Router/v3/tokens"
 	"github.com/anyswap/CrossChain-Router/v3/worker"
 )
@@ -21,6 +26,17 @@ const (
 	passbigvalueCmd = "passbigvalue"
 	reswapCmd       = "reswap"
 	replaceswapCmd  = "replaceswap"
+	dryrunreloadCmd = "dryrunreload"
+	retryswapCmd    = "retryswap"
+	fillnoncegapCmd = "fillnoncegap"
+	signgroupCmd    = "signgroup"
+	disagreesCmd    = "disagrees"
+	restoreswapCmd  = "restoreswap"
+	exportCmd       = "export"
+	nonceauditCmd   = "nonceaudit"
+	snapshotCmd     = "configsnapshot"
+
+	reportdisagreesCmd = "reportdisagrees" // called by oracles
 
 	// maintain actions
 	actPause       = "pause"
@@ -30,6 +46,19 @@ const (
 	actBlacklist   = "blacklist"
 	actUnblacklist = "unblacklist"
 
+	// signgroup actions
+	actList    = "list"
+	actEnable  = "enable"
+	actDisable = "disable"
+
+	// configsnapshot actions
+	actApply = "apply"
+
+	mpcKindMPC     = "mpc"
+	mpcKindFastMPC = "fastmpc"
+
+	mpcKindHighValueMPC = "highvaluempc"
+
 	successReuslt = "Success"
 )
 
@@ -47,9 +76,17 @@ func (s *RouterSwapAPI) AdminCall(r *http.Request, rawTx, result *string) (err e
 		return err
 	}
 	senderAddress := sender.String()
+	if args.Method == reportdisagreesCmd {
+		// the oracle is bound to the sender, not specified in params
+		oracle := params.GetOracleReporterEnode(senderAddress)
+		if oracle == "" {
+			return fmt.Errorf("sender %v is not oracle reporter", senderAddress)
+		}
+		return routerReportDisagrees(oracle, args, result)
+	}
 	if !params.IsRouterAdmin(senderAddress) {
 		switch args.Method {
-		case reswapCmd:
+		case reswapCmd, fillnoncegapCmd, restoreswapCmd:
 			return fmt.Errorf("sender %v is not admin", senderAddress)
 		case maintainCmd:
 			action := args.Params[0]
@@ -57,7 +94,11 @@ func (s *RouterSwapAPI) AdminCall(r *http.Request, rawTx, result *string) (err e
 			case actPause, actUnpause:
 				return fmt.Errorf("sender %v is not admin", senderAddress)
 			}
-		case passbigvalueCmd, replaceswapCmd:
+		case signgroupCmd, snapshotCmd:
+			if len(args.Params) == 0 || args.Params[0] != actList {
+				return fmt.Errorf("sender %v is not admin", senderAddress)
+			}
+		case passbigvalueCmd, replaceswapCmd, dryrunreloadCmd, retryswapCmd, disagreesCmd, exportCmd, nonceauditCmd:
 		default:
 			return fmt.Errorf("unknown admin method '%v'", args.Method)
 		}
@@ -66,19 +107,38 @@ func (s *RouterSwapAPI) AdminCall(r *http.Request, rawTx, result *string) (err e
 		}
 	}
 	log.Info("admin call", "caller", senderAddress, "args", args, "result", result)
-	return doRouterAdminCall(args, result)
+	return doRouterAdminCall(senderAddress, args, result)
 }
 
-func doRouterAdminCall(args *admin.CallArgs, result *string) error {
+func doRouterAdminCall(caller string, args *admin.CallArgs, result *string) error {
+	actor := "admin:" + caller // who changes swap status
 	switch args.Method {
 	case maintainCmd:
 		return maintain(args, result)
 	case passbigvalueCmd:
-		return routerPassBigValue(args, result)
+		return routerPassBigValue(actor, args, result)
 	case reswapCmd:
-		return routerReswap(args, result)
+		return routerReswap(actor, args, result)
 	case replaceswapCmd:
 		return routerReplaceSwap(args, result)
+	case dryrunreloadCmd:
+		return routerDryRunReload(args, result)
+	case retryswapCmd:
+		return routerRetrySwap(actor, args, result)
+	case fillnoncegapCmd:
+		return routerFillNonceGap(args, result)
+	case signgroupCmd:
+		return routerSignGroup(args, result)
+	case disagreesCmd:
+		return routerDisagrees(args, result)
+	case restoreswapCmd:
+		return routerRestoreSwap(args, result)
+	case exportCmd:
+		return routerExport(args, result)
+	case nonceauditCmd:
+		return routerNonceAudit(args, result)
+	case snapshotCmd:
+		return routerConfigSnapshot(args, result)
 	default:
 		return fmt.Errorf("unknown admin method '%v'", args.Method)
 	}
@@ -180,7 +240,7 @@ func getGasPrice(args *admin.CallArgs, startPos int) (gasPrice *big.Int, err err
 	return
 }
 
-func routerPassBigValue(args *admin.CallArgs, result *string) (err error) {
+func routerPassBigValue(actor string, args *admin.CallArgs, result *string) (err error) {
 	chainID, txid, logIndex, err := getKeys(args, 0)
 	if err != nil {
 		return err
@@ -198,7 +258,7 @@ func routerPassBigValue(args *admin.CallArgs, result *string) (err error) {
 	if err != nil {
 		return err
 	}
-	err = mongodb.RouterAdminPassBigValue(chainID, txid, logIndex)
+	err = storage.RouterAdminPassBigValue(chainID, txid, logIndex, actor)
 	if err != nil {
 		return err
 	}
@@ -207,12 +267,26 @@ func routerPassBigValue(args *admin.CallArgs, result *string) (err error) {
 	return nil
 }
 
-func routerReswap(args *admin.CallArgs, result *string) (err error) {
+func routerReswap(actor string, args *admin.CallArgs, result *string) (err error) {
+	chainID, txid, logIndex, err := getKeys(args, 0)
+	if err != nil {
+		return err
+	}
+	err = storage.RouterAdminReswap(chainID, txid, logIndex, actor)
+	if err != nil {
+		return err
+	}
+	worker.DeleteCachedSwap(chainID, txid, logIndex)
+	*result = successReuslt
+	return nil
+}
+
+func routerRetrySwap(actor string, args *admin.CallArgs, result *string) (err error) {
 	chainID, txid, logIndex, err := getKeys(args, 0)
 	if err != nil {
 		return err
 	}
-	err = mongodb.RouterAdminReswap(chainID, txid, logIndex)
+	err = storage.RouterAdminRetrySwap(chainID, txid, logIndex, actor)
 	if err != nil {
 		return err
 	}
@@ -221,6 +295,93 @@ func routerReswap(args *admin.CallArgs, result *string) (err error) {
 	return nil
 }
 
+func routerRestoreSwap(args *admin.CallArgs, result *string) (err error) {
+	chainID, txid, logIndex, err := getKeys(args, 0)
+	if err != nil {
+		return err
+	}
+	if !mongodb.HasClient() {
+		return fmt.Errorf("restore swap is only for mongodb")
+	}
+	err = mongodb.RestoreRouterSwap(chainID, txid, logIndex)
+	if err != nil {
+		return err
+	}
+	*result = successReuslt
+	return nil
+}
+
+func routerExport(args *admin.CallArgs, result *string) (err error) {
+	if len(args.Params) != 1 {
+		return fmt.Errorf("wrong number of params, have %v want 1", len(args.Params))
+	}
+	var exportArgs swapapi.ExportArgs
+	if err = json.Unmarshal([]byte(args.Params[0]), &exportArgs); err != nil {
+		return fmt.Errorf("wrong export args: %w", err)
+	}
+	res, err := swapapi.ExportSwapResults(&exportArgs)
+	if err != nil {
+		return err
+	}
+	data, err := json.Marshal(res)
+	if err != nil {
+		return err
+	}
+	*result = string(data)
+	return nil
+}
+
+func routerFillNonceGap(args *admin.CallArgs, result *string) (err error) {
+	if len(args.Params) != 3 {
+		return fmt.Errorf("wrong number of params, have %v want 3", len(args.Params))
+	}
+	chainID := args.Params[0]
+	if _, err = common.GetBigIntFromStr(chainID); err != nil || chainID == "" {
+		return fmt.Errorf("wrong chain id '%v'", chainID)
+	}
+	mpcAddress := args.Params[1]
+	if !common.IsHexAddress(mpcAddress) {
+		return fmt.Errorf("wrong mpc address '%v'", mpcAddress)
+	}
+	nonce, err := common.GetUint64FromStr(args.Params[2])
+	if err != nil {
+		return fmt.Errorf("wrong nonce '%v'", args.Params[2])
+	}
+	txHash, err := worker.FillSwapNonceGap(chainID, mpcAddress, nonce)
+	if err != nil {
+		return err
+	}
+	*result = txHash
+	return nil
+}
+
+func routerNonceAudit(args *admin.CallArgs, result *string) (err error) {
+	if len(args.Params) < 1 || len(args.Params) > 2 {
+		return fmt.Errorf("wrong number of params, have %v want 1 or 2", len(args.Params))
+	}
+	chainID := args.Params[0]
+	if _, err = common.GetBigIntFromStr(chainID); err != nil || chainID == "" {
+		return fmt.Errorf("wrong chain id '%v'", chainID)
+	}
+	var mpcAddress string
+	if len(args.Params) > 1 {
+		mpcAddress = args.Params[1]
+		if !common.IsHexAddress(mpcAddress) {
+			return fmt.Errorf("wrong mpc address '%v'", mpcAddress)
+		}
+	}
+	reports, err := worker.AuditSwapNonce(chainID, mpcAddress)
+	if err != nil {
+		return err
+	}
+	data, err := json.Marshal(reports)
+	if err != nil {
+		return err
+	}
+	*result = string(data)
+	return nil
+}
+
 func routerReplaceSwap(args *admin.CallArgs, result *string) (err error) {
 	chainID, txid, logIndex, err := getKeys(args, 0)
 	if err != nil {
@@ -230,7 +391,7 @@ func routerReplaceSwap(args *admin.CallArgs, result *string) (err error) {
 	if err != nil {
 		return err
 	}
-	res, err := mongodb.FindRouterSwapResult(chainID, txid, logIndex)
+	res, err := storage.Get().FindRouterSwapResult(chainID, txid, logIndex)
 	if err != nil {
 		return err
 	}
@@ -241,3 +402,184 @@ func routerReplaceSwap(args *admin.CallArgs, result *string) (err error) {
 	*result = successReuslt
 	return nil
 }
+
+func routerDryRunReload(_ *admin.CallArgs, result *string) (err error) {
+	diff, err := bridge.DryRunReloadRouterConfig()
+	if err != nil {
+		return err
+	}
+	data, err := json.Marshal(diff)
+	if err != nil {
+		return err
+	}
+	*result = string(data)
+	return nil
+}
+
+// ConfigSnapshotInfo config snapshot info of list
+type ConfigSnapshotInfo struct {
+	Key       string
+	Timestamp int64
+	Diff      *router.ConfigDiff
+}
+
+func routerConfigSnapshot(args *admin.CallArgs, result *string) (err error) {
+	if len(args.Params) == 0 {
+		return fmt.Errorf("wrong number of params, have 0 want at least 1")
+	}
+	if !mongodb.HasClient() {
+		return fmt.Errorf("config snapshots are not saved without mongodb")
+	}
+	action := args.Params[0]
+	switch action {
+	case actList:
+		var limit uint64
+		if len(args.Params) > 1 {
+			limit, err = common.GetUint64FromStr(args.Params[1])
+			if err != nil {
+				return err
+			}
+		}
+		snapshots, errf := mongodb.FindLatestConfigSnapshots(int64(limit))
+		if errf != nil {
+			return errf
+		}
+		infos := make([]*ConfigSnapshotInfo, 0, len(snapshots))
+		for _, cs := range snapshots {
+			info := &ConfigSnapshotInfo{Key: cs.Key, Timestamp: cs.Timestamp}
+			if cs.Diff != "" {
+				info.Diff = &router.ConfigDiff{}
+				if errf = json.Unmarshal([]byte(cs.Diff), info.Diff); errf != nil {
+					return errf
+				}
+			}
+			infos = append(infos, info)
+		}
+		data, errf := json.Marshal(infos)
+		if errf != nil {
+			return errf
+		}
+		*result = string(data)
+	case actApply:
+		if len(args.Params) != 2 {
+			return fmt.Errorf("wrong number of params, have %v want 2", len(args.Params))
+		}
+		snapshot, errf := bridge.GetConfigSnapshot(args.Params[1])
+		if errf != nil {
+			return errf
+		}
+		err = bridge.ApplyConfigSnapshot(snapshot)
+		if err != nil {
+			return err
+		}
+		*result = successReuslt
+	default:
+		return fmt.Errorf("unknown configsnapshot action '%v'", action)
+	}
+	return nil
+}
+
+func getMPCConfigOfKind(mpcKind string) (*mpc.Config, error) {
+	var mpcConfig *mpc.Config
+	switch mpcKind {
+	case mpcKindMPC:
+		mpcConfig = mpc.GetMPCConfig(false)
+	case mpcKindFastMPC:
+		mpcConfig = mpc.GetMPCConfig(true)
+	case mpcKindHighValueMPC:
+		mpcConfig = mpc.GetHighValueMPCConfig()
+	default:
+		return nil, fmt.Errorf("wrong mpc kind '%v'", mpcKind)
+	}
+	if mpcConfig == nil {
+		return nil, fmt.Errorf("%v is not configed", mpcKind)
+	}
+	return mpcConfig, nil
+}
+
+func routerSignGroup(args *admin.CallArgs, result *string) (err error) {
+	if len(args.Params) == 0 {
+		return fmt.Errorf("wrong number of params, have 0 want at least 1")
+	}
+	action := args.Params[0]
+	switch action {
+	case actList:
+		mpcKinds := []string{mpcKindMPC, mpcKindFastMPC, mpcKindHighValueMPC}
+		if len(args.Params) > 1 {
+			mpcKinds = args.Params[1:2]
+		}
+		health := make(map[string][]*mpc.SignGroupHealth, len(mpcKinds))
+		for _, mpcKind := range mpcKinds {
+			mpcConfig, errf := getMPCConfigOfKind(mpcKind)
+			if errf != nil {
+				if len(args.Params) > 1 {
+					return errf
+				}
+				continue
+			}
+			health[mpcKind] = mpcConfig.GetSignGroupsHealth()
+		}
+		data, errf := json.Marshal(health)
+		if errf != nil {
+			return errf
+		}
+		*result = string(data)
+	case actEnable, actDisable:
+		if len(args.Params) != 3 {
+			return fmt.Errorf("wrong number of params, have %v want 3", len(args.Params))
+		}
+		mpcConfig, errf := getMPCConfigOfKind(args.Params[1])
+		if errf != nil {
+			return errf
+		}
+		err = mpcConfig.SetSignGroupDisabled(args.Params[2], action == actDisable)
+		if err != nil {
+			return err
+		}
+		*result = successReuslt
+	default:
+		return fmt.Errorf("unknown signgroup action '%v'", action)
+	}
+	return nil
+}
+
+// oracles sign the report with their mpc user keystore,
+// the mpc users and enodes of oracles are configed in 'OracleReporters'.
+func routerReportDisagrees(oracle string, args *admin.CallArgs, result *string) (err error) {
+	if len(args.Params) != 1 {
+		return fmt.Errorf("wrong number of params, have %v want 1", len(args.Params))
+	}
+	var disagrees []*swapapi.OracleDisagree
+	if err = json.Unmarshal([]byte(args.Params[0]), &disagrees); err != nil {
+		return fmt.Errorf("wrong disagrees: %w", err)
+	}
+	if err = swapapi.ReportOracleDisagrees(oracle, disagrees); err != nil {
+		return err
+	}
+	*result = successReuslt
+	return nil
+}
+
+func routerDisagrees(args *admin.CallArgs, result *string) (err error) {
+	chainID, txid, logIndex, err := getKeys(args, 0)
+	if err != nil {
+		return err
+	}
+	res, err := storage.Get().FindRouterSwapResult(chainID, txid, logIndex)
+	if err != nil {
+		return err
+	}
+	codes := make(map[string]int)
+	for _, d := range res.Disagrees {
+		codes[d.Code]++
+	}
+	data, err := json.Marshal(map[string]interface{}{
+		"codes":     codes,
+		"disagrees": res.Disagrees,
+	})
+	if err != nil {
+		return err
+	}
+	*result = string(data)
+	return nil
+}
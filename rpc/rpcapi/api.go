@@ -64,12 +64,32 @@ func (s *RouterSwapAPI) GetStatusInfo(r *http.Request, statuses *string, result
 type OracleInfoArgs struct {
 	Enode     string `json:"enode"`
 	Timestamp int64  `json:"timestamp"`
+
+	Version          string            `json:"version"`
+	ConfigHash       string            `json:"configHash"`
+	MPCConnected     bool              `json:"mpcConnected"`
+	FastMPCConnected *bool             `json:"fastMPCConnected"`
+	AcceptQueue      int64             `json:"acceptQueue"`
+	AcceptRoutines   int64             `json:"acceptRoutines"`
+	AgreeCount       uint64            `json:"agreeCount"`
+	DisagreeCount    uint64            `json:"disagreeCount"`
+	ChainHeights     map[string]uint64 `json:"chainHeights"`
 }
 
 func (args *OracleInfoArgs) toOracleInfo() *swapapi.OracleInfo {
 	return &swapapi.OracleInfo{
 		Heartbeat:          time.Unix(args.Timestamp, 0).Format(time.RFC3339),
 		HeartbeatTimestamp: args.Timestamp,
+
+		Version:          args.Version,
+		ConfigHash:       args.ConfigHash,
+		MPCConnected:     args.MPCConnected,
+		FastMPCConnected: args.FastMPCConnected,
+		AcceptQueue:      args.AcceptQueue,
+		AcceptRoutines:   args.AcceptRoutines,
+		AgreeCount:       args.AgreeCount,
+		DisagreeCount:    args.DisagreeCount,
+		ChainHeights:     args.ChainHeights,
 	}
 }
 
@@ -101,6 +121,20 @@ func (s *RouterSwapAPI) GetRouterSwap(r *http.Request, args *RouterSwapKeyArgs,
 	return err
 }
 
+// RouterSwapTxArgs args
+type RouterSwapTxArgs struct {
+	SwapTx string `json:"swaptx"`
+}
+
+// GetRouterSwapBySwapTx api
+func (s *RouterSwapAPI) GetRouterSwapBySwapTx(r *http.Request, args *RouterSwapTxArgs, result *swapapi.SwapTxLookupResult) error {
+	res, err := swapapi.GetRouterSwapBySwapTx(args.SwapTx)
+	if err == nil && res != nil {
+		*result = *res
+	}
+	return err
+}
+
 // RouterGetSwapHistoryArgs args
 type RouterGetSwapHistoryArgs struct {
 	ChainID string `json:"chainid"`
@@ -119,6 +153,22 @@ func (s *RouterSwapAPI) GetRouterSwapHistory(r *http.Request, args *RouterGetSwa
 	return err
 }
 
+// RouterGetSwapResultsSinceArgs args
+type RouterGetSwapResultsSinceArgs struct {
+	Since    int64  `json:"since"`
+	AfterKey string `json:"afterKey"`
+	Limit    int    `json:"limit"`
+}
+
+// GetSwapResultsSince api
+func (s *RouterSwapAPI) GetSwapResultsSince(r *http.Request, args *RouterGetSwapResultsSinceArgs, result *[]*swapapi.SwapInfo) error {
+	res, err := swapapi.GetSwapResultsSince(args.Since, args.AfterKey, args.Limit)
+	if err == nil && res != nil {
+		*result = res
+	}
+	return err
+}
+
 // GetAllChainIDs api
 func (s *RouterSwapAPI) GetAllChainIDs(r *http.Request, args *RPCNullArgs, result *[]*big.Int) error {
 	*result = router.AllChainIDs
@@ -0,0 +1,68 @@
+package rpcapi
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/anyswap/CrossChain-Router/v3/common"
+	"github.com/anyswap/CrossChain-Router/v3/worker"
+)
+
+// OracleLedgerAPI oracle local api to query accept ledger
+type OracleLedgerAPI struct{}
+
+// LedgerKeyIDArgs args
+type LedgerKeyIDArgs struct {
+	KeyID string `json:"keyid"`
+}
+
+// LedgerRangeArgs args
+type LedgerRangeArgs struct {
+	Start int64 `json:"start"`
+	End   int64 `json:"end"`
+	Limit int   `json:"limit"`
+}
+
+// GetRecord api
+func (s *OracleLedgerAPI) GetRecord(r *http.Request, args *LedgerKeyIDArgs, result *worker.AcceptLedgerRecord) error {
+	res, err := worker.GetAcceptLedgerRecord(args.KeyID)
+	if err == nil && res != nil {
+		*result = *res
+	}
+	return err
+}
+
+// GetRecords api
+func (s *OracleLedgerAPI) GetRecords(r *http.Request, args *LedgerRangeArgs, result *[]*worker.AcceptLedgerRecord) error {
+	res, err := worker.GetAcceptLedgerRecords(args.Start, args.End, args.Limit)
+	if err == nil && res != nil {
+		*result = res
+	}
+	return err
+}
+
+// GetSwapRecords api
+func (s *OracleLedgerAPI) GetSwapRecords(r *http.Request, args *RouterSwapKeyArgs, result *[]*worker.AcceptLedgerRecord) error {
+	logIndex := 0
+	if args.LogIndex != "" {
+		var err error
+		logIndex, err = common.GetIntFromStr(args.LogIndex)
+		if err != nil {
+			return fmt.Errorf("wrong log index '%v'", args.LogIndex)
+		}
+	}
+	res, err := worker.GetAcceptLedgerRecordsOfSwap(args.ChainID, args.TxID, logIndex)
+	if err == nil && res != nil {
+		*result = res
+	}
+	return err
+}
+
+// GetFlags api
+func (s *OracleLedgerAPI) GetFlags(r *http.Request, args *LedgerRangeArgs, result *[]*worker.AcceptLedgerFlag) error {
+	res, err := worker.GetAcceptLedgerFlags(args.Limit)
+	if err == nil && res != nil {
+		*result = res
+	}
+	return err
+}
@@ -0,0 +1,54 @@
+package server
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/gorilla/mux"
+	"github.com/gorilla/rpc/v2"
+	rpcjson "github.com/gorilla/rpc/v2/json2"
+
+	"github.com/anyswap/CrossChain-Router/v3/cmd/utils"
+	"github.com/anyswap/CrossChain-Router/v3/log"
+	"github.com/anyswap/CrossChain-Router/v3/params"
+	"github.com/anyswap/CrossChain-Router/v3/rpc/rpcapi"
+)
+
+// StartOracleAPIServer start oracle local api server (listen on localhost)
+func StartOracleAPIServer() {
+	oracleCfg := params.GetRouterOracleConfig()
+	if oracleCfg == nil || oracleCfg.LocalAPIPort == 0 {
+		return
+	}
+	apiPort := oracleCfg.LocalAPIPort
+
+	rpcserver := rpc.NewServer()
+	rpcserver.RegisterCodec(rpcjson.NewCodec(), "application/json")
+	err := rpcserver.RegisterService(new(rpcapi.OracleLedgerAPI), "ledger")
+	if err != nil {
+		log.Fatal("start oracle rpc service failed", "err", err)
+	}
+	router := mux.NewRouter()
+	router.Handle("/rpc", rpcserver)
+
+	log.Info("oracle local JSON RPC service listen and serving", "port", apiPort)
+	svr := http.Server{
+		Addr:         fmt.Sprintf("127.0.0.1:%v", apiPort),
+		ReadTimeout:  60 * time.Second,
+		WriteTimeout: 300 * time.Second,
+		Handler:      router,
+	}
+	go func() {
+		if err := svr.ListenAndServe(); err != nil {
+			if errors.Is(err, http.ErrServerClosed) && utils.IsCleanuping() {
+				return
+			}
+			log.Fatal("ListenAndServe error", "err", err)
+		}
+	}()
+
+	utils.TopWaitGroup.Add(1)
+	go utils.WaitAndCleanup(func() { doCleanup(&svr) })
+}
@@ -127,7 +127,9 @@ func initRouterSwapRouter(r *mux.Router) {
 	r.HandleFunc("/statusinfo", restapi.StatusInfoHandler).Methods("GET")
 	r.HandleFunc("/swap/register/{chainid}/{txid}", restapi.RegisterRouterSwapHandler).Methods("POST")
 	r.HandleFunc("/swap/status/{chainid}/{txid}", restapi.GetRouterSwapHandler).Methods("GET")
+	r.HandleFunc("/swap/swaptx/{swaptx}", restapi.GetRouterSwapBySwapTxHandler).Methods("GET")
 	r.HandleFunc("/swap/history/{chainid}/{address}", restapi.GetRouterSwapHistoryHandler).Methods("GET")
+	r.HandleFunc("/swap/results", restapi.GetSwapResultsSinceHandler).Methods("GET")
 
 	r.HandleFunc("/allchainids", restapi.GetAllChainIDsHandler).Methods("GET")
 	r.HandleFunc("/alltokenids", restapi.GetAllTokenIDsHandler).Methods("GET")
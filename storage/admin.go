@@ -0,0 +1,138 @@
+package storage
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/anyswap/CrossChain-Router/v3/log"
+	"github.com/anyswap/CrossChain-Router/v3/mongodb"
+	"github.com/anyswap/CrossChain-Router/v3/router"
+	"github.com/anyswap/CrossChain-Router/v3/tokens"
+)
+
+// ----------------------------- admin functions -------------------------------------
+
+// RouterAdminPassBigValue pass big value
+func RouterAdminPassBigValue(fromChainID, txid string, logIndex int, actor string) error {
+	swap, err := store.FindRouterSwap(fromChainID, txid, logIndex)
+	if err != nil {
+		return err
+	}
+	if swap.Status != mongodb.TxWithBigValue {
+		return fmt.Errorf("swap status is %v, not big value status %v", swap.Status.String(), mongodb.TxWithBigValue.String())
+	}
+
+	_, err = store.FindRouterSwapResult(fromChainID, txid, logIndex)
+	if err == nil {
+		return fmt.Errorf("can not pass big value swap with result exist")
+	}
+	return store.UpdateRouterSwapStatus(fromChainID, txid, logIndex, mongodb.TxNotSwapped, time.Now().Unix(), "", actor)
+}
+
+// RouterAdminRetrySwap retry swap which is reverted in simulation
+func RouterAdminRetrySwap(fromChainID, txid string, logIndex int, actor string) error {
+	swap, err := store.FindRouterSwap(fromChainID, txid, logIndex)
+	if err != nil {
+		return err
+	}
+	if swap.Status != mongodb.TxSimulateFailed {
+		return fmt.Errorf("swap status is %v, not simulate failed status %v", swap.Status.String(), mongodb.TxSimulateFailed.String())
+	}
+
+	res, err := store.FindRouterSwapResult(fromChainID, txid, logIndex)
+	if err != nil {
+		return err
+	}
+	if res.Status != mongodb.MatchTxEmpty || res.SwapTx != "" || res.SwapNonce != 0 {
+		return fmt.Errorf("can not retry swap with result status %v", res.Status.String())
+	}
+	return store.UpdateRouterSwapStatus(fromChainID, txid, logIndex, mongodb.TxNotSwapped, time.Now().Unix(), "", actor)
+}
+
+// RouterAdminReswap reswap
+func RouterAdminReswap(fromChainID, txid string, logIndex int, actor string) error {
+	swap, err := store.FindRouterSwap(fromChainID, txid, logIndex)
+	if err != nil {
+		return err
+	}
+	if swap.Status != mongodb.TxProcessed {
+		return fmt.Errorf("swap status is %v, can not reswap", swap.Status.String())
+	}
+
+	res, err := store.FindRouterSwapResult(fromChainID, txid, logIndex)
+	if err != nil {
+		return err
+	}
+	if res.Status != mongodb.MatchTxFailed {
+		return fmt.Errorf("swap result status is %v, can not reswap", res.Status.String())
+	}
+
+	if res.SwapTx == "" {
+		return errors.New("swap without swaptx")
+	}
+
+	resBridge := router.GetBridgeByChainID(swap.ToChainID)
+	if resBridge == nil {
+		return tokens.ErrNoBridgeForChainID
+	}
+
+	txStatus, txHash := getSwapResultsTxStatus(resBridge, res)
+	if txStatus != nil && txStatus.BlockHeight > 0 && !txStatus.IsSwapTxOnChainAndFailed() {
+		_ = store.UpdateRouterSwapResultStatus(fromChainID, txid, logIndex, mongodb.MatchTxNotStable, time.Now().Unix(), "", actor)
+		return fmt.Errorf("swap succeed with swaptx %v", txHash)
+	}
+
+	nonceSetter, ok := resBridge.(tokens.NonceSetter)
+	if ok {
+		mpcAddress := res.MPC
+		nonce, errf := nonceSetter.GetPoolNonce(mpcAddress, "latest")
+		if errf != nil {
+			log.Warn("get router mpc nonce failed", "address", mpcAddress)
+			return errf
+		}
+		if nonce <= res.SwapNonce {
+			return errors.New("can not retry swap with lower nonce")
+		}
+	}
+
+	log.Info("[reswap] update status to TxNotSwapped", "chainid", fromChainID, "txid", txid, "logIndex", logIndex, "swaptx", res.SwapTx)
+
+	err = store.UpdateRouterSwapResultStatus(fromChainID, txid, logIndex, mongodb.Reswapping, time.Now().Unix(), "", actor)
+	if err != nil {
+		return err
+	}
+
+	return store.UpdateRouterSwapStatus(fromChainID, txid, logIndex, mongodb.TxNotSwapped, time.Now().Unix(), "", actor)
+}
+
+func getSwapResultsTxStatus(bridge tokens.IBridge, res *mongodb.MgoSwapResult) (status *tokens.TxStatus, txHash string) {
+	var err error
+	if status, err = bridge.GetTransactionStatus(res.SwapTx); err == nil {
+		return status, res.SwapTx
+	}
+	for _, tx := range res.OldSwapTxs {
+		if status, err = bridge.GetTransactionStatus(tx); err == nil {
+			return status, tx
+		}
+	}
+	return nil, ""
+}
+
+// ----------------------------- helper functions -------------------------------------
+
+// GetRegisteredRouterSwap get registered router swap
+func GetRegisteredRouterSwap(fromChainID, txid string, logIndex int) (oldSwap *mongodb.MgoSwap, registeredOk bool) {
+	oldSwap, err := store.FindRouterSwap(fromChainID, txid, logIndex)
+	if err != nil || oldSwap == nil {
+		return nil, false
+	}
+	if oldSwap.Status.IsRegisteredOk() {
+		return oldSwap, true
+	}
+	oldSwapRes, err := store.FindRouterSwapResult(fromChainID, txid, logIndex)
+	if err == nil && oldSwapRes != nil {
+		return oldSwap, true
+	}
+	return oldSwap, false
+}
@@ -0,0 +1,459 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/anyswap/CrossChain-Router/v3/mongodb"
+	"github.com/anyswap/CrossChain-Router/v3/tokens"
+)
+
+// testConformance is the conformance test suite every storage backend must pass.
+// it uses unique chain IDs and tx hashes, so it can run on a non empty database.
+func testConformance(t *testing.T, s Storage) {
+	chainID := fmt.Sprintf("%d", time.Now().UnixNano())
+	toChainID := chainID + "1"
+	t.Run("swaps", func(t *testing.T) { testSwaps(t, s, chainID, toChainID) })
+	t.Run("results", func(t *testing.T) { testSwapResults(t, s, chainID, toChainID) })
+	t.Run("nonces", func(t *testing.T) { testSwapNonces(t, s, chainID, toChainID) })
+	t.Run("usedr", func(t *testing.T) { testUsedRValues(t, s, chainID) })
+	t.Run("export", func(t *testing.T) { testExport(t, s, chainID+"3", toChainID) })
+	t.Run("swaptx", func(t *testing.T) { testSwapTxLookup(t, s, chainID+"4", toChainID) })
+	if w, ok := s.(Watcher); ok {
+		t.Run("watch", func(t *testing.T) { testWatcher(t, s, w, chainID+"2", toChainID) })
+	}
+}
+
+func newTestTxID(chainID string, i int) string {
+	hash := fmt.Sprintf("%s%04d", chainID, i)
+	return "0x" + strings.Repeat("0", 64-len(hash)) + hash
+}
+
+func newTestSwap(fromChainID, toChainID, txid string, status mongodb.SwapStatus) *mongodb.MgoSwap {
+	return &mongodb.MgoSwap{
+		SwapType:    uint32(tokens.ERC20SwapType),
+		TxID:        txid,
+		From:        "0xAbCd000000000000000000000000000000000001",
+		Bind:        "0xabcd000000000000000000000000000000000002",
+		Value:       "1000",
+		LogIndex:    1,
+		FromChainID: fromChainID,
+		ToChainID:   toChainID,
+		SwapInfo: mongodb.SwapInfo{ERC20SwapInfo: &mongodb.ERC20SwapInfo{
+			Token:   "0x0000000000000000000000000000000000000003",
+			TokenID: "USDC",
+		}},
+		Status:    status,
+		Timestamp: time.Now().Unix(),
+	}
+}
+
+func checkErrIs(t *testing.T, err, target error, msg string) {
+	t.Helper()
+	if !errors.Is(err, target) {
+		t.Fatalf("%v: expect error %v, got %v", msg, target, err)
+	}
+}
+
+func checkNoErr(t *testing.T, err error, msg string) {
+	t.Helper()
+	if err != nil {
+		t.Fatalf("%v: %v", msg, err)
+	}
+}
+
+func testSwaps(t *testing.T, s Storage, chainID, toChainID string) {
+	txid := newTestTxID(chainID, 1)
+	swap := newTestSwap(chainID, toChainID, txid, mongodb.TxNotStable)
+	checkNoErr(t, s.AddRouterSwap(swap), "add swap")
+	checkErrIs(t, s.AddRouterSwap(newTestSwap(chainID, toChainID, txid, mongodb.TxNotStable)), mongodb.ErrItemIsDup, "add duplicate swap")
+
+	found, err := s.FindRouterSwap(chainID, txid, 1)
+	checkNoErr(t, err, "find swap")
+	if found.Key != mongodb.GetRouterSwapKey(chainID, txid, 1) || found.InitTime == 0 || found.Version != 0 ||
+		found.From != swap.From || found.GetTokenID() != "USDC" {
+		t.Fatalf("wrong swap found: %+v", found)
+	}
+	_, err = s.FindRouterSwap(chainID, txid, 2)
+	checkErrIs(t, err, mongodb.ErrItemNotFound, "find not exist swap")
+
+	found, err = s.FindRouterSwapAuto(chainID, strings.ToUpper(txid[2:]), 0)
+	checkNoErr(t, err, "find swap auto")
+	if found.LogIndex != 1 {
+		t.Fatalf("wrong swap found auto: %+v", found)
+	}
+
+	err = s.UpdateRouterSwapStatus(chainID, txid, 1, mongodb.TxNotStable, time.Now().Unix(), "", "test")
+	if err == nil {
+		t.Fatal("update swap status to TxNotStable should be forbidden")
+	}
+	checkErrIs(t, s.UpdateRouterSwapStatus(chainID, txid, 2, mongodb.TxNotSwapped, time.Now().Unix(), "", "test"), mongodb.ErrItemNotFound, "update not exist swap status")
+	checkNoErr(t, s.PassRouterSwapVerify(chainID, txid, 1, time.Now().Unix()), "pass verify")
+	if s.PassRouterSwapVerify(chainID, txid, 1, time.Now().Unix()) == nil {
+		t.Fatal("pass verify twice should fail")
+	}
+
+	swaps, err := s.FindRouterSwapsWithChainIDAndStatus(chainID, mongodb.TxNotSwapped, 0)
+	checkNoErr(t, err, "find swaps with status")
+	if len(swaps) != 1 || swaps[0].TxID != txid {
+		t.Fatalf("wrong swaps with status: %v", len(swaps))
+	}
+	swaps, err = s.FindRouterSwapsWithStatus(mongodb.TxNotSwapped, time.Now().Unix()-60)
+	checkNoErr(t, err, "find swaps with status of all chains")
+	if !containsSwap(swaps, txid) {
+		t.Fatal("swaps with status of all chains miss the swap")
+	}
+
+	err = s.UpdateRouterSwapStatus(chainID, txid, 1, mongodb.TxWithBigValue, time.Now().Unix(), "big value", "test")
+	checkErrIs(t, err, mongodb.ErrIllegalStatusTransition, "update swap status from TxNotSwapped to TxWithBigValue")
+	checkNoErr(t, s.UpdateRouterSwapStatus(chainID, txid, 1, mongodb.SwapInBlacklist, time.Now().Unix(), "blacklist", "test"), "update swap status")
+	found, _ = s.FindRouterSwap(chainID, txid, 1)
+	if found.Status != mongodb.SwapInBlacklist || found.Memo != "blacklist" || found.Version != 2 {
+		t.Fatalf("wrong swap after update status: %v %v", found.Status, found.Memo)
+	}
+	history := found.StatusHistory
+	if len(history) != 2 ||
+		history[0].From != mongodb.TxNotStable || history[0].To != mongodb.TxNotSwapped || history[0].Actor != "verify" ||
+		history[1].From != mongodb.TxNotSwapped || history[1].To != mongodb.SwapInBlacklist || history[1].Actor != "test" || history[1].Memo != "blacklist" {
+		t.Fatalf("wrong swap status history: %v", len(history))
+	}
+
+	newInfo := &mongodb.SwapInfo{ERC20SwapInfo: &mongodb.ERC20SwapInfo{TokenID: "USDT"}}
+	err = s.UpdateRouterSwapInfoAndStatus(chainID, txid, 1, newInfo, mongodb.TxNotSwapped, time.Now().Unix(), "", "test")
+	checkErrIs(t, err, mongodb.ErrIllegalStatusTransition, "re-register swap to TxNotSwapped")
+	checkNoErr(t, s.UpdateRouterSwapInfoAndStatus(chainID, txid, 1, newInfo, mongodb.TxNotStable, time.Now().Unix(), "", "test"), "update swap info")
+	found, _ = s.FindRouterSwap(chainID, txid, 1)
+	if found.GetTokenID() != "USDT" || found.Status != mongodb.TxNotStable || len(found.StatusHistory) != 3 {
+		t.Fatalf("wrong swap after update swap info: %v %v", found.GetTokenID(), found.Status)
+	}
+	if s.UpdateRouterSwapInfoAndStatus(chainID, txid, 1, newInfo, mongodb.TxNotStable, time.Now().Unix(), "", "test") == nil {
+		t.Fatal("update swap info of registered ok swap should be forbidden")
+	}
+}
+
+func containsSwap(swaps []*mongodb.MgoSwap, txid string) bool {
+	for _, swap := range swaps {
+		if swap.TxID == txid {
+			return true
+		}
+	}
+	return false
+}
+
+func containsResult(results []*mongodb.MgoSwapResult, txid string) bool {
+	for _, res := range results {
+		if res.TxID == txid {
+			return true
+		}
+	}
+	return false
+}
+
+func addTestSwapWithResult(t *testing.T, s Storage, chainID, toChainID, txid string) {
+	t.Helper()
+	swap := newTestSwap(chainID, toChainID, txid, mongodb.TxNotSwapped)
+	checkNoErr(t, s.AddRouterSwap(swap), "add swap")
+	res := swap.ToSwapResult()
+	res.Status = mongodb.MatchTxEmpty
+	checkNoErr(t, s.AddRouterSwapResult(res), "add swap result")
+}
+
+func testSwapResults(t *testing.T, s Storage, chainID, toChainID string) {
+	txid := newTestTxID(chainID, 2)
+	addTestSwapWithResult(t, s, chainID, toChainID, txid)
+	checkErrIs(t, s.AddRouterSwapResult(&mongodb.MgoSwapResult{FromChainID: chainID, TxID: txid, LogIndex: 1}), mongodb.ErrItemIsDup, "add duplicate result")
+
+	res, err := s.FindRouterSwapResult(chainID, txid, 1)
+	checkNoErr(t, err, "find swap result")
+	if res.Status != mongodb.MatchTxEmpty || res.InitTime == 0 || res.ToChainID != toChainID {
+		t.Fatalf("wrong swap result found: %+v", res)
+	}
+	swap, err := s.FindRouterSwap(chainID, txid, 1)
+	checkNoErr(t, err, "find swap of result")
+	if res.InitTime != swap.InitTime {
+		t.Fatalf("given init time is not kept, have %v want %v", res.InitTime, swap.InitTime)
+	}
+	_, err = s.FindRouterSwapResultAuto(chainID, txid, 0)
+	checkNoErr(t, err, "find swap result auto")
+
+	now := time.Now().Unix()
+	items := &mongodb.SwapResultUpdateItems{
+		MPC:       "0x00000000000000000000000000000000000000ff",
+		SwapTx:    "0x01",
+		SwapValue: "990",
+		Status:    mongodb.MatchTxNotStable,
+		Timestamp: now,
+	}
+	checkNoErr(t, s.UpdateRouterSwapResult(chainID, txid, 1, items), "update swap result")
+	checkErrIs(t, s.UpdateRouterSwapResult(chainID, txid, 1, items), mongodb.ErrForbidUpdateSwapTx, "update swap tx again")
+
+	checkNoErr(t, s.UpdateRouterOldSwapTxs(chainID, txid, 1, "0x02"), "update old swap txs")
+	checkNoErr(t, s.UpdateRouterOldSwapTxs(chainID, txid, 1, "0x02"), "update old swap txs again")
+	checkNoErr(t, s.UpdateRouterOldSwapTxs(chainID, txid, 1, "0x03"), "update old swap txs third")
+	checkNoErr(t, s.AddRouterSwapTxAttempt(chainID, txid, 1, &mongodb.SwapTxAttempt{SwapTx: "0x03", GasPrice: "10"}), "add swap tx attempt")
+	checkNoErr(t, s.AddRouterSwapResultDisagree(chainID, txid, 1, &mongodb.DisagreeReason{Oracle: "aa", Code: "MsgHashMismatch"}), "add disagree")
+
+	res, _ = s.FindRouterSwapResult(chainID, txid, 1)
+	if res.SwapTx != "0x03" || strings.Join(res.OldSwapTxs, ",") != "0x01,0x02,0x03" {
+		t.Fatalf("wrong swap txs: %v %v", res.SwapTx, res.OldSwapTxs)
+	}
+	// one result update and two old swaptxs updates (tx attempts and disagrees keep version)
+	if res.Status != mongodb.MatchTxNotStable || res.SwapValue != "990" || res.MPC != items.MPC || res.Version != 3 {
+		t.Fatalf("wrong swap result after update: %+v", res)
+	}
+	if len(res.SwapTxAttempts) != 1 || res.SwapTxAttempts[0].GasPrice != "10" {
+		t.Fatalf("wrong swap tx attempts: %v", res.SwapTxAttempts)
+	}
+	if len(res.Disagrees) != 1 || res.Disagrees[0].Code != "MsgHashMismatch" {
+		t.Fatalf("wrong disagrees: %v", res.Disagrees)
+	}
+
+	oldSwapTx := "0x02"
+	staleItems := &mongodb.SwapResultUpdateItems{SwapTx: "0x02", Status: mongodb.KeepStatus, Timestamp: now, ExpectedSwapTx: &oldSwapTx}
+	checkErrIs(t, s.UpdateRouterSwapResult(chainID, txid, 1, staleItems), mongodb.ErrSwapTxModified, "update swap tx with stale swaptx")
+
+	results, err := s.FindRouterSwapResultsWithChainIDAndStatus(chainID, mongodb.MatchTxNotStable, now)
+	checkNoErr(t, err, "find results with status")
+	if len(results) != 1 {
+		t.Fatalf("wrong results with status: %v", len(results))
+	}
+	results, err = s.FindRouterSwapResultsWithStatus(mongodb.MatchTxNotStable, now)
+	checkNoErr(t, err, "find results with status of all chains")
+	if !containsResult(results, txid) {
+		t.Fatal("results with status of all chains miss the result")
+	}
+	results, err = s.FindRouterSwapResultsSince(now, "", 1000)
+	checkNoErr(t, err, "find results since")
+	if !containsResult(results, txid) {
+		t.Fatal("results since miss the result")
+	}
+	res, _ = s.FindRouterSwapResult(chainID, txid, 1)
+	results, err = s.FindRouterSwapResultsSince(res.Timestamp, res.Key, 1000)
+	checkNoErr(t, err, "find results since key")
+	if containsResult(results, txid) {
+		t.Fatal("results since key contain the result")
+	}
+	results, err = s.FindRouterSwapResultsToStable(toChainID, 0)
+	checkNoErr(t, err, "find results to stable")
+	if len(results) != 1 {
+		t.Fatalf("wrong results to stable: %v", len(results))
+	}
+	results, err = s.FindRouterSwapResultsToReplace(toChainID, 0)
+	checkNoErr(t, err, "find results to replace")
+	if len(results) != 1 {
+		t.Fatalf("wrong results to replace: %v", len(results))
+	}
+
+	results, err = s.FindRouterSwapResults(chainID, "0xabcd000000000000000000000000000000000001", 0, 10, "")
+	checkNoErr(t, err, "find results of address")
+	if len(results) != 1 {
+		t.Fatalf("wrong results of address: %v", len(results))
+	}
+	results, err = s.FindRouterSwapResults(chainID, "all", 0, 10, fmt.Sprint(uint32(mongodb.TxNotSwapped)))
+	checkNoErr(t, err, "find registered swaps")
+	if len(results) != 1 || results[0].Status != mongodb.TxNotSwapped {
+		t.Fatalf("wrong registered swaps: %v", len(results))
+	}
+
+	info, err := s.GetStatusInfo(fmt.Sprint(uint32(mongodb.MatchTxNotStable)))
+	checkNoErr(t, err, "get status info")
+	if _, exist := info[fmt.Sprint(uint32(mongodb.MatchTxNotStable))]; !exist {
+		t.Fatalf("wrong status info: %v", info)
+	}
+
+	err = s.UpdateRouterSwapResultStatus(chainID, txid, 1, mongodb.Reswapping, time.Now().Unix(), "reswap", "test")
+	checkErrIs(t, err, mongodb.ErrIllegalStatusTransition, "update result status from MatchTxNotStable to Reswapping")
+	checkNoErr(t, s.UpdateRouterSwapResultStatus(chainID, txid, 1, mongodb.MatchTxFailed, time.Now().Unix(), "", "stable"), "update result status to failed")
+	checkNoErr(t, s.UpdateRouterSwapResultStatus(chainID, txid, 1, mongodb.Reswapping, time.Now().Unix(), "reswap", "test"), "update result status")
+	res, _ = s.FindRouterSwapResult(chainID, txid, 1)
+	if res.Status != mongodb.Reswapping || res.SwapTx != "" || len(res.OldSwapTxs) != 0 || res.Memo != "" {
+		t.Fatalf("wrong swap result after reswapping: %+v", res)
+	}
+	history := res.StatusHistory
+	if len(history) != 3 || history[0].To != mongodb.MatchTxNotStable ||
+		history[1].To != mongodb.MatchTxFailed || history[1].Actor != "stable" ||
+		history[2].From != mongodb.MatchTxFailed || history[2].To != mongodb.Reswapping {
+		t.Fatalf("wrong swap result status history: %v", len(history))
+	}
+}
+
+func testSwapNonces(t *testing.T, s Storage, chainID, toChainID string) {
+	mpc := "0x00000000000000000000000000000000000000Ee"
+	fromChainID, _ := new(big.Int).SetString(chainID, 10)
+
+	_, err := s.FindNextSwapNonce(toChainID, mpc)
+	checkErrIs(t, err, mongodb.ErrItemNotFound, "find next nonce of no swaps")
+
+	nonce := uint64(5)
+	for i := 3; i < 6; i++ {
+		txid := newTestTxID(chainID, i)
+		addTestSwapWithResult(t, s, chainID, toChainID, txid)
+		args := &tokens.BuildTxArgs{
+			SwapArgs: tokens.SwapArgs{
+				SwapID:      txid,
+				LogIndex:    1,
+				FromChainID: fromChainID,
+			},
+			From:      mpc,
+			SwapValue: big.NewInt(100),
+		}
+		swapNonce, errf := s.AllocateRouterSwapNonce(args, &nonce, false)
+		checkNoErr(t, errf, "allocate swap nonce")
+		if swapNonce != uint64(i+2) || nonce != uint64(i+3) {
+			t.Fatalf("wrong allocated swap nonce %v, next %v", swapNonce, nonce)
+		}
+		_, errf = s.AllocateRouterSwapNonce(args, &nonce, false)
+		checkErrIs(t, errf, mongodb.ErrForbidUpdateNonce, "allocate swap nonce again")
+
+		swap, _ := s.FindRouterSwap(chainID, txid, 1)
+		if swap.Status != mongodb.TxProcessed {
+			t.Fatalf("wrong swap status after allocate nonce: %v", swap.Status)
+		}
+	}
+
+	next, err := s.FindNextSwapNonce(toChainID, strings.ToLower(mpc))
+	checkNoErr(t, err, "find next swap nonce")
+	if next != 8 {
+		t.Fatalf("wrong next swap nonce %v", next)
+	}
+
+	results, err := s.FindRouterSwapResultsWithNonceRange(toChainID, mpc, 5, 7)
+	checkNoErr(t, err, "find results with nonce range")
+	if len(results) != 2 || results[0].SwapNonce != 5 || results[1].SwapNonce != 6 {
+		t.Fatalf("wrong results with nonce range: %v", len(results))
+	}
+
+	results, err = s.FindRouterSwapResultsWithPassedNonce(toChainID, mpc, 7)
+	checkNoErr(t, err, "find results with passed nonce")
+	if len(results) != 2 || results[0].SwapNonce != 5 {
+		t.Fatalf("wrong results with passed nonce: %v", len(results))
+	}
+
+	recycled := uint64(9)
+	txid := newTestTxID(chainID, 6)
+	addTestSwapWithResult(t, s, chainID, toChainID, txid)
+	args := &tokens.BuildTxArgs{
+		SwapArgs: tokens.SwapArgs{SwapID: txid, LogIndex: 1, FromChainID: fromChainID},
+		From:     mpc,
+	}
+	swapNonce, err := s.AllocateRouterSwapNonce(args, &recycled, true)
+	checkNoErr(t, err, "allocate recycled swap nonce")
+	if swapNonce != 9 || recycled != 0 {
+		t.Fatalf("wrong allocated recycled nonce %v, left %v", swapNonce, recycled)
+	}
+}
+
+func testUsedRValues(t *testing.T, s Storage, chainID string) {
+	r := "0x" + chainID
+	checkNoErr(t, s.AddUsedRValue("pubkey", r), "add used r")
+	checkErrIs(t, s.AddUsedRValue("pubkey", strings.ToUpper(r)), mongodb.ErrItemIsDup, "add duplicate used r")
+	checkNoErr(t, s.AddUsedRValue("pubkey2", r), "add used r of another pubkey")
+}
+
+func testWatcher(t *testing.T, s Storage, w Watcher, chainID, toChainID string) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	events := make(chan *mongodb.SwapEvent, 10)
+	watchErr := make(chan error, 1)
+	go func() {
+		watchErr <- w.WatchRouterSwaps(ctx, []mongodb.SwapStatus{mongodb.TxNotSwapped}, func(ev *mongodb.SwapEvent) {
+			if ev.FromChainID == chainID {
+				select {
+				case events <- ev:
+				default:
+				}
+			}
+		})
+	}()
+
+	// watching starts asynchronously, so add swaps until receiving event
+	timeout := time.After(10 * time.Second)
+	for i := 1; ; i++ {
+		_ = s.AddRouterSwap(newTestSwap(chainID, toChainID, newTestTxID(chainID, i), mongodb.TxNotSwapped))
+		_ = s.AddRouterSwap(newTestSwap(chainID, toChainID, newTestTxID(chainID, 1000+i), mongodb.TxNotStable))
+		select {
+		case ev := <-events:
+			if ev.Status != mongodb.TxNotSwapped || ev.ToChainID != toChainID || ev.Key == "" {
+				t.Fatalf("wrong swap event: %+v", ev)
+			}
+			return
+		case err := <-watchErr:
+			if err != nil {
+				t.Skipf("watch is not supported: %v", err)
+			}
+			t.Fatal("watch stopped unexpectedly")
+		case <-timeout:
+			t.Fatal("wait swap event timeout")
+		case <-time.After(100 * time.Millisecond):
+		}
+	}
+}
+
+func testExport(t *testing.T, s Storage, chainID, toChainID string) {
+	for i := 1; i <= 5; i++ {
+		addTestSwapWithResult(t, s, chainID, toChainID, newTestTxID(chainID, i))
+	}
+
+	filter := &mongodb.ExportFilter{FromChainID: chainID, TokenID: "USDC"}
+	var exported []*mongodb.MgoSwapResult
+	for {
+		results, err := s.FindRouterSwapResultsToExport(filter, 2)
+		checkNoErr(t, err, "find swap results to export")
+		if len(results) == 0 {
+			break
+		}
+		exported = append(exported, results...)
+		last := results[len(results)-1]
+		filter.AfterInitTime, filter.AfterKey = last.InitTime, last.Key
+	}
+	if len(exported) != 5 {
+		t.Fatalf("expect 5 exported swap results, got %v", len(exported))
+	}
+	for i := 1; i < len(exported); i++ {
+		if exported[i].InitTime < exported[i-1].InitTime || exported[i].Key == exported[i-1].Key {
+			t.Fatalf("exported swap results are not in order or duplicated")
+		}
+	}
+
+	filter = &mongodb.ExportFilter{FromChainID: chainID, Statuses: []mongodb.SwapStatus{mongodb.MatchTxStable}}
+	results, err := s.FindRouterSwapResultsToExport(filter, 10)
+	checkNoErr(t, err, "find stable swap results to export")
+	if len(results) != 0 {
+		t.Fatalf("expect no stable swap results, got %v", len(results))
+	}
+}
+
+func testSwapTxLookup(t *testing.T, s Storage, chainID, toChainID string) {
+	txid := newTestTxID(chainID, 1)
+	addTestSwapWithResult(t, s, chainID, toChainID, txid)
+	oldSwapTx, swapTx := newTestTxID(chainID, 11), newTestTxID(chainID, 12)
+	items := &mongodb.SwapResultUpdateItems{
+		SwapTx:    oldSwapTx,
+		Status:    mongodb.MatchTxNotStable,
+		Timestamp: time.Now().Unix(),
+	}
+	checkNoErr(t, s.UpdateRouterSwapResult(chainID, txid, 1, items), "update swap result")
+	checkNoErr(t, s.UpdateRouterOldSwapTxs(chainID, txid, 1, swapTx), "update old swap txs")
+
+	checkSwapTxLookup := func(tx string, wantCurrent bool) {
+		t.Helper()
+		res, err := s.FindRouterSwapResultBySwapTx(tx)
+		checkNoErr(t, err, "find swap result by swap tx")
+		matched, isCurrent := mongodb.MatchSwapTx(res, tx)
+		if res.TxID != txid || !matched || isCurrent != wantCurrent {
+			t.Fatalf("wrong swap result found by swap tx %v: %v %v %v", tx, res.TxID, matched, isCurrent)
+		}
+	}
+	checkSwapTxLookup(swapTx, true)
+	checkSwapTxLookup(oldSwapTx, false)
+	checkSwapTxLookup(strings.ToUpper(swapTx), true)
+
+	_, err := s.FindRouterSwapResultBySwapTx(newTestTxID(chainID, 13))
+	checkErrIs(t, err, mongodb.ErrItemNotFound, "find swap result by unknown swap tx")
+}
@@ -0,0 +1,988 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/anyswap/CrossChain-Router/v3/common"
+	"github.com/anyswap/CrossChain-Router/v3/leveldb"
+	"github.com/anyswap/CrossChain-Router/v3/log"
+	"github.com/anyswap/CrossChain-Router/v3/mongodb"
+	"github.com/anyswap/CrossChain-Router/v3/tokens"
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+const (
+	embeddedSwapPrefix   = "swap:"
+	embeddedResultPrefix = "result:"
+	embeddedUsedRPrefix  = "usedr:"
+
+	maxCountOfResults  = 1000
+	maxDisagreesOfSwap = 50
+	maxStatusHistory   = 100
+)
+
+var (
+	_ Storage = &EmbeddedStorage{}
+	_ Watcher = &EmbeddedStorage{}
+)
+
+// EmbeddedStorage storage on embedded leveldb (for single node deployment and tests).
+// queries scan all the records, so it is not suitable for large amount of swaps.
+type EmbeddedStorage struct {
+	db   leveldb.KeyValueStore
+	lock sync.RWMutex
+
+	watchLock      sync.Mutex
+	swapWatchers   map[*embeddedWatcher]struct{}
+	resultWatchers map[*embeddedWatcher]struct{}
+}
+
+type embeddedWatcher struct {
+	statuses []mongodb.SwapStatus
+	onEvent  func(*mongodb.SwapEvent)
+}
+
+// NewEmbeddedStorage new embedded storage in directory `path` (in memory if path is empty)
+func NewEmbeddedStorage(path string) (*EmbeddedStorage, error) {
+	var db leveldb.KeyValueStore
+	var err error
+	if path == "" {
+		db, err = leveldb.NewMemory()
+	} else {
+		db, err = leveldb.New(path, 0, 0, false)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &EmbeddedStorage{
+		db:             db,
+		swapWatchers:   make(map[*embeddedWatcher]struct{}),
+		resultWatchers: make(map[*embeddedWatcher]struct{}),
+	}, nil
+}
+
+// Close close database
+func (s *EmbeddedStorage) Close() error {
+	return s.db.Close()
+}
+
+func (s *EmbeddedStorage) get(key string, out interface{}) error {
+	data, err := s.db.Get([]byte(key))
+	if err != nil {
+		if leveldb.IsNotFoundErr(err) {
+			return mongodb.ErrItemNotFound
+		}
+		return err
+	}
+	return bson.Unmarshal(data, out)
+}
+
+func (s *EmbeddedStorage) put(key string, val interface{}) error {
+	data, err := bson.Marshal(val)
+	if err != nil {
+		return err
+	}
+	return s.db.Put([]byte(key), data)
+}
+
+func (s *EmbeddedStorage) getSwap(key string) (*mongodb.MgoSwap, error) {
+	swap := &mongodb.MgoSwap{}
+	if err := s.get(embeddedSwapPrefix+key, swap); err != nil {
+		return nil, err
+	}
+	return swap, nil
+}
+
+func (s *EmbeddedStorage) putSwap(swap *mongodb.MgoSwap) error {
+	err := s.put(embeddedSwapPrefix+swap.Key, swap)
+	if err == nil {
+		s.notifyWatchers(s.swapWatchers, swap.Key, swap.FromChainID, swap.ToChainID, swap.Status)
+	}
+	return err
+}
+
+// updateSwap put updated swap and increase its version (same as mongodb)
+func (s *EmbeddedStorage) updateSwap(swap *mongodb.MgoSwap) error {
+	swap.Version++
+	return s.putSwap(swap)
+}
+
+func (s *EmbeddedStorage) getResult(key string) (*mongodb.MgoSwapResult, error) {
+	res := &mongodb.MgoSwapResult{}
+	if err := s.get(embeddedResultPrefix+key, res); err != nil {
+		return nil, err
+	}
+	return res, nil
+}
+
+func (s *EmbeddedStorage) putResult(res *mongodb.MgoSwapResult) error {
+	err := s.put(embeddedResultPrefix+res.Key, res)
+	if err == nil {
+		s.notifyWatchers(s.resultWatchers, res.Key, res.FromChainID, res.ToChainID, res.Status)
+	}
+	return err
+}
+
+// updateResult put updated swap result and increase its version (same as mongodb)
+func (s *EmbeddedStorage) updateResult(res *mongodb.MgoSwapResult) error {
+	res.Version++
+	return s.putResult(res)
+}
+
+func (s *EmbeddedStorage) filterSwaps(filter func(*mongodb.MgoSwap) bool) ([]*mongodb.MgoSwap, error) {
+	iter := s.db.NewIterator([]byte(embeddedSwapPrefix), nil)
+	defer iter.Release()
+	result := make([]*mongodb.MgoSwap, 0, 20)
+	for iter.Next() {
+		swap := &mongodb.MgoSwap{}
+		if err := bson.Unmarshal(iter.Value(), swap); err != nil {
+			return nil, err
+		}
+		if filter(swap) {
+			result = append(result, swap)
+		}
+	}
+	return result, iter.Error()
+}
+
+func (s *EmbeddedStorage) filterResults(filter func(*mongodb.MgoSwapResult) bool) ([]*mongodb.MgoSwapResult, error) {
+	iter := s.db.NewIterator([]byte(embeddedResultPrefix), nil)
+	defer iter.Release()
+	result := make([]*mongodb.MgoSwapResult, 0, 20)
+	for iter.Next() {
+		res := &mongodb.MgoSwapResult{}
+		if err := bson.Unmarshal(iter.Value(), res); err != nil {
+			return nil, err
+		}
+		if filter(res) {
+			result = append(result, res)
+		}
+	}
+	return result, iter.Error()
+}
+
+// containsFold is the same as mongodb case insensitive regex query of plain string
+func containsFold(s, substr string) bool {
+	return strings.Contains(strings.ToLower(s), strings.ToLower(substr))
+}
+
+func limitSwaps(swaps []*mongodb.MgoSwap, offset, limit int) []*mongodb.MgoSwap {
+	if offset >= len(swaps) {
+		return swaps[:0]
+	}
+	swaps = swaps[offset:]
+	if limit > 0 && len(swaps) > limit {
+		swaps = swaps[:limit]
+	}
+	return swaps
+}
+
+func limitResults(results []*mongodb.MgoSwapResult, offset, limit int) []*mongodb.MgoSwapResult {
+	if offset >= len(results) {
+		return results[:0]
+	}
+	results = results[offset:]
+	if limit > 0 && len(results) > limit {
+		results = results[:limit]
+	}
+	return results
+}
+
+func sortResultsByNonce(results []*mongodb.MgoSwapResult) {
+	sort.SliceStable(results, func(i, j int) bool {
+		return results[i].SwapNonce < results[j].SwapNonce
+	})
+}
+
+// AddRouterSwap impl
+func (s *EmbeddedStorage) AddRouterSwap(ms *mongodb.MgoSwap) error {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	ms.Key = mongodb.GetRouterSwapKey(ms.FromChainID, ms.TxID, ms.LogIndex)
+	if ms.InitTime == 0 {
+		ms.InitTime = common.NowMilli()
+	}
+	swap, err := s.getSwap(ms.Key)
+	if err == nil {
+		if swap.Status == mongodb.TxNotSwapped {
+			now := time.Now().Unix()
+			if swap.Timestamp+3*24*3600 < now {
+				swap.Timestamp = now
+				_ = s.updateSwap(swap)
+			}
+		}
+		return mongodb.ErrItemIsDup
+	}
+	err = s.putSwap(ms)
+	if err == nil {
+		log.Info("embedded db add router swap success", "chainid", ms.FromChainID, "txid", ms.TxID, "logindex", ms.LogIndex)
+	} else {
+		log.Error("embedded db add router swap failed", "chainid", ms.FromChainID, "txid", ms.TxID, "logindex", ms.LogIndex, "err", err)
+	}
+	return err
+}
+
+// PassRouterSwapVerify impl
+func (s *EmbeddedStorage) PassRouterSwapVerify(fromChainID, txid string, logindex int, timestamp int64) error {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	swap, err := s.getSwap(mongodb.GetRouterSwapKey(fromChainID, txid, logindex))
+	if err != nil {
+		return fmt.Errorf("forbid pass verify as swap is not exist")
+	}
+	if swap.Status != mongodb.TxNotStable {
+		return fmt.Errorf("forbid pass verify as swap status is '%v'", swap.Status)
+	}
+	swap.StatusHistory = appendStatusHistory(swap.StatusHistory, swap.Status, mongodb.TxNotSwapped, "verify", "", timestamp)
+	swap.Status = mongodb.TxNotSwapped
+	swap.Timestamp = timestamp
+	return s.updateSwap(swap)
+}
+
+// UpdateRouterSwapStatus impl
+func (s *EmbeddedStorage) UpdateRouterSwapStatus(fromChainID, txid string, logindex int, status mongodb.SwapStatus, timestamp int64, memo, actor string) error {
+	if status == mongodb.TxNotStable {
+		return errors.New("forbid update swap status to TxNotStable")
+	}
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	return s.updateSwapStatus(mongodb.GetRouterSwapKey(fromChainID, txid, logindex), status, timestamp, memo, actor)
+}
+
+// updateSwapStatus update swap status (must hold lock)
+func (s *EmbeddedStorage) updateSwapStatus(key string, status mongodb.SwapStatus, timestamp int64, memo, actor string) error {
+	swap, err := s.getSwap(key)
+	if err != nil {
+		return err
+	}
+	if err = mongodb.CheckSwapStatusTransition(swap.Status, status); err != nil {
+		log.Warn("embedded db update router swap status forbidden", "key", key, "actor", actor, "err", err)
+		return err
+	}
+	swap.StatusHistory = appendStatusHistory(swap.StatusHistory, swap.Status, status, actor, memo, timestamp)
+	swap.Status = status
+	swap.Timestamp = timestamp
+	if memo != "" {
+		swap.Memo = memo
+	} else if status == mongodb.TxNotSwapped {
+		swap.Memo = ""
+	}
+	return s.updateSwap(swap)
+}
+
+// UpdateRouterSwapInfoAndStatus impl
+func (s *EmbeddedStorage) UpdateRouterSwapInfoAndStatus(fromChainID, txid string, logindex int, swapInfo *mongodb.SwapInfo, status mongodb.SwapStatus, timestamp int64, memo, actor string) error {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	key := mongodb.GetRouterSwapKey(fromChainID, txid, logindex)
+	swap, err := s.getSwap(key)
+	if err != nil {
+		return fmt.Errorf("forbid update swap info if swap is not exist")
+	}
+	if swap.Status.IsRegisteredOk() {
+		return fmt.Errorf("forbid update swap info from registered status %v", swap.Status.String())
+	}
+	if _, err = s.getResult(key); err == nil {
+		return fmt.Errorf("forbid update swap info if swap result exists")
+	}
+	if err = mongodb.CheckSwapStatusTransition(swap.Status, status); err != nil {
+		return err
+	}
+	swap.StatusHistory = appendStatusHistory(swap.StatusHistory, swap.Status, status, actor, memo, timestamp)
+	swap.SwapInfo = *swapInfo
+	swap.Status = status
+	swap.Timestamp = timestamp
+	swap.InitTime = timestamp * 1000
+	swap.Memo = memo
+	return s.updateSwap(swap)
+}
+
+// FindRouterSwap impl
+func (s *EmbeddedStorage) FindRouterSwap(fromChainID, txid string, logindex int) (*mongodb.MgoSwap, error) {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+
+	return s.getSwap(mongodb.GetRouterSwapKey(fromChainID, txid, logindex))
+}
+
+// FindRouterSwapAuto impl
+func (s *EmbeddedStorage) FindRouterSwapAuto(fromChainID, txid string, logindex int) (*mongodb.MgoSwap, error) {
+	if logindex != 0 {
+		return s.FindRouterSwap(fromChainID, txid, logindex)
+	}
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+
+	swaps, err := s.filterSwaps(func(swap *mongodb.MgoSwap) bool {
+		return swap.FromChainID == fromChainID && containsFold(swap.TxID, txid)
+	})
+	if err != nil {
+		return nil, err
+	}
+	if len(swaps) == 0 {
+		return nil, mongodb.ErrItemNotFound
+	}
+	return swaps[0], nil
+}
+
+// FindRouterSwapsWithStatus impl
+func (s *EmbeddedStorage) FindRouterSwapsWithStatus(status mongodb.SwapStatus, septime int64) ([]*mongodb.MgoSwap, error) {
+	return s.FindRouterSwapsWithChainIDAndStatus("", status, septime)
+}
+
+// FindRouterSwapsWithChainIDAndStatus impl (empty fromChainID means all chains)
+func (s *EmbeddedStorage) FindRouterSwapsWithChainIDAndStatus(fromChainID string, status mongodb.SwapStatus, septime int64) ([]*mongodb.MgoSwap, error) {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+
+	swaps, err := s.filterSwaps(func(swap *mongodb.MgoSwap) bool {
+		return swap.Status == status && swap.Timestamp >= septime &&
+			(fromChainID == "" || swap.FromChainID == fromChainID)
+	})
+	if err != nil {
+		return nil, err
+	}
+	sort.SliceStable(swaps, func(i, j int) bool {
+		return swaps[i].InitTime < swaps[j].InitTime
+	})
+	return limitSwaps(swaps, 0, maxCountOfResults), nil
+}
+
+// AddRouterSwapResult impl
+func (s *EmbeddedStorage) AddRouterSwapResult(mr *mongodb.MgoSwapResult) error {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	mr.Key = mongodb.GetRouterSwapKey(mr.FromChainID, mr.TxID, mr.LogIndex)
+	if mr.InitTime == 0 {
+		mr.InitTime = common.NowMilli()
+	}
+	if _, err := s.getResult(mr.Key); err == nil {
+		return mongodb.ErrItemIsDup
+	}
+	err := s.putResult(mr)
+	if err == nil {
+		log.Info("embedded db add router swap result success", "chainid", mr.FromChainID, "txid", mr.TxID, "logindex", mr.LogIndex)
+	} else {
+		log.Error("embedded db add router swap result failed", "chainid", mr.FromChainID, "txid", mr.TxID, "logindex", mr.LogIndex, "err", err)
+	}
+	return err
+}
+
+// checkRouterSwapResultUpdate forbid update swap nonce or swap tx again (must hold lock)
+func (s *EmbeddedStorage) checkRouterSwapResultUpdate(res *mongodb.MgoSwapResult, swapnonce uint64) error {
+	if res.SwapNonce != 0 {
+		log.Error("forbid update swap nonce again", "old", res.SwapNonce, "new", swapnonce)
+		return mongodb.ErrForbidUpdateNonce
+	}
+	if res.SwapTx != "" {
+		log.Error("forbid update swap tx again", "old", res.SwapTx)
+		return mongodb.ErrForbidUpdateSwapTx
+	}
+	return nil
+}
+
+// UpdateRouterSwapResult impl
+func (s *EmbeddedStorage) UpdateRouterSwapResult(fromChainID, txid string, logindex int, items *mongodb.SwapResultUpdateItems) error {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	res, err := s.getResult(mongodb.GetRouterSwapKey(fromChainID, txid, logindex))
+	if err != nil {
+		return err
+	}
+	if items.ExpectedSwapTx != nil && !strings.EqualFold(res.SwapTx, *items.ExpectedSwapTx) {
+		return mongodb.ErrSwapTxModified
+	}
+	if items.SwapNonce != 0 || items.Status == mongodb.MatchTxNotStable {
+		if err = s.checkRouterSwapResultUpdate(res, items.SwapNonce); err != nil {
+			return err
+		}
+		if items.SwapNonce != 0 {
+			res.SwapNonce = items.SwapNonce
+		}
+	}
+	if items.Status != mongodb.KeepStatus {
+		if err = mongodb.CheckSwapResultStatusTransition(res.Status, items.Status); err != nil {
+			return err
+		}
+		res.StatusHistory = appendStatusHistory(res.StatusHistory, res.Status, items.Status, items.Actor, items.Memo, items.Timestamp)
+		res.Status = items.Status
+	}
+	res.Timestamp = items.Timestamp
+	if items.MPC != "" {
+		res.MPC = items.MPC
+	}
+	if items.SwapTx != "" {
+		res.SwapTx = items.SwapTx
+	}
+	if items.SwapHeight != 0 {
+		res.SwapHeight = items.SwapHeight
+	}
+	if items.SwapTime != 0 {
+		res.SwapTime = items.SwapTime
+	}
+	if items.SwapValue != "" {
+		res.SwapValue = items.SwapValue
+	}
+	if items.SwapGasLimit != 0 {
+		res.SwapGasLimit = items.SwapGasLimit
+	}
+	if items.SwapGasUsed != 0 {
+		res.SwapGasUsed = items.SwapGasUsed
+	}
+	if items.Memo != "" {
+		res.Memo = items.Memo
+	} else if items.Status == mongodb.MatchTxNotStable {
+		res.Memo = ""
+	}
+	return s.updateResult(res)
+}
+
+// UpdateRouterSwapResultStatus impl
+func (s *EmbeddedStorage) UpdateRouterSwapResultStatus(fromChainID, txid string, logindex int, status mongodb.SwapStatus, timestamp int64, memo, actor string) error {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	res, err := s.getResult(mongodb.GetRouterSwapKey(fromChainID, txid, logindex))
+	if err != nil {
+		return err
+	}
+	if err = mongodb.CheckSwapResultStatusTransition(res.Status, status); err != nil {
+		log.Warn("embedded db update swap result status forbidden", "chainid", fromChainID, "txid", txid, "logindex", logindex, "actor", actor, "err", err)
+		return err
+	}
+	res.StatusHistory = appendStatusHistory(res.StatusHistory, res.Status, status, actor, memo, timestamp)
+	res.Status = status
+	res.Timestamp = timestamp
+	if memo != "" {
+		res.Memo = memo
+	}
+	if status == mongodb.Reswapping {
+		res.Memo = ""
+		res.SwapTx = ""
+		res.OldSwapTxs = nil
+		res.SwapHeight = 0
+		res.SwapTime = 0
+		res.SwapNonce = 0
+	}
+	return s.updateResult(res)
+}
+
+// UpdateRouterOldSwapTxs impl
+func (s *EmbeddedStorage) UpdateRouterOldSwapTxs(fromChainID, txid string, logindex int, swapTx string) error {
+	if swapTx == "" {
+		return nil
+	}
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	res, err := s.getResult(mongodb.GetRouterSwapKey(fromChainID, txid, logindex))
+	if err != nil {
+		return err
+	}
+	if strings.EqualFold(swapTx, res.SwapTx) {
+		return nil
+	}
+	for _, oldSwapTx := range res.OldSwapTxs {
+		if strings.EqualFold(swapTx, oldSwapTx) {
+			return nil
+		}
+	}
+	if len(res.OldSwapTxs) == 0 {
+		res.OldSwapTxs = []string{res.SwapTx, swapTx}
+	} else {
+		res.OldSwapTxs = append(res.OldSwapTxs, swapTx)
+	}
+	res.SwapTx = swapTx
+	res.Timestamp = time.Now().Unix()
+	return s.updateResult(res)
+}
+
+// AddRouterSwapTxAttempt impl
+func (s *EmbeddedStorage) AddRouterSwapTxAttempt(fromChainID, txid string, logindex int, attempt *mongodb.SwapTxAttempt) error {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	res, err := s.getResult(mongodb.GetRouterSwapKey(fromChainID, txid, logindex))
+	if err != nil {
+		return ignoreNotFound(err)
+	}
+	res.SwapTxAttempts = append(res.SwapTxAttempts, attempt)
+	return s.putResult(res)
+}
+
+// AddRouterSwapResultDisagree impl
+func (s *EmbeddedStorage) AddRouterSwapResultDisagree(fromChainID, txid string, logindex int, disagree *mongodb.DisagreeReason) error {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	res, err := s.getResult(mongodb.GetRouterSwapKey(fromChainID, txid, logindex))
+	if err != nil {
+		return ignoreNotFound(err)
+	}
+	res.Disagrees = append(res.Disagrees, disagree)
+	if overflow := len(res.Disagrees) - maxDisagreesOfSwap; overflow > 0 {
+		res.Disagrees = res.Disagrees[overflow:]
+	}
+	return s.putResult(res)
+}
+
+// FindRouterSwapResult impl
+func (s *EmbeddedStorage) FindRouterSwapResult(fromChainID, txid string, logindex int) (*mongodb.MgoSwapResult, error) {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+
+	return s.getResult(mongodb.GetRouterSwapKey(fromChainID, txid, logindex))
+}
+
+// FindRouterSwapResultAuto impl
+func (s *EmbeddedStorage) FindRouterSwapResultAuto(fromChainID, txid string, logindex int) (*mongodb.MgoSwapResult, error) {
+	if logindex != 0 {
+		return s.FindRouterSwapResult(fromChainID, txid, logindex)
+	}
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+
+	results, err := s.filterResults(func(res *mongodb.MgoSwapResult) bool {
+		return res.FromChainID == fromChainID && containsFold(res.TxID, txid)
+	})
+	if err != nil {
+		return nil, err
+	}
+	if len(results) == 0 {
+		return nil, mongodb.ErrItemNotFound
+	}
+	return results[0], nil
+}
+
+// FindRouterSwapResultBySwapTx impl
+func (s *EmbeddedStorage) FindRouterSwapResultBySwapTx(swapTx string) (*mongodb.MgoSwapResult, error) {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+
+	results, err := s.filterResults(func(res *mongodb.MgoSwapResult) bool {
+		matched, _ := mongodb.MatchSwapTx(res, swapTx)
+		return matched
+	})
+	if err != nil {
+		return nil, err
+	}
+	if len(results) == 0 {
+		return nil, mongodb.ErrItemNotFound
+	}
+	return results[0], nil
+}
+
+// FindRouterSwapResultsWithStatus impl
+func (s *EmbeddedStorage) FindRouterSwapResultsWithStatus(status mongodb.SwapStatus, septime int64) ([]*mongodb.MgoSwapResult, error) {
+	return s.FindRouterSwapResultsWithChainIDAndStatus("", status, septime)
+}
+
+// FindRouterSwapResultsWithChainIDAndStatus impl (empty fromChainID means all chains)
+func (s *EmbeddedStorage) FindRouterSwapResultsWithChainIDAndStatus(fromChainID string, status mongodb.SwapStatus, septime int64) ([]*mongodb.MgoSwapResult, error) {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+
+	results, err := s.filterResults(func(res *mongodb.MgoSwapResult) bool {
+		return res.Status == status && res.Timestamp >= septime &&
+			(fromChainID == "" || res.FromChainID == fromChainID)
+	})
+	if err != nil {
+		return nil, err
+	}
+	sort.SliceStable(results, func(i, j int) bool {
+		return results[i].InitTime < results[j].InitTime
+	})
+	return limitResults(results, 0, maxCountOfResults), nil
+}
+
+// FindRouterSwapResultsSince impl
+func (s *EmbeddedStorage) FindRouterSwapResultsSince(since int64, afterKey string, limit int64) ([]*mongodb.MgoSwapResult, error) {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+
+	results, err := s.filterResults(func(res *mongodb.MgoSwapResult) bool {
+		return res.Timestamp > since || (res.Timestamp == since && res.Key > afterKey)
+	})
+	if err != nil {
+		return nil, err
+	}
+	sort.SliceStable(results, func(i, j int) bool {
+		if results[i].Timestamp != results[j].Timestamp {
+			return results[i].Timestamp < results[j].Timestamp
+		}
+		return results[i].Key < results[j].Key
+	})
+	return limitResults(results, 0, int(limit)), nil
+}
+
+// FindRouterSwapResultsToStable impl
+func (s *EmbeddedStorage) FindRouterSwapResultsToStable(chainID string, septime int64) ([]*mongodb.MgoSwapResult, error) {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+
+	results, err := s.filterResults(func(res *mongodb.MgoSwapResult) bool {
+		return res.InitTime >= septime && res.Status == mongodb.MatchTxNotStable &&
+			res.ToChainID == chainID
+	})
+	if err != nil {
+		return nil, err
+	}
+	sortResultsByNonce(results)
+	return limitResults(results, 0, 100), nil
+}
+
+// FindRouterSwapResultsToReplace impl
+func (s *EmbeddedStorage) FindRouterSwapResultsToReplace(chainID string, septime int64) ([]*mongodb.MgoSwapResult, error) {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+
+	results, err := s.filterResults(func(res *mongodb.MgoSwapResult) bool {
+		return res.InitTime >= septime && res.Status == mongodb.MatchTxNotStable &&
+			res.ToChainID == chainID && res.SwapHeight == 0
+	})
+	if err != nil {
+		return nil, err
+	}
+	sortResultsByNonce(results)
+	return limitResults(results, 0, 20), nil
+}
+
+// FindRouterSwapResults impl
+func (s *EmbeddedStorage) FindRouterSwapResults(fromChainID, address string, offset, limit int, status string) ([]*mongodb.MgoSwapResult, error) {
+	registerStatuses, resultStatuses := mongodb.GetStatusesFromStr(status)
+	filterStatuses, isInResultColl := resultStatuses, true
+	if len(resultStatuses) == 0 && len(registerStatuses) > 0 {
+		filterStatuses = registerStatuses
+		isInResultColl = false
+	}
+	match := func(from, chainID string, swapStatus mongodb.SwapStatus) bool {
+		if address != "" && address != "all" && !containsFold(from, address) {
+			return false
+		}
+		if fromChainID != "" && fromChainID != "all" && chainID != fromChainID {
+			return false
+		}
+		if len(filterStatuses) == 0 {
+			return true
+		}
+		for _, st := range filterStatuses {
+			if st == swapStatus {
+				return true
+			}
+		}
+		return false
+	}
+
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+
+	var results []*mongodb.MgoSwapResult
+	if isInResultColl {
+		var err error
+		results, err = s.filterResults(func(res *mongodb.MgoSwapResult) bool {
+			return match(res.From, res.FromChainID, res.Status)
+		})
+		if err != nil {
+			return nil, err
+		}
+	} else {
+		swaps, err := s.filterSwaps(func(swap *mongodb.MgoSwap) bool {
+			return match(swap.From, swap.FromChainID, swap.Status)
+		})
+		if err != nil {
+			return nil, err
+		}
+		results = mongodb.ConvertToSwapResults(swaps)
+	}
+
+	sort.SliceStable(results, func(i, j int) bool {
+		if limit >= 0 {
+			return results[i].InitTime < results[j].InitTime
+		}
+		return results[i].InitTime > results[j].InitTime
+	})
+	if limit < 0 {
+		limit = -limit
+	}
+	if limit == 0 { // same as mongodb, limit 0 means no limit
+		return limitResults(results, offset, 0), nil
+	}
+	return limitResults(results, offset, limit), nil
+}
+
+// FindRouterSwapResultsToExport impl
+func (s *EmbeddedStorage) FindRouterSwapResultsToExport(filter *mongodb.ExportFilter, limit int64) ([]*mongodb.MgoSwapResult, error) {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+
+	results, err := s.filterResults(filter.Match)
+	if err != nil {
+		return nil, err
+	}
+	mongodb.SortExportedSwapResults(results)
+	return limitResults(results, 0, int(limit)), nil
+}
+
+// GetStatusInfo impl
+func (s *EmbeddedStorage) GetStatusInfo(statuses string) (map[string]interface{}, error) {
+	registerStatuses, resultStatuses := mongodb.GetStatusInfoFilters(statuses)
+	counts := make(map[mongodb.SwapStatus]int)
+	for _, st := range registerStatuses {
+		counts[st] = 0
+	}
+	for _, st := range resultStatuses {
+		counts[st] = 0
+	}
+
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+
+	if len(registerStatuses) > 0 {
+		_, err := s.filterSwaps(func(swap *mongodb.MgoSwap) bool {
+			if containsStatus(registerStatuses, swap.Status) {
+				counts[swap.Status]++
+			}
+			return false
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+	if len(resultStatuses) > 0 {
+		_, err := s.filterResults(func(res *mongodb.MgoSwapResult) bool {
+			if containsStatus(resultStatuses, res.Status) {
+				counts[res.Status]++
+			}
+			return false
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	statusInfo := make(map[string]interface{}, len(counts))
+	for st, count := range counts {
+		if count > 0 { // same as mongodb aggregation, no zero counts
+			statusInfo[fmt.Sprint(uint32(st))] = count
+		}
+	}
+	return statusInfo, nil
+}
+
+func containsStatus(statuses []mongodb.SwapStatus, status mongodb.SwapStatus) bool {
+	for _, st := range statuses {
+		if st == status {
+			return true
+		}
+	}
+	return false
+}
+
+// AllocateRouterSwapNonce impl
+func (s *EmbeddedStorage) AllocateRouterSwapNonce(args *tokens.BuildTxArgs, nonceptr *uint64, isRecycleNonce bool) (swapnonce uint64, err error) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	fromChainID := args.FromChainID.String()
+	txid := args.SwapID
+	logindex := args.LogIndex
+
+	swapnonce = *nonceptr
+	if isRecycleNonce && swapnonce == 0 {
+		return 0, errors.New("swap nonce is alreay recycled")
+	}
+
+	key := mongodb.GetRouterSwapKey(fromChainID, txid, logindex)
+	res, err := s.getResult(key)
+	if err != nil {
+		return 0, err
+	}
+	if err = s.checkRouterSwapResultUpdate(res, swapnonce); err != nil {
+		return 0, err
+	}
+	if err = mongodb.CheckSwapResultStatusTransition(res.Status, mongodb.MatchTxNotStable); err != nil {
+		return 0, err
+	}
+
+	nowTime := time.Now().Unix()
+	res.MPC = args.From
+	res.StatusHistory = appendStatusHistory(res.StatusHistory, res.Status, mongodb.MatchTxNotStable, "swap", "", nowTime)
+	res.Status = mongodb.MatchTxNotStable
+	res.SwapNonce = swapnonce
+	res.Timestamp = nowTime
+	if args.SwapValue != nil {
+		res.SwapValue = args.SwapValue.String()
+	}
+	if err = s.updateResult(res); err != nil {
+		log.Warn("embedded db allocate swap nonce failed", "chainid", fromChainID, "txid", txid, "logindex", logindex, "swapnonce", swapnonce, "err", err)
+		return 0, err
+	}
+	log.Info("embedded db allocate swap nonce success", "chainid", fromChainID, "txid", txid, "logindex", logindex, "swapnonce", swapnonce)
+
+	if errf := s.updateSwapStatus(key, mongodb.TxProcessed, nowTime, "", "swap"); errf != nil {
+		log.Warn("embedded db update swap status to TxProcessed failed", "chainid", fromChainID, "txid", txid, "logindex", logindex, "swapnonce", swapnonce, "err", errf)
+	}
+
+	if isRecycleNonce {
+		*nonceptr = 0
+	} else {
+		*nonceptr++
+	}
+	return swapnonce, nil
+}
+
+// FindNextSwapNonce impl
+func (s *EmbeddedStorage) FindNextSwapNonce(chainID, mpc string) (uint64, error) {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+
+	results, err := s.filterResults(func(res *mongodb.MgoSwapResult) bool {
+		return res.ToChainID == chainID && containsFold(res.MPC, mpc)
+	})
+	if err != nil {
+		return 0, err
+	}
+	if len(results) == 0 {
+		return 0, mongodb.ErrItemNotFound
+	}
+	var maxNonce uint64
+	for _, res := range results {
+		if res.SwapNonce > maxNonce {
+			maxNonce = res.SwapNonce
+		}
+	}
+	return maxNonce + 1, nil
+}
+
+// FindRouterSwapResultsWithNonceRange impl
+func (s *EmbeddedStorage) FindRouterSwapResultsWithNonceRange(chainID, mpc string, fromNonce, toNonce uint64) ([]*mongodb.MgoSwapResult, error) {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+
+	results, err := s.filterResults(func(res *mongodb.MgoSwapResult) bool {
+		return res.ToChainID == chainID && containsFold(res.MPC, mpc) &&
+			res.SwapNonce >= fromNonce && res.SwapNonce < toNonce
+	})
+	if err != nil {
+		return nil, err
+	}
+	sortResultsByNonce(results)
+	return results, nil
+}
+
+// FindRouterSwapResultsWithPassedNonce impl
+func (s *EmbeddedStorage) FindRouterSwapResultsWithPassedNonce(chainID, mpc string, latestNonce uint64) ([]*mongodb.MgoSwapResult, error) {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+
+	results, err := s.filterResults(func(res *mongodb.MgoSwapResult) bool {
+		return res.ToChainID == chainID && containsFold(res.MPC, mpc) &&
+			res.SwapNonce > 0 && res.SwapNonce < latestNonce &&
+			res.Status == mongodb.MatchTxNotStable && res.SwapHeight == 0
+	})
+	if err != nil {
+		return nil, err
+	}
+	sortResultsByNonce(results)
+	return limitResults(results, 0, 100), nil
+}
+
+// AddUsedRValue impl
+func (s *EmbeddedStorage) AddUsedRValue(pubkey, r string) error {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	key := embeddedUsedRPrefix + strings.ToLower(r+":"+pubkey)
+	exist, err := s.db.Has([]byte(key))
+	if err != nil {
+		return err
+	}
+	if exist {
+		log.Warn("embedded db add used r failed", "pubkey", pubkey, "r", r, "err", mongodb.ErrItemIsDup)
+		return mongodb.ErrItemIsDup
+	}
+	return s.put(key, &mongodb.MgoUsedRValue{
+		Key:       key,
+		Timestamp: common.NowMilli(),
+	})
+}
+
+// WatchRouterSwaps impl
+func (s *EmbeddedStorage) WatchRouterSwaps(ctx context.Context, statuses []mongodb.SwapStatus, onEvent func(*mongodb.SwapEvent)) error {
+	s.watch(ctx, s.swapWatchers, statuses, onEvent)
+	return nil
+}
+
+// WatchRouterSwapResults impl
+func (s *EmbeddedStorage) WatchRouterSwapResults(ctx context.Context, statuses []mongodb.SwapStatus, onEvent func(*mongodb.SwapEvent)) error {
+	s.watch(ctx, s.resultWatchers, statuses, onEvent)
+	return nil
+}
+
+func (s *EmbeddedStorage) watch(ctx context.Context, watchers map[*embeddedWatcher]struct{}, statuses []mongodb.SwapStatus, onEvent func(*mongodb.SwapEvent)) {
+	watcher := &embeddedWatcher{statuses: statuses, onEvent: onEvent}
+	s.watchLock.Lock()
+	watchers[watcher] = struct{}{}
+	s.watchLock.Unlock()
+
+	<-ctx.Done()
+
+	s.watchLock.Lock()
+	delete(watchers, watcher)
+	s.watchLock.Unlock()
+}
+
+func (s *EmbeddedStorage) notifyWatchers(watchers map[*embeddedWatcher]struct{}, key, fromChainID, toChainID string, status mongodb.SwapStatus) {
+	s.watchLock.Lock()
+	defer s.watchLock.Unlock()
+
+	for watcher := range watchers {
+		if !containsStatus(watcher.statuses, status) {
+			continue
+		}
+		watcher.onEvent(&mongodb.SwapEvent{
+			Key:         key,
+			FromChainID: fromChainID,
+			ToChainID:   toChainID,
+			Status:      status,
+		})
+	}
+}
+
+// appendStatusHistory record status transition in the status history
+func appendStatusHistory(history []*mongodb.StatusChange, from, to mongodb.SwapStatus, actor, memo string, timestamp int64) []*mongodb.StatusChange {
+	if from == to {
+		return history
+	}
+	history = append(history, &mongodb.StatusChange{
+		From:      from,
+		To:        to,
+		Actor:     actor,
+		Memo:      memo,
+		Timestamp: timestamp,
+	})
+	if len(history) > maxStatusHistory {
+		history = history[len(history)-maxStatusHistory:]
+	}
+	return history
+}
+
+// ignoreNotFound same as mongodb update of not exist item
+func ignoreNotFound(err error) error {
+	if errors.Is(err, mongodb.ErrItemNotFound) {
+		return nil
+	}
+	return err
+}
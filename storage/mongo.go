@@ -0,0 +1,181 @@
+package storage
+
+import (
+	"context"
+
+	"github.com/anyswap/CrossChain-Router/v3/mongodb"
+	"github.com/anyswap/CrossChain-Router/v3/tokens"
+)
+
+var (
+	_ Storage = &MongoStorage{}
+	_ Watcher = &MongoStorage{}
+)
+
+// MongoStorage storage on mongodb (must call `mongodb.MongoServerInit` before using it)
+type MongoStorage struct{}
+
+// NewMongoStorage new mongodb storage
+func NewMongoStorage() *MongoStorage {
+	return &MongoStorage{}
+}
+
+// AddRouterSwap impl
+func (s *MongoStorage) AddRouterSwap(ms *mongodb.MgoSwap) error {
+	return mongodb.AddRouterSwap(ms)
+}
+
+// PassRouterSwapVerify impl
+func (s *MongoStorage) PassRouterSwapVerify(fromChainID, txid string, logindex int, timestamp int64) error {
+	return mongodb.PassRouterSwapVerify(fromChainID, txid, logindex, timestamp)
+}
+
+// UpdateRouterSwapStatus impl
+func (s *MongoStorage) UpdateRouterSwapStatus(fromChainID, txid string, logindex int, status mongodb.SwapStatus, timestamp int64, memo, actor string) error {
+	return mongodb.UpdateRouterSwapStatus(fromChainID, txid, logindex, status, timestamp, memo, actor)
+}
+
+// UpdateRouterSwapInfoAndStatus impl
+func (s *MongoStorage) UpdateRouterSwapInfoAndStatus(fromChainID, txid string, logindex int, swapInfo *mongodb.SwapInfo, status mongodb.SwapStatus, timestamp int64, memo, actor string) error {
+	return mongodb.UpdateRouterSwapInfoAndStatus(fromChainID, txid, logindex, swapInfo, status, timestamp, memo, actor)
+}
+
+// FindRouterSwap impl
+func (s *MongoStorage) FindRouterSwap(fromChainID, txid string, logindex int) (*mongodb.MgoSwap, error) {
+	return mongodb.FindRouterSwap(fromChainID, txid, logindex)
+}
+
+// FindRouterSwapAuto impl
+func (s *MongoStorage) FindRouterSwapAuto(fromChainID, txid string, logindex int) (*mongodb.MgoSwap, error) {
+	return mongodb.FindRouterSwapAuto(fromChainID, txid, logindex)
+}
+
+// FindRouterSwapsWithStatus impl
+func (s *MongoStorage) FindRouterSwapsWithStatus(status mongodb.SwapStatus, septime int64) ([]*mongodb.MgoSwap, error) {
+	return mongodb.FindRouterSwapsWithStatus(status, septime)
+}
+
+// FindRouterSwapsWithChainIDAndStatus impl
+func (s *MongoStorage) FindRouterSwapsWithChainIDAndStatus(fromChainID string, status mongodb.SwapStatus, septime int64) ([]*mongodb.MgoSwap, error) {
+	return mongodb.FindRouterSwapsWithChainIDAndStatus(fromChainID, status, septime)
+}
+
+// AddRouterSwapResult impl
+func (s *MongoStorage) AddRouterSwapResult(mr *mongodb.MgoSwapResult) error {
+	return mongodb.AddRouterSwapResult(mr)
+}
+
+// UpdateRouterSwapResult impl
+func (s *MongoStorage) UpdateRouterSwapResult(fromChainID, txid string, logindex int, items *mongodb.SwapResultUpdateItems) error {
+	return mongodb.UpdateRouterSwapResult(fromChainID, txid, logindex, items)
+}
+
+// UpdateRouterSwapResultStatus impl
+func (s *MongoStorage) UpdateRouterSwapResultStatus(fromChainID, txid string, logindex int, status mongodb.SwapStatus, timestamp int64, memo, actor string) error {
+	return mongodb.UpdateRouterSwapResultStatus(fromChainID, txid, logindex, status, timestamp, memo, actor)
+}
+
+// UpdateRouterOldSwapTxs impl
+func (s *MongoStorage) UpdateRouterOldSwapTxs(fromChainID, txid string, logindex int, swapTx string) error {
+	return mongodb.UpdateRouterOldSwapTxs(fromChainID, txid, logindex, swapTx)
+}
+
+// AddRouterSwapTxAttempt impl
+func (s *MongoStorage) AddRouterSwapTxAttempt(fromChainID, txid string, logindex int, attempt *mongodb.SwapTxAttempt) error {
+	return mongodb.AddRouterSwapTxAttempt(fromChainID, txid, logindex, attempt)
+}
+
+// AddRouterSwapResultDisagree impl
+func (s *MongoStorage) AddRouterSwapResultDisagree(fromChainID, txid string, logindex int, disagree *mongodb.DisagreeReason) error {
+	return mongodb.AddRouterSwapResultDisagree(fromChainID, txid, logindex, disagree)
+}
+
+// FindRouterSwapResult impl
+func (s *MongoStorage) FindRouterSwapResult(fromChainID, txid string, logindex int) (*mongodb.MgoSwapResult, error) {
+	return mongodb.FindRouterSwapResult(fromChainID, txid, logindex)
+}
+
+// FindRouterSwapResultAuto impl
+func (s *MongoStorage) FindRouterSwapResultAuto(fromChainID, txid string, logindex int) (*mongodb.MgoSwapResult, error) {
+	return mongodb.FindRouterSwapResultAuto(fromChainID, txid, logindex)
+}
+
+// FindRouterSwapResultBySwapTx impl
+func (s *MongoStorage) FindRouterSwapResultBySwapTx(swapTx string) (*mongodb.MgoSwapResult, error) {
+	return mongodb.FindRouterSwapResultBySwapTx(swapTx)
+}
+
+// FindRouterSwapResultsWithStatus impl
+func (s *MongoStorage) FindRouterSwapResultsWithStatus(status mongodb.SwapStatus, septime int64) ([]*mongodb.MgoSwapResult, error) {
+	return mongodb.FindRouterSwapResultsWithStatus(status, septime)
+}
+
+// FindRouterSwapResultsWithChainIDAndStatus impl
+func (s *MongoStorage) FindRouterSwapResultsWithChainIDAndStatus(fromChainID string, status mongodb.SwapStatus, septime int64) ([]*mongodb.MgoSwapResult, error) {
+	return mongodb.FindRouterSwapResultsWithChainIDAndStatus(fromChainID, status, septime)
+}
+
+// FindRouterSwapResultsSince impl
+func (s *MongoStorage) FindRouterSwapResultsSince(since int64, afterKey string, limit int64) ([]*mongodb.MgoSwapResult, error) {
+	return mongodb.FindRouterSwapResultsSince(since, afterKey, limit)
+}
+
+// FindRouterSwapResultsToStable impl
+func (s *MongoStorage) FindRouterSwapResultsToStable(chainID string, septime int64) ([]*mongodb.MgoSwapResult, error) {
+	return mongodb.FindRouterSwapResultsToStable(chainID, septime)
+}
+
+// FindRouterSwapResultsToReplace impl
+func (s *MongoStorage) FindRouterSwapResultsToReplace(chainID string, septime int64) ([]*mongodb.MgoSwapResult, error) {
+	return mongodb.FindRouterSwapResultsToReplace(chainID, septime)
+}
+
+// FindRouterSwapResults impl
+func (s *MongoStorage) FindRouterSwapResults(fromChainID, address string, offset, limit int, status string) ([]*mongodb.MgoSwapResult, error) {
+	return mongodb.FindRouterSwapResults(fromChainID, address, offset, limit, status)
+}
+
+// FindRouterSwapResultsToExport impl
+func (s *MongoStorage) FindRouterSwapResultsToExport(filter *mongodb.ExportFilter, limit int64) ([]*mongodb.MgoSwapResult, error) {
+	return mongodb.FindRouterSwapResultsToExport(filter, limit)
+}
+
+// GetStatusInfo impl
+func (s *MongoStorage) GetStatusInfo(statuses string) (map[string]interface{}, error) {
+	return mongodb.GetStatusInfo(statuses)
+}
+
+// AllocateRouterSwapNonce impl
+func (s *MongoStorage) AllocateRouterSwapNonce(args *tokens.BuildTxArgs, nonceptr *uint64, isRecycleNonce bool) (uint64, error) {
+	return mongodb.AllocateRouterSwapNonce(args, nonceptr, isRecycleNonce)
+}
+
+// FindNextSwapNonce impl
+func (s *MongoStorage) FindNextSwapNonce(chainID, mpc string) (uint64, error) {
+	return mongodb.FindNextSwapNonce(chainID, mpc)
+}
+
+// FindRouterSwapResultsWithNonceRange impl
+func (s *MongoStorage) FindRouterSwapResultsWithNonceRange(chainID, mpc string, fromNonce, toNonce uint64) ([]*mongodb.MgoSwapResult, error) {
+	return mongodb.FindRouterSwapResultsWithNonceRange(chainID, mpc, fromNonce, toNonce)
+}
+
+// FindRouterSwapResultsWithPassedNonce impl
+func (s *MongoStorage) FindRouterSwapResultsWithPassedNonce(chainID, mpc string, latestNonce uint64) ([]*mongodb.MgoSwapResult, error) {
+	return mongodb.FindRouterSwapResultsWithPassedNonce(chainID, mpc, latestNonce)
+}
+
+// AddUsedRValue impl
+func (s *MongoStorage) AddUsedRValue(pubkey, r string) error {
+	return mongodb.AddUsedRValue(pubkey, r)
+}
+
+// WatchRouterSwaps impl (change streams require mongodb replica set)
+func (s *MongoStorage) WatchRouterSwaps(ctx context.Context, statuses []mongodb.SwapStatus, onEvent func(*mongodb.SwapEvent)) error {
+	return mongodb.WatchRouterSwaps(ctx, statuses, onEvent)
+}
+
+// WatchRouterSwapResults impl (change streams require mongodb replica set)
+func (s *MongoStorage) WatchRouterSwapResults(ctx context.Context, statuses []mongodb.SwapStatus, onEvent func(*mongodb.SwapEvent)) error {
+	return mongodb.WatchRouterSwapResults(ctx, statuses, onEvent)
+}
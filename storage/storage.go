@@ -0,0 +1,105 @@
+// Package storage defines the storage of swaps, swap results,
+// used r values and swap nonces, with mongodb and embedded backends.
+package storage
+
+import (
+	"context"
+
+	"github.com/anyswap/CrossChain-Router/v3/cmd/utils"
+	"github.com/anyswap/CrossChain-Router/v3/log"
+	"github.com/anyswap/CrossChain-Router/v3/mongodb"
+	"github.com/anyswap/CrossChain-Router/v3/tokens"
+)
+
+// Storage interface
+type Storage interface {
+	// swaps
+	AddRouterSwap(ms *mongodb.MgoSwap) error
+	PassRouterSwapVerify(fromChainID, txid string, logindex int, timestamp int64) error
+	UpdateRouterSwapStatus(fromChainID, txid string, logindex int, status mongodb.SwapStatus, timestamp int64, memo, actor string) error
+	UpdateRouterSwapInfoAndStatus(fromChainID, txid string, logindex int, swapInfo *mongodb.SwapInfo, status mongodb.SwapStatus, timestamp int64, memo, actor string) error
+	FindRouterSwap(fromChainID, txid string, logindex int) (*mongodb.MgoSwap, error)
+	FindRouterSwapAuto(fromChainID, txid string, logindex int) (*mongodb.MgoSwap, error)
+	FindRouterSwapsWithStatus(status mongodb.SwapStatus, septime int64) ([]*mongodb.MgoSwap, error)
+	FindRouterSwapsWithChainIDAndStatus(fromChainID string, status mongodb.SwapStatus, septime int64) ([]*mongodb.MgoSwap, error)
+
+	// swap results
+	AddRouterSwapResult(mr *mongodb.MgoSwapResult) error
+	UpdateRouterSwapResult(fromChainID, txid string, logindex int, items *mongodb.SwapResultUpdateItems) error
+	UpdateRouterSwapResultStatus(fromChainID, txid string, logindex int, status mongodb.SwapStatus, timestamp int64, memo, actor string) error
+	UpdateRouterOldSwapTxs(fromChainID, txid string, logindex int, swapTx string) error
+	AddRouterSwapTxAttempt(fromChainID, txid string, logindex int, attempt *mongodb.SwapTxAttempt) error
+	AddRouterSwapResultDisagree(fromChainID, txid string, logindex int, disagree *mongodb.DisagreeReason) error
+	FindRouterSwapResult(fromChainID, txid string, logindex int) (*mongodb.MgoSwapResult, error)
+	FindRouterSwapResultAuto(fromChainID, txid string, logindex int) (*mongodb.MgoSwapResult, error)
+	FindRouterSwapResultBySwapTx(swapTx string) (*mongodb.MgoSwapResult, error)
+	FindRouterSwapResultsWithStatus(status mongodb.SwapStatus, septime int64) ([]*mongodb.MgoSwapResult, error)
+	FindRouterSwapResultsWithChainIDAndStatus(fromChainID string, status mongodb.SwapStatus, septime int64) ([]*mongodb.MgoSwapResult, error)
+	FindRouterSwapResultsSince(since int64, afterKey string, limit int64) ([]*mongodb.MgoSwapResult, error)
+	FindRouterSwapResultsToStable(chainID string, septime int64) ([]*mongodb.MgoSwapResult, error)
+	FindRouterSwapResultsToReplace(chainID string, septime int64) ([]*mongodb.MgoSwapResult, error)
+	FindRouterSwapResults(fromChainID, address string, offset, limit int, status string) ([]*mongodb.MgoSwapResult, error)
+	FindRouterSwapResultsToExport(filter *mongodb.ExportFilter, limit int64) ([]*mongodb.MgoSwapResult, error)
+	GetStatusInfo(statuses string) (map[string]interface{}, error)
+
+	// swap nonces
+	AllocateRouterSwapNonce(args *tokens.BuildTxArgs, nonceptr *uint64, isRecycleNonce bool) (swapnonce uint64, err error)
+	FindNextSwapNonce(chainID, mpc string) (uint64, error)
+	FindRouterSwapResultsWithNonceRange(chainID, mpc string, fromNonce, toNonce uint64) ([]*mongodb.MgoSwapResult, error)
+	FindRouterSwapResultsWithPassedNonce(chainID, mpc string, latestNonce uint64) ([]*mongodb.MgoSwapResult, error)
+
+	// used r values
+	AddUsedRValue(pubkey, r string) error
+}
+
+// Watcher storage which can notify changes of swaps and swap results,
+// so workers can process them immediately instead of waiting for polling.
+// `onEvent` must not block, and watching blocks until `ctx` is done or error occurs.
+type Watcher interface {
+	WatchRouterSwaps(ctx context.Context, statuses []mongodb.SwapStatus, onEvent func(*mongodb.SwapEvent)) error
+	WatchRouterSwapResults(ctx context.Context, statuses []mongodb.SwapStatus, onEvent func(*mongodb.SwapEvent)) error
+}
+
+var store Storage
+
+// SetStorage set storage in use
+func SetStorage(s Storage) {
+	store = s
+}
+
+// HasStorage has storage in use
+func HasStorage() bool {
+	return store != nil
+}
+
+// Get get storage in use
+func Get() Storage {
+	return store
+}
+
+// InitMongoStorage init storage on mongodb
+func InitMongoStorage(appName string, dbURLs []string, dbName, user, pass string) {
+	mongodb.MongoServerInit(appName, dbURLs, dbName, user, pass)
+	SetStorage(NewMongoStorage())
+}
+
+// InitEmbeddedStorage init embedded storage in directory `path`
+func InitEmbeddedStorage(path string) {
+	s, err := NewEmbeddedStorage(path)
+	if err != nil {
+		log.Fatal("[storage] open embedded database failed", "path", path, "err", err)
+	}
+	log.Info("[storage] open embedded database success", "path", path)
+	SetStorage(s)
+
+	utils.TopWaitGroup.Add(1)
+	go utils.WaitAndCleanup(func() {
+		defer utils.TopWaitGroup.Done()
+		mongodb.MgoWaitGroup.Wait()
+		if err := s.Close(); err != nil {
+			log.Error("[storage] close embedded database failed", "err", err)
+		} else {
+			log.Info("[storage] close embedded database success")
+		}
+	})
+}
@@ -0,0 +1,44 @@
+package storage
+
+import (
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/anyswap/CrossChain-Router/v3/mongodb"
+)
+
+func TestEmbeddedStorage(t *testing.T) {
+	s, err := NewEmbeddedStorage("")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer s.Close()
+	testConformance(t, s)
+}
+
+func TestEmbeddedStorageOnDisk(t *testing.T) {
+	s, err := NewEmbeddedStorage(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer s.Close()
+	testConformance(t, s)
+}
+
+// set env ROUTER_TEST_MONGODB_URLS (comma separated), ROUTER_TEST_MONGODB_NAME,
+// ROUTER_TEST_MONGODB_USER and ROUTER_TEST_MONGODB_PASS to run it
+func TestMongoStorage(t *testing.T) {
+	urls := os.Getenv("ROUTER_TEST_MONGODB_URLS")
+	if urls == "" {
+		t.Skip("ROUTER_TEST_MONGODB_URLS is not set")
+	}
+	mongodb.MongoServerInit(
+		"storage-test",
+		strings.Split(urls, ","),
+		os.Getenv("ROUTER_TEST_MONGODB_NAME"),
+		os.Getenv("ROUTER_TEST_MONGODB_USER"),
+		os.Getenv("ROUTER_TEST_MONGODB_PASS"),
+	)
+	testConformance(t, NewMongoStorage())
+}
@@ -7,7 +7,7 @@ import (
 	"time"
 
 	"github.com/anyswap/CrossChain-Router/v3/log"
-	"github.com/anyswap/CrossChain-Router/v3/mongodb"
+	"github.com/anyswap/CrossChain-Router/v3/storage"
 	"github.com/anyswap/CrossChain-Router/v3/tokens"
 )
 
@@ -94,6 +94,18 @@ func (b *NonceSetterBase) InitSwapNonce(br tokens.NonceSetter, address string, n
 	log.Info("init swap nonce success", "chainID", b.ChainConfig.ChainID, "account", account, "dbNexNonce", dbNexNonce, "nonce", nonce)
 }
 
+// GetSwapNonceAccounts get accounts with swap nonce
+func (b *NonceSetterBase) GetSwapNonceAccounts() []string {
+	swapNonceLock.RLock()
+	defer swapNonceLock.RUnlock()
+
+	accounts := make([]string, 0, len(b.swapNonce))
+	for account := range b.swapNonce {
+		accounts = append(accounts, account)
+	}
+	return accounts
+}
+
 // SetNonce set account nonce (eth like chain)
 func (b *NonceSetterBase) SetNonce(address string, value uint64) {
 	swapNonceLock.Lock()
@@ -125,7 +137,7 @@ func (b *NonceSetterBase) AllocateNonce(args *tokens.BuildTxArgs) (nonce uint64,
 		allocNonce = &initNonce
 		b.swapNonce[account] = allocNonce
 	}
-	return mongodb.AllocateRouterSwapNonce(args, allocNonce, false)
+	return storage.Get().AllocateRouterSwapNonce(args, allocNonce, false)
 }
 
 // TryAllocateRecycleNonce try allocate recycle swap nonce
@@ -138,7 +150,7 @@ func (b *NonceSetterBase) TryAllocateRecycleNonce(args *tokens.BuildTxArgs, life
 	if !exist || time.Now().Unix()-rec.timestamp < lifetime {
 		return 0, errRecycleNotAcked
 	}
-	return mongodb.AllocateRouterSwapNonce(args, &rec.nonce, true)
+	return storage.Get().AllocateRouterSwapNonce(args, &rec.nonce, true)
 }
 
 // RecycleSwapNonce recycle swap nonce
@@ -158,3 +170,14 @@ func (b *NonceSetterBase) RecycleSwapNonce(sender string, nonce uint64) {
 		rec.timestamp = time.Now().Unix()
 	}
 }
+
+// RemoveRecycleSwapNonce remove recycle swap nonce (eg. the nonce is used by other tx)
+func (b *NonceSetterBase) RemoveRecycleSwapNonce(sender string, nonce uint64) {
+	recycleSwapNonceLock.Lock()
+	defer recycleSwapNonceLock.Unlock()
+
+	account := strings.ToLower(sender)
+	if rec, exist := b.recycleNonce[account]; exist && rec.nonce == nonce {
+		rec.nonce = 0
+	}
+}
@@ -42,6 +42,8 @@ var (
 	ErrNoEnoughReserveBudget = errors.New("no enough reserve budget")
 	ErrTxWithNoPayment       = errors.New("tx with no payment")
 	ErrTxIsNotValidated      = errors.New("tx is not validated")
+	ErrSimulateTxReverted    = errors.New("simulate tx reverted")
+	ErrReplaceFeeTooHigh     = errors.New("replace fee exceeded maximum limit")
 
 	// errors should register in router swap
 	ErrTxWithWrongValue  = errors.New("tx with wrong value")
@@ -9,10 +9,10 @@ import (
 
 	"github.com/anyswap/CrossChain-Router/v3/common"
 	"github.com/anyswap/CrossChain-Router/v3/log"
-	"github.com/anyswap/CrossChain-Router/v3/mongodb"
 	"github.com/anyswap/CrossChain-Router/v3/params"
 	"github.com/anyswap/CrossChain-Router/v3/router"
 	"github.com/anyswap/CrossChain-Router/v3/rpc/client"
+	"github.com/anyswap/CrossChain-Router/v3/storage"
 	"github.com/anyswap/CrossChain-Router/v3/tokens"
 	"github.com/anyswap/CrossChain-Router/v3/tokens/base"
 	"github.com/anyswap/CrossChain-Router/v3/types"
@@ -31,13 +31,18 @@ type Bridge struct {
 	*base.NonceSetterBase
 	Signer        types.Signer
 	SignerChainID *big.Int
+
+	gasPriceStrategy *gasPriceStrategyHolder
+	signer           *signerHolder
 }
 
 // NewCrossChainBridge new bridge
 func NewCrossChainBridge() *Bridge {
 	return &Bridge{
-		CustomConfig:    NewCustomConfig(),
-		NonceSetterBase: base.NewNonceSetterBase(),
+		CustomConfig:     NewCustomConfig(),
+		NonceSetterBase:  base.NewNonceSetterBase(),
+		gasPriceStrategy: &gasPriceStrategyHolder{},
+		signer:           &signerHolder{},
 	}
 }
 
@@ -105,9 +110,12 @@ func (b *Bridge) initSigner(chainID *big.Int) (err error) {
 		return err
 	}
 	b.SignerChainID = signerChainID
-	if params.IsDynamicFeeTxEnabled(signerChainID.String()) {
+	switch {
+	case params.IsDynamicFeeTxEnabled(signerChainID.String()):
 		b.Signer = types.MakeSigner("London", signerChainID)
-	} else {
+	case params.IsAccessListTxEnabled(signerChainID.String()):
+		b.Signer = types.MakeSigner("Berlin", signerChainID)
+	default:
 		b.Signer = types.MakeSigner("EIP155", signerChainID)
 	}
 	return nil
@@ -165,10 +173,10 @@ func (b *Bridge) InitRouterInfo(routerContract string) (err error) {
 		"routerContract", routerContract, "routerMPC", routerMPC,
 		"routerFactory", routerFactory, "routerWNative", routerWNative)
 
-	if mongodb.HasClient() {
+	if storage.HasStorage() {
 		var nextSwapNonce uint64
 		for i := 0; i < 3; i++ {
-			nextSwapNonce, err = mongodb.FindNextSwapNonce(chainID, strings.ToLower(routerMPC))
+			nextSwapNonce, err = storage.Get().FindNextSwapNonce(chainID, strings.ToLower(routerMPC))
 			if err == nil {
 				break
 			}
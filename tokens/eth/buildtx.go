@@ -25,12 +25,15 @@ func (b *Bridge) BuildRawTransaction(args *tokens.BuildTxArgs) (rawTx interface{
 	if !params.IsTestMode && args.ToChainID.String() != b.ChainConfig.ChainID {
 		return nil, tokens.ErrToChainIDMismatch
 	}
-	if args.Input != nil {
-		return nil, fmt.Errorf("forbid build raw swap tx with input data")
-	}
 	if args.From == "" {
 		return nil, fmt.Errorf("forbid empty sender")
 	}
+	if args.SwapType == tokens.NonceGapFillType {
+		return b.buildNonceGapFillTx(args)
+	}
+	if args.Input != nil {
+		return nil, fmt.Errorf("forbid build raw swap tx with input data")
+	}
 	routerMPC, err := router.GetRouterMPC(args.GetTokenID(), b.ChainConfig.ChainID)
 	if err != nil {
 		return nil, err
@@ -55,6 +58,13 @@ func (b *Bridge) BuildRawTransaction(args *tokens.BuildTxArgs) (rawTx interface{
 		return nil, err
 	}
 
+	if b.shouldSimulateSwapTx(args) {
+		err = b.simulateSwapTx(args)
+		if err != nil {
+			return nil, err
+		}
+	}
+
 	err = b.setDefaults(args)
 	if err != nil {
 		return nil, err
@@ -75,7 +85,13 @@ func (b *Bridge) buildTx(args *tokens.BuildTxArgs) (rawTx interface{}, err error
 		gasFeeCap = extra.GasFeeCap
 
 		isDynamicFeeTx = params.IsDynamicFeeTxEnabled(b.ChainConfig.ChainID)
+		isAccessListTx = params.IsAccessListTxEnabled(b.ChainConfig.ChainID)
+
+		accessList types.AccessList
 	)
+	if extra.AccessList != nil {
+		accessList = *extra.AccessList
+	}
 
 	minReserveFee := b.getMinReserveFee()
 	// if min reserve fee is zero, then do not check balance
@@ -110,9 +126,12 @@ func (b *Bridge) buildTx(args *tokens.BuildTxArgs) (rawTx interface{}, err error
 	}
 	nonce := *extra.Nonce
 
-	if isDynamicFeeTx {
-		rawTx = types.NewDynamicFeeTx(b.SignerChainID, nonce, &to, value, gasLimit, gasTipCap, gasFeeCap, input, nil)
-	} else {
+	switch {
+	case isDynamicFeeTx:
+		rawTx = types.NewDynamicFeeTx(b.SignerChainID, nonce, &to, value, gasLimit, gasTipCap, gasFeeCap, input, accessList)
+	case isAccessListTx:
+		rawTx = types.NewAccessListTx(b.SignerChainID, nonce, &to, value, gasLimit, gasPrice, input, accessList)
+	default:
 		rawTx = types.NewTransaction(nonce, to, value, gasLimit, gasPrice, input)
 	}
 
@@ -127,6 +146,9 @@ func (b *Bridge) buildTx(args *tokens.BuildTxArgs) (rawTx interface{}, err error
 	} else {
 		ctx = append(ctx, "gasPrice", gasPrice)
 	}
+	if isAccessListTx {
+		ctx = append(ctx, "accessList", len(accessList))
+	}
 	switch {
 	case args.ERC20SwapInfo != nil:
 		ctx = append(ctx,
@@ -181,6 +203,12 @@ func (b *Bridge) setDefaults(args *tokens.BuildTxArgs) (err error) {
 		extra.GasTipCap = nil
 		extra.GasFeeCap = nil
 	}
+	var accessListGasUsed uint64
+	if !params.IsAccessListTxEnabled(b.ChainConfig.ChainID) {
+		extra.AccessList = nil
+	} else if extra.AccessList == nil {
+		extra.AccessList, accessListGasUsed = b.createAccessList(args)
+	}
 	if extra.Gas == nil {
 		esGasLimit, errf := b.EstimateGas(args.From, args.To, args.Value, *args.Input)
 		if errf != nil {
@@ -189,17 +217,75 @@ func (b *Bridge) setDefaults(args *tokens.BuildTxArgs) (err error) {
 				"value", args.Value, "data", *args.Input, "err", errf)
 			return tokens.ErrEstimateGasFailed
 		}
-		esGasLimit += esGasLimit * 30 / 100
-		defGasLimit := b.getDefaultGasLimit()
-		if esGasLimit < defGasLimit {
-			esGasLimit = defGasLimit
+		if esGasLimit < accessListGasUsed {
+			esGasLimit = accessListGasUsed
+		}
+		gasLimit, errf := b.adjustSwapGasLimit(args, esGasLimit)
+		if errf != nil {
+			return errf
 		}
 		extra.Gas = new(uint64)
-		*extra.Gas = esGasLimit
+		*extra.Gas = gasLimit
 	}
 	return nil
 }
 
+// swap types of gas limit config, the more specific one first
+func getGasLimitSwapTypes(args *tokens.BuildTxArgs) []string {
+	swapType := args.SwapType.String()
+	if args.NFTSwapInfo != nil && args.NFTSwapInfo.Batch {
+		return []string{"nftbatchswap", swapType}
+	}
+	return []string{swapType}
+}
+
+// adjustSwapGasLimit add safety margin to estimated gas and
+// limit it in range of the configed floor and ceiling
+func (b *Bridge) adjustSwapGasLimit(args *tokens.BuildTxArgs, esGasLimit uint64) (uint64, error) {
+	plusPercent := uint64(30)
+	minGasLimit := b.getDefaultGasLimit()
+	maxGasLimit := uint64(0)
+	gasLimitCfg := params.GetGasLimitConfig(b.ChainConfig.ChainID, getGasLimitSwapTypes(args)...)
+	if gasLimitCfg != nil {
+		if gasLimitCfg.PlusGasLimitPercent > 0 {
+			plusPercent = gasLimitCfg.PlusGasLimitPercent
+		}
+		if gasLimitCfg.MinGasLimit > 0 {
+			minGasLimit = gasLimitCfg.MinGasLimit
+		}
+		maxGasLimit = gasLimitCfg.MaxGasLimit
+	}
+	if maxGasLimit > 0 && esGasLimit > maxGasLimit {
+		log.Error(fmt.Sprintf("build %s tx estimated gas exceeded maximum limit", args.SwapType.String()),
+			"swapID", args.SwapID, "estimated", esGasLimit, "maxGasLimit", maxGasLimit)
+		return 0, fmt.Errorf("%w: estimated gas %v exceeded maximum limit %v", tokens.ErrEstimateGasFailed, esGasLimit, maxGasLimit)
+	}
+	gasLimit := esGasLimit + esGasLimit*plusPercent/100
+	if gasLimit < minGasLimit {
+		gasLimit = minGasLimit
+	}
+	if maxGasLimit > 0 && gasLimit > maxGasLimit {
+		gasLimit = maxGasLimit
+	}
+	log.Info(fmt.Sprintf("build %s tx estimate gas limit", args.SwapType.String()),
+		"swapID", args.SwapID, "estimated", esGasLimit, "gasLimit", gasLimit,
+		"plusPercent", plusPercent, "minGasLimit", minGasLimit, "maxGasLimit", maxGasLimit)
+	return gasLimit, nil
+}
+
+// createAccessList generate access list by the gateway (EIP-2930).
+// returns an empty list if failed, so the tx can still be built
+// and the verifiers can rebuild the same tx from the extra args.
+func (b *Bridge) createAccessList(args *tokens.BuildTxArgs) (*types.AccessList, uint64) {
+	result, err := b.CreateAccessList(args.From, args.To, args.Value, *args.Input)
+	if err != nil || result.AccessList == nil {
+		log.Warn(fmt.Sprintf("build %s tx create access list failed", args.SwapType.String()),
+			"swapID", args.SwapID, "from", args.From, "to", args.To, "err", err)
+		return &types.AccessList{}, 0
+	}
+	return result.AccessList, uint64(result.GasUsed)
+}
+
 func (b *Bridge) getDefaultGasLimit() uint64 {
 	gasLimit := uint64(90000)
 	serverCfg := params.GetRouterServerConfig()
@@ -219,16 +305,21 @@ func (b *Bridge) getGasPrice(args *tokens.BuildTxArgs) (price *big.Int, err erro
 			return price, nil
 		}
 	} else {
+		strategy := b.GetGasPriceStrategy()
 		for i := 0; i < retryRPCCount; i++ {
-			price, err = b.SuggestPrice()
+			price, err = strategy.SuggestGasPrice(args)
 			if err == nil {
 				break
 			}
 			time.Sleep(retryRPCInterval)
 		}
 		if err != nil {
+			log.Warn("suggest gas price failed", "chainID", b.ChainConfig.ChainID,
+				"strategy", strategy.Name(), "swapID", args.SwapID, "err", err)
 			return nil, err
 		}
+		log.Info("suggest gas price success", "chainID", b.ChainConfig.ChainID,
+			"strategy", strategy.Name(), "swapID", args.SwapID, "gasPrice", price)
 	}
 
 	if params.IsTestMode {
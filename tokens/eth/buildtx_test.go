@@ -0,0 +1,58 @@
+package eth
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/anyswap/CrossChain-Router/v3/params"
+	"github.com/anyswap/CrossChain-Router/v3/tokens"
+)
+
+func TestAdjustSwapGasLimit(t *testing.T) {
+	br.ChainConfig = &tokens.ChainConfig{ChainID: "4"}
+	params.GetRouterConfig().Server = &params.RouterServerConfig{
+		DefaultGasLimit: map[string]uint64{"4": 100000},
+		GasLimit: map[string]map[string]*params.GasLimitConfig{
+			"4": {
+				"erc20swap":    {MaxGasLimit: 1000000},
+				"anycallswap":  {PlusGasLimitPercent: 50, MinGasLimit: 150000, MaxGasLimit: 600000},
+				"nftbatchswap": {PlusGasLimitPercent: 20},
+			},
+		},
+	}
+	defer func() { params.GetRouterConfig().Server = nil }()
+
+	newArgs := func(swapType tokens.SwapType, batch bool) *tokens.BuildTxArgs {
+		args := &tokens.BuildTxArgs{SwapArgs: tokens.SwapArgs{SwapType: swapType}}
+		if swapType == tokens.NFTSwapType {
+			args.NFTSwapInfo = &tokens.NFTSwapInfo{Batch: batch}
+		}
+		return args
+	}
+
+	tests := []struct {
+		args      *tokens.BuildTxArgs
+		estimated uint64
+		want      uint64
+		wantErr   error
+	}{
+		{newArgs(tokens.ERC20SwapType, false), 50000, 100000, nil},    // default floor
+		{newArgs(tokens.ERC20SwapType, false), 100000, 130000, nil},   // default 30% margin if not configed
+		{newArgs(tokens.AnyCallSwapType, false), 80000, 150000, nil},  // configed floor
+		{newArgs(tokens.AnyCallSwapType, false), 200000, 300000, nil}, // configed margin
+		{newArgs(tokens.AnyCallSwapType, false), 500000, 600000, nil}, // configed ceiling
+		{newArgs(tokens.AnyCallSwapType, false), 700000, 0, tokens.ErrEstimateGasFailed},
+		{newArgs(tokens.NFTSwapType, true), 500000, 600000, nil},  // batch nft config
+		{newArgs(tokens.NFTSwapType, false), 500000, 650000, nil}, // fallback to default
+	}
+	for i, test := range tests {
+		have, err := br.adjustSwapGasLimit(test.args, test.estimated)
+		if !errors.Is(err, test.wantErr) {
+			t.Errorf("test %v: error mismatch, have %v want %v", i, err, test.wantErr)
+			continue
+		}
+		if have != test.want {
+			t.Errorf("test %v: gas limit mismatch, have %v want %v", i, have, test.want)
+		}
+	}
+}
@@ -287,8 +287,11 @@ func (b *Bridge) getMaxPoolNonce(account common.Address, height string, urls []s
 
 // SuggestPrice call eth_gasPrice
 func (b *Bridge) SuggestPrice() (*big.Int, error) {
+	return b.suggestPriceByMethod(params.GetCalcGasPriceMethod(b.ChainConfig.ChainID))
+}
+
+func (b *Bridge) suggestPriceByMethod(calcMethod string) (*big.Int, error) {
 	gateway := b.GatewayConfig
-	calcMethod := params.GetCalcGasPriceMethod(b.ChainConfig.ChainID)
 	switch calcMethod {
 	case "first":
 		return b.getGasPriceFromURL(gateway.APIAddress[0])
@@ -658,3 +661,29 @@ func (b *Bridge) EstimateGas(from, to string, value *big.Int, data []byte) (uint
 	log.Warn("[rpc] estimate gas failed", "from", from, "to", to, "value", value, "data", hexutil.Bytes(data), "err", err)
 	return 0, wrapRPCQueryError(err, "eth_estimateGas")
 }
+
+// CreateAccessList call eth_createAccessList
+func (b *Bridge) CreateAccessList(from, to string, value *big.Int, data []byte) (*types.AccessListResult, error) {
+	reqArgs := map[string]interface{}{
+		"from":  from,
+		"to":    to,
+		"value": (*hexutil.Big)(value),
+		"data":  hexutil.Bytes(data),
+	}
+	gateway := b.GatewayConfig
+	var result types.AccessListResult
+	var err error
+	for _, apiAddress := range gateway.APIAddress {
+		url := apiAddress
+		err = client.RPCPostWithTimeout(b.RPCClientTimeout, &result, url, "eth_createAccessList", reqArgs, "pending")
+		if err == nil {
+			if result.Error != "" {
+				err = fmt.Errorf("create access list error: %v", result.Error)
+				continue
+			}
+			return &result, nil
+		}
+	}
+	log.Warn("[rpc] create access list failed", "from", from, "to", to, "value", value, "data", hexutil.Bytes(data), "err", err)
+	return nil, wrapRPCQueryError(err, "eth_createAccessList")
+}
@@ -0,0 +1,249 @@
+package eth
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+	"sort"
+	"sync"
+
+	"github.com/anyswap/CrossChain-Router/v3/common"
+	"github.com/anyswap/CrossChain-Router/v3/log"
+	"github.com/anyswap/CrossChain-Router/v3/params"
+	"github.com/anyswap/CrossChain-Router/v3/tokens"
+)
+
+const (
+	defaultFeeHistoryBlockCount = 20
+	defaultRewardPercentile     = 50
+	defaultEWMAWeightPercent    = 30
+)
+
+var errNoFeeHistoryRewards = errors.New("no rewards in fee history")
+
+// GasPriceStrategy suggest gas price of swap tx
+type GasPriceStrategy interface {
+	Name() string
+	SuggestGasPrice(args *tokens.BuildTxArgs) (*big.Int, error)
+}
+
+type gasPriceStrategyHolder struct {
+	lock     sync.Mutex
+	config   *params.GasPriceStrategyConfig
+	strategy GasPriceStrategy
+}
+
+// GetGasPriceStrategy get gas price strategy of this chain
+// (rebuild it if config is changed)
+func (b *Bridge) GetGasPriceStrategy() GasPriceStrategy {
+	config := params.GetGasPriceStrategyConfig(b.ChainConfig.ChainID)
+	if config == nil {
+		return newRPCGasPriceStrategy(b, params.GetCalcGasPriceMethod(b.ChainConfig.ChainID))
+	}
+	holder := b.gasPriceStrategy
+	if holder == nil {
+		return newGasPriceStrategy(b, config)
+	}
+	holder.lock.Lock()
+	defer holder.lock.Unlock()
+	if holder.strategy == nil || holder.config != config {
+		holder.strategy = newGasPriceStrategy(b, config)
+		holder.config = config
+		log.Info("init gas price strategy", "chainID", b.ChainConfig.ChainID, "strategy", holder.strategy.Name())
+	}
+	return holder.strategy
+}
+
+func newGasPriceStrategy(b *Bridge, config *params.GasPriceStrategyConfig) GasPriceStrategy {
+	switch config.Strategy {
+	case params.GasPriceStrategyUrgency:
+		return &urgencyGasPriceStrategy{
+			bridge: b,
+			base:   newBaseGasPriceStrategy(b, config.BaseStrategy, config),
+			tiers:  config.UrgencyTiers,
+		}
+	case params.GasPriceStrategyEWMA:
+		weight := config.EWMAWeightPercent
+		if weight == 0 {
+			weight = defaultEWMAWeightPercent
+		}
+		return &ewmaGasPriceStrategy{
+			base:   newBaseGasPriceStrategy(b, config.BaseStrategy, config),
+			weight: weight,
+		}
+	default:
+		return newBaseGasPriceStrategy(b, config.Strategy, config)
+	}
+}
+
+func newBaseGasPriceStrategy(b *Bridge, strategy string, config *params.GasPriceStrategyConfig) GasPriceStrategy {
+	if strategy != params.GasPriceStrategyPercentile {
+		return newRPCGasPriceStrategy(b, strategy)
+	}
+	blockCount := config.BlockCount
+	if blockCount == 0 {
+		blockCount = defaultFeeHistoryBlockCount
+	}
+	percentile := config.Percentile
+	if percentile == 0 {
+		percentile = defaultRewardPercentile
+	}
+	return &percentileGasPriceStrategy{
+		bridge:     b,
+		blockCount: blockCount,
+		percentile: percentile,
+	}
+}
+
+// rpcGasPriceStrategy first, max, median of 'eth_gasPrice' of gateways
+type rpcGasPriceStrategy struct {
+	bridge *Bridge
+	method string
+}
+
+func newRPCGasPriceStrategy(b *Bridge, method string) *rpcGasPriceStrategy {
+	if method == "" {
+		method = params.GasPriceStrategyMedian
+	}
+	return &rpcGasPriceStrategy{bridge: b, method: method}
+}
+
+func (s *rpcGasPriceStrategy) Name() string {
+	return s.method
+}
+
+func (s *rpcGasPriceStrategy) SuggestGasPrice(_ *tokens.BuildTxArgs) (*big.Int, error) {
+	return s.bridge.suggestPriceByMethod(s.method)
+}
+
+// percentileGasPriceStrategy base fee + median of percentile rewards in recent blocks
+type percentileGasPriceStrategy struct {
+	bridge     *Bridge
+	blockCount int
+	percentile float64
+}
+
+func (s *percentileGasPriceStrategy) Name() string {
+	return fmt.Sprintf("%v(%v,%v)", params.GasPriceStrategyPercentile, s.blockCount, s.percentile)
+}
+
+func (s *percentileGasPriceStrategy) SuggestGasPrice(_ *tokens.BuildTxArgs) (*big.Int, error) {
+	feeHistory, err := s.bridge.FeeHistory(s.blockCount, []float64{s.percentile})
+	if err != nil {
+		return nil, err
+	}
+	rewards := make([]*big.Int, 0, len(feeHistory.Reward))
+	for _, reward := range feeHistory.Reward {
+		// ignore empty blocks
+		if len(reward) > 0 && reward[0] != nil && reward[0].ToInt().Sign() > 0 {
+			rewards = append(rewards, reward[0].ToInt())
+		}
+	}
+	if len(rewards) == 0 {
+		return nil, errNoFeeHistoryRewards
+	}
+	price := getMedianBigInt(rewards)
+	// the last one is the base fee of the next block
+	if length := len(feeHistory.BaseFee); length > 0 && feeHistory.BaseFee[length-1] != nil {
+		price.Add(price, feeHistory.BaseFee[length-1].ToInt())
+	}
+	return price, nil
+}
+
+// urgencyGasPriceStrategy increase base gas price by swap age and value
+type urgencyGasPriceStrategy struct {
+	bridge *Bridge
+	base   GasPriceStrategy
+	tiers  []*params.UrgencyTierConfig
+}
+
+func (s *urgencyGasPriceStrategy) Name() string {
+	return fmt.Sprintf("%v(%v)", params.GasPriceStrategyUrgency, s.base.Name())
+}
+
+func (s *urgencyGasPriceStrategy) SuggestGasPrice(args *tokens.BuildTxArgs) (*big.Int, error) {
+	price, err := s.base.SuggestGasPrice(args)
+	if err != nil {
+		return nil, err
+	}
+	plusPercent := s.getPlusPercent(args)
+	if plusPercent > 0 {
+		price.Mul(price, new(big.Int).SetUint64(100+plusPercent))
+		price.Div(price, big.NewInt(100))
+	}
+	return price, nil
+}
+
+func (s *urgencyGasPriceStrategy) getPlusPercent(args *tokens.BuildTxArgs) (plusPercent uint64) {
+	var age int64
+	if args.InitTime > 0 {
+		age = (common.NowMilli() - args.InitTime) / 1000
+	}
+	isBigValue := s.isBigValue(args)
+	for _, tier := range s.tiers {
+		if age < tier.MinAge || (tier.BigValue && !isBigValue) {
+			continue
+		}
+		if tier.PlusPercent > plusPercent {
+			plusPercent = tier.PlusPercent
+		}
+	}
+	return plusPercent
+}
+
+func (s *urgencyGasPriceStrategy) isBigValue(args *tokens.BuildTxArgs) bool {
+	if args.ERC20SwapInfo == nil || args.OriginValue == nil {
+		return false
+	}
+	swapCfg := tokens.GetSwapConfig(args.ERC20SwapInfo.TokenID, args.FromChainID.String(), args.ToChainID.String())
+	if swapCfg == nil || swapCfg.BigValueThreshold == nil {
+		return false
+	}
+	return args.OriginValue.Cmp(swapCfg.BigValueThreshold) >= 0
+}
+
+// ewmaGasPriceStrategy exponentially weighted moving average of base gas price
+type ewmaGasPriceStrategy struct {
+	base   GasPriceStrategy
+	weight uint64 // percent of the newest sample
+
+	lock    sync.Mutex
+	average *big.Int
+}
+
+func (s *ewmaGasPriceStrategy) Name() string {
+	return fmt.Sprintf("%v(%v,%v)", params.GasPriceStrategyEWMA, s.base.Name(), s.weight)
+}
+
+func (s *ewmaGasPriceStrategy) SuggestGasPrice(args *tokens.BuildTxArgs) (*big.Int, error) {
+	sample, err := s.base.SuggestGasPrice(args)
+	if err != nil {
+		return nil, err
+	}
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	if s.average == nil {
+		s.average = new(big.Int).Set(sample)
+	} else {
+		// average = (sample * weight + average * (100 - weight)) / 100
+		newAverage := new(big.Int).Mul(sample, new(big.Int).SetUint64(s.weight))
+		newAverage.Add(newAverage, new(big.Int).Mul(s.average, new(big.Int).SetUint64(100-s.weight)))
+		s.average = newAverage.Div(newAverage, big.NewInt(100))
+	}
+	return new(big.Int).Set(s.average), nil
+}
+
+func getMedianBigInt(values []*big.Int) *big.Int {
+	sorted := make([]*big.Int, len(values))
+	copy(sorted, values)
+	sort.Slice(sorted, func(i, j int) bool {
+		return sorted[i].Cmp(sorted[j]) < 0
+	})
+	count := len(sorted)
+	mdInd := (count - 1) / 2
+	if count%2 != 0 {
+		return new(big.Int).Set(sorted[mdInd])
+	}
+	median := new(big.Int).Add(sorted[mdInd], sorted[mdInd+1])
+	return median.Div(median, big.NewInt(2))
+}
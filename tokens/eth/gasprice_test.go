@@ -0,0 +1,70 @@
+package eth
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/anyswap/CrossChain-Router/v3/common"
+	"github.com/anyswap/CrossChain-Router/v3/params"
+	"github.com/anyswap/CrossChain-Router/v3/tokens"
+)
+
+type fixedGasPriceStrategy struct {
+	prices []int64
+	index  int
+}
+
+func (s *fixedGasPriceStrategy) Name() string {
+	return "fixed"
+}
+
+func (s *fixedGasPriceStrategy) SuggestGasPrice(_ *tokens.BuildTxArgs) (*big.Int, error) {
+	price := big.NewInt(s.prices[s.index%len(s.prices)])
+	s.index++
+	return price, nil
+}
+
+func TestEWMAGasPriceStrategy(t *testing.T) {
+	s := &ewmaGasPriceStrategy{
+		base:   &fixedGasPriceStrategy{prices: []int64{100, 200, 200}},
+		weight: 50,
+	}
+	for i, want := range []int64{100, 150, 175} {
+		price, err := s.SuggestGasPrice(&tokens.BuildTxArgs{})
+		if err != nil {
+			t.Fatalf("suggest gas price failed: %v", err)
+		}
+		if price.Int64() != want {
+			t.Errorf("round %v: want %v, got %v", i, want, price)
+		}
+	}
+}
+
+func TestUrgencyGasPriceStrategy(t *testing.T) {
+	s := &urgencyGasPriceStrategy{
+		base: &fixedGasPriceStrategy{prices: []int64{100}},
+		tiers: []*params.UrgencyTierConfig{
+			{MinAge: 600, PlusPercent: 10},
+			{MinAge: 1800, PlusPercent: 30},
+		},
+	}
+	now := common.NowMilli()
+	testCases := []struct {
+		initTime int64
+		want     int64
+	}{
+		{0, 100},
+		{now, 100},
+		{now - 700*1000, 110},
+		{now - 2000*1000, 130},
+	}
+	for i, tc := range testCases {
+		price, err := s.SuggestGasPrice(&tokens.BuildTxArgs{InitTime: tc.initTime})
+		if err != nil {
+			t.Fatalf("suggest gas price failed: %v", err)
+		}
+		if price.Int64() != tc.want {
+			t.Errorf("case %v: want %v, got %v", i, tc.want, price)
+		}
+	}
+}
@@ -0,0 +1,67 @@
+package eth
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/anyswap/CrossChain-Router/v3/common"
+	"github.com/anyswap/CrossChain-Router/v3/common/hexutil"
+	"github.com/anyswap/CrossChain-Router/v3/params"
+	"github.com/anyswap/CrossChain-Router/v3/router"
+	"github.com/anyswap/CrossChain-Router/v3/tokens"
+	"github.com/anyswap/CrossChain-Router/v3/types"
+)
+
+const nonceGapFillGasLimit = uint64(21000)
+
+// buildNonceGapFillTx build zero value self transfer of mpc with the specified nonce
+func (b *Bridge) buildNonceGapFillTx(args *tokens.BuildTxArgs) (rawTx interface{}, err error) {
+	if args.Input != nil && len(*args.Input) > 0 {
+		return nil, errors.New("forbid build nonce gap fill tx with input data")
+	}
+	if args.Value != nil && args.Value.Sign() != 0 {
+		return nil, errors.New("forbid build nonce gap fill tx with value")
+	}
+	if !common.IsEqualIgnoreCase(args.From, args.To) {
+		return nil, errors.New("nonce gap fill tx is not self transfer")
+	}
+	if !router.IsRouterMPC(args.From) {
+		return nil, tokens.ErrSenderMismatch
+	}
+	extra := getOrInitEthExtra(args)
+	if extra.Nonce == nil {
+		return nil, errors.New("nonce gap fill tx without nonce")
+	}
+
+	args.Value = big.NewInt(0)
+	args.Input = &hexutil.Bytes{}
+	if extra.Gas == nil {
+		extra.Gas = new(uint64)
+		*extra.Gas = nonceGapFillGasLimit
+	}
+	if extra.AccessList == nil && params.IsAccessListTxEnabled(b.ChainConfig.ChainID) {
+		extra.AccessList = &types.AccessList{}
+	}
+
+	err = b.setDefaults(args)
+	if err != nil {
+		return nil, err
+	}
+
+	return b.buildTx(args)
+}
+
+func verifyNonceGapFillTx(rawTx interface{}, args *tokens.BuildTxArgs) (*types.Transaction, error) {
+	tx, ok := rawTx.(*types.Transaction)
+	if !ok {
+		return nil, errors.New("[sign] wrong raw tx param")
+	}
+	if tx.To() == nil || !common.IsEqualIgnoreCase(tx.To().String(), args.From) {
+		return nil, fmt.Errorf("[sign] nonce gap fill tx receiver mismatch. have %v want %v", tx.To(), args.From)
+	}
+	if tx.Value().Sign() != 0 || len(tx.Data()) != 0 {
+		return nil, errors.New("[sign] nonce gap fill tx with value or input data")
+	}
+	return tx, nil
+}
@@ -0,0 +1,218 @@
+package eth
+
+import (
+	"fmt"
+	"math/big"
+	"strings"
+
+	"github.com/anyswap/CrossChain-Router/v3/log"
+	"github.com/anyswap/CrossChain-Router/v3/params"
+	"github.com/anyswap/CrossChain-Router/v3/tokens"
+	"github.com/anyswap/CrossChain-Router/v3/types"
+)
+
+const (
+	defaultReplaceMinBumpPercent = 10 // txpool.pricebump of geth
+	defaultReplaceFeeHistorySize = 10
+)
+
+type replacedTxFees struct {
+	txHash    string
+	gasPrice  *big.Int
+	gasTipCap *big.Int
+	gasFeeCap *big.Int
+}
+
+// SetReplaceTxFees set fees of replace tx by the replace policy.
+// if the replaced tx is still in pool, the fees are bumped from the old ones,
+// otherwise they are the suggested ones.
+// the gas price specified by admin is used as given (only limited by the max).
+func (b *Bridge) SetReplaceTxFees(args *tokens.BuildTxArgs, oldSwapTxs []string) (err error) {
+	chainID := b.ChainConfig.ChainID
+	extra := getOrInitEthExtra(args)
+	nonce := args.GetTxNonce()
+
+	bumpPercent := uint64(defaultReplaceMinBumpPercent)
+	blockCount := defaultReplaceFeeHistorySize
+	var inPoolBumpPercent, risePlusPercent uint64
+	if policy := params.GetReplacePolicyConfig(chainID); policy != nil {
+		if policy.MinBumpPercent > 0 {
+			bumpPercent = policy.MinBumpPercent
+		}
+		inPoolBumpPercent = policy.InPoolBumpPercent
+		if policy.BlockCountFeeHistory > 0 {
+			blockCount = policy.BlockCountFeeHistory
+		}
+		risePlusPercent = policy.BaseFeeRisePlusPercent
+	}
+
+	oldFees := b.findReplacedTxInPool(args.From, nonce, oldSwapTxs)
+	if oldFees != nil {
+		bumpPercent += inPoolBumpPercent
+	}
+	isBaseFeeRising := risePlusPercent > 0 && b.isBaseFeeRising(blockCount)
+
+	ctx := []interface{}{
+		"chainID", chainID, "swapID", args.SwapID, "nonce", nonce,
+		"replaceNum", args.GetReplaceNum(), "inPool", oldFees != nil,
+		"baseFeeRising", isBaseFeeRising, "bumpPercent", bumpPercent,
+	}
+	if oldFees != nil {
+		ctx = append(ctx, "oldTx", oldFees.txHash)
+	}
+
+	if params.IsDynamicFeeTxEnabled(chainID) {
+		dfConfig := params.GetDynamicFeeTxConfig(chainID)
+		if dfConfig == nil {
+			return tokens.ErrMissDynamicFeeConfig
+		}
+		gasTipCap, errf := b.getGasTipCap(args)
+		if errf != nil {
+			return errf
+		}
+		gasFeeCap, errf := b.getGasFeeCap(args, gasTipCap)
+		if errf != nil {
+			return errf
+		}
+		if isBaseFeeRising {
+			gasFeeCap = addPercent(gasFeeCap, risePlusPercent)
+		}
+		var oldTipCap, oldFeeCap *big.Int
+		if oldFees != nil {
+			oldTipCap, oldFeeCap = oldFees.gasTipCap, oldFees.gasFeeCap
+		}
+		gasTipCap, err = calcReplaceFee(gasTipCap, oldTipCap, bumpPercent, dfConfig.GetMaxGasTipCap())
+		if err != nil {
+			return fmt.Errorf("%w: gas tip cap", err)
+		}
+		gasFeeCap, err = calcReplaceFee(gasFeeCap, oldFeeCap, bumpPercent, dfConfig.GetMaxGasFeeCap())
+		if err != nil {
+			return fmt.Errorf("%w: gas fee cap", err)
+		}
+		if gasFeeCap.Cmp(gasTipCap) < 0 {
+			gasFeeCap = new(big.Int).Set(gasTipCap)
+		}
+		extra.GasTipCap = gasTipCap
+		extra.GasFeeCap = gasFeeCap
+		extra.GasPrice = nil
+		ctx = append(ctx, "oldGasTipCap", oldTipCap, "oldGasFeeCap", oldFeeCap,
+			"gasTipCap", gasTipCap, "gasFeeCap", gasFeeCap)
+	} else {
+		var oldGasPrice *big.Int
+		if oldFees != nil {
+			oldGasPrice = oldFees.gasPrice
+		}
+		gasPrice := extra.GasPrice
+		if gasPrice != nil { // specified by admin
+			gasPrice, _ = calcReplaceFee(gasPrice, nil, 0, params.GetMaxGasPrice(chainID))
+			ctx = append(ctx, "adminGasPrice", extra.GasPrice)
+		} else {
+			gasPrice, err = b.getGasPrice(args)
+			if err != nil {
+				return err
+			}
+			if isBaseFeeRising {
+				gasPrice = addPercent(gasPrice, risePlusPercent)
+			}
+			gasPrice, err = calcReplaceFee(gasPrice, oldGasPrice, bumpPercent, params.GetMaxGasPrice(chainID))
+			if err != nil {
+				return fmt.Errorf("%w: gas price", err)
+			}
+		}
+		extra.GasPrice = gasPrice
+		extra.GasTipCap = nil
+		extra.GasFeeCap = nil
+		ctx = append(ctx, "oldGasPrice", oldGasPrice, "gasPrice", gasPrice)
+	}
+	log.Info("set replace tx fees", ctx...)
+	return nil
+}
+
+// calcReplaceFee the result is at least 'bumpPercent' higher than the old fee (if exist),
+// and not higher than the max fee (if exist)
+func calcReplaceFee(fee, oldFee *big.Int, bumpPercent uint64, maxFee *big.Int) (*big.Int, error) {
+	result := new(big.Int).Set(fee)
+	if oldFee != nil {
+		minFee := addPercent(oldFee, bumpPercent)
+		if bumpPercent > 0 && minFee.Cmp(oldFee) == 0 {
+			minFee.Add(minFee, big.NewInt(1))
+		}
+		if maxFee != nil && minFee.Cmp(maxFee) > 0 {
+			return nil, fmt.Errorf("%w: min %v max %v", tokens.ErrReplaceFeeTooHigh, minFee, maxFee)
+		}
+		if result.Cmp(minFee) < 0 {
+			result = minFee
+		}
+	}
+	if maxFee != nil && result.Cmp(maxFee) > 0 {
+		result = new(big.Int).Set(maxFee)
+	}
+	return result, nil
+}
+
+func addPercent(value *big.Int, percent uint64) *big.Int {
+	result := new(big.Int).Mul(value, new(big.Int).SetUint64(100+percent))
+	return result.Div(result, big.NewInt(100))
+}
+
+// findReplacedTxInPool find the tx with the same sender and nonce in pool
+func (b *Bridge) findReplacedTxInPool(sender string, nonce uint64, oldSwapTxs []string) *replacedTxFees {
+	if pendingTxs, err := b.GetPendingTransactions(); err == nil {
+		for _, tx := range pendingTxs {
+			if tx.From == nil || !strings.EqualFold(tx.From.String(), sender) || tx.GetAccountNonce() != nonce {
+				continue
+			}
+			return getReplacedTxFees(tx)
+		}
+	}
+	// eth_pendingTransactions only return txs of local accounts in some nodes
+	for i := len(oldSwapTxs) - 1; i >= 0; i-- {
+		if oldSwapTxs[i] == "" {
+			continue
+		}
+		tx, err := b.GetTransactionByHash(oldSwapTxs[i])
+		if err != nil || tx == nil || tx.BlockNumber != nil {
+			continue
+		}
+		if tx.GetAccountNonce() != nonce {
+			continue
+		}
+		return getReplacedTxFees(tx)
+	}
+	return nil
+}
+
+func getReplacedTxFees(tx *types.RPCTransaction) *replacedTxFees {
+	fees := &replacedTxFees{}
+	if tx.Hash != nil {
+		fees.txHash = tx.Hash.String()
+	}
+	if tx.Price != nil {
+		fees.gasPrice = tx.Price.ToInt()
+	}
+	if tx.GasTipCap != nil && tx.GasFeeCap != nil {
+		fees.gasTipCap = tx.GasTipCap.ToInt()
+		fees.gasFeeCap = tx.GasFeeCap.ToInt()
+	} else {
+		// legacy tx has the same tip cap and fee cap
+		fees.gasTipCap = fees.gasPrice
+		fees.gasFeeCap = fees.gasPrice
+	}
+	if fees.gasPrice == nil {
+		fees.gasPrice = fees.gasFeeCap
+	}
+	return fees
+}
+
+func (b *Bridge) isBaseFeeRising(blockCount int) bool {
+	feeHistory, err := b.FeeHistory(blockCount, nil)
+	if err != nil {
+		log.Warn("get fee history failed", "chainID", b.ChainConfig.ChainID, "blockCount", blockCount, "err", err)
+		return false
+	}
+	length := len(feeHistory.BaseFee)
+	if length < 2 || feeHistory.BaseFee[0] == nil || feeHistory.BaseFee[length-1] == nil {
+		return false
+	}
+	return feeHistory.BaseFee[length-1].ToInt().Cmp(feeHistory.BaseFee[0].ToInt()) > 0
+}
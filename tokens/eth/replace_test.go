@@ -0,0 +1,47 @@
+package eth
+
+import (
+	"errors"
+	"math/big"
+	"testing"
+
+	"github.com/anyswap/CrossChain-Router/v3/tokens"
+)
+
+func TestCalcReplaceFee(t *testing.T) {
+	testCases := []struct {
+		fee, oldFee, maxFee int64 // zero old or max fee means not exist
+		bumpPercent         uint64
+		want                int64
+		wantErr             error
+	}{
+		{fee: 100, want: 100},
+		{fee: 100, oldFee: 100, bumpPercent: 10, want: 110},
+		{fee: 120, oldFee: 100, bumpPercent: 10, want: 120},
+		{fee: 5, oldFee: 5, bumpPercent: 10, want: 6},
+		{fee: 150, oldFee: 100, bumpPercent: 10, maxFee: 130, want: 130},
+		{fee: 100, oldFee: 100, bumpPercent: 10, maxFee: 105, wantErr: tokens.ErrReplaceFeeTooHigh},
+	}
+	toBig := func(v int64) *big.Int {
+		if v == 0 {
+			return nil
+		}
+		return big.NewInt(v)
+	}
+	for i, tc := range testCases {
+		fee, err := calcReplaceFee(big.NewInt(tc.fee), toBig(tc.oldFee), tc.bumpPercent, toBig(tc.maxFee))
+		if tc.wantErr != nil {
+			if !errors.Is(err, tc.wantErr) {
+				t.Errorf("case %v: want error %v, got %v", i, tc.wantErr, err)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("case %v: unexpected error %v", i, err)
+			continue
+		}
+		if fee.Int64() != tc.want {
+			t.Errorf("case %v: want %v, got %v", i, tc.want, fee)
+		}
+	}
+}
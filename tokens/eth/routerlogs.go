@@ -0,0 +1,107 @@
+package eth
+
+import (
+	"errors"
+	"math/big"
+	"strings"
+
+	"github.com/anyswap/CrossChain-Router/v3/common"
+	"github.com/anyswap/CrossChain-Router/v3/tokens"
+	"github.com/anyswap/CrossChain-Router/v3/types"
+)
+
+var (
+	// LogAnySwapIn(bytes32 indexed txhash, address indexed token, address indexed to, uint amount, uint fromChainID, uint toChainID);
+	LogAnySwapInTopic = common.FromHex("0xaac9ce45fe3adf5143598c4f18a369591a20a3384aedaf1b525d29127e1fcd55")
+
+	errWrongSwapinLog = errors.New("wrong swapin log")
+)
+
+// ScanRouterLogs impl
+func (b *Bridge) ScanRouterLogs(start, end uint64) (swapoutTxs []string, swapins []*tokens.SwapinLog, err error) {
+	routers := b.getAllRouterContracts()
+	if len(routers) == 0 {
+		return nil, nil, tokens.ErrMissRouterInfo
+	}
+	topics := [][]common.Hash{{
+		common.BytesToHash(LogAnySwapOutTopic),
+		common.BytesToHash(LogAnySwapOut2Topic),
+		common.BytesToHash(LogAnySwapOutAndCallTopic),
+		common.BytesToHash(LogAnySwapTradeTokensForTokensTopic),
+		common.BytesToHash(LogAnySwapTradeTokensForNativeTopic),
+		common.BytesToHash(LogAnySwapInTopic),
+	}}
+	logs, err := b.GetLogs(&types.FilterQuery{
+		FromBlock: new(big.Int).SetUint64(start),
+		ToBlock:   new(big.Int).SetUint64(end),
+		Addresses: routers,
+		Topics:    topics,
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	exist := make(map[string]struct{})
+	for _, rlog := range logs {
+		if rlog.Removed != nil && *rlog.Removed {
+			continue
+		}
+		if rlog.TxHash == nil || len(rlog.Topics) == 0 {
+			continue
+		}
+		if rlog.Topics[0] == common.BytesToHash(LogAnySwapInTopic) {
+			swapin, errf := parseSwapinLog(rlog)
+			if errf != nil {
+				return nil, nil, errf
+			}
+			swapins = append(swapins, swapin)
+			continue
+		}
+		txHash := strings.ToLower(rlog.TxHash.Hex())
+		if _, ok := exist[txHash]; !ok {
+			exist[txHash] = struct{}{}
+			swapoutTxs = append(swapoutTxs, txHash)
+		}
+	}
+	return swapoutTxs, swapins, nil
+}
+
+func (b *Bridge) getAllRouterContracts() []common.Address {
+	var routers []common.Address
+	exist := make(map[common.Address]struct{})
+	addRouter := func(router string) {
+		if !common.IsHexAddress(router) {
+			return
+		}
+		addr := common.HexToAddress(router)
+		if _, ok := exist[addr]; !ok {
+			exist[addr] = struct{}{}
+			routers = append(routers, addr)
+		}
+	}
+	addRouter(b.ChainConfig.RouterContract)
+	b.TokenConfigMap.Range(func(k, v interface{}) bool {
+		addRouter(v.(*tokens.TokenConfig).RouterContract)
+		return true
+	})
+	return routers
+}
+
+func parseSwapinLog(rlog *types.RPCLog) (*tokens.SwapinLog, error) {
+	if len(rlog.Topics) != 4 || rlog.Data == nil || len(*rlog.Data) != 96 ||
+		rlog.BlockNumber == nil || rlog.LogIndex == nil {
+		return nil, errWrongSwapinLog
+	}
+	logData := *rlog.Data
+	return &tokens.SwapinLog{
+		TxHash:      strings.ToLower(rlog.TxHash.Hex()),
+		BlockNumber: rlog.BlockNumber.ToInt().Uint64(),
+		LogIndex:    int(*rlog.LogIndex),
+		SwapID:      strings.ToLower(rlog.Topics[1].Hex()),
+		Token:       common.BytesToAddress(rlog.Topics[2].Bytes()).LowerHex(),
+		To:          common.BytesToAddress(rlog.Topics[3].Bytes()).LowerHex(),
+		Amount:      common.GetBigInt(logData, 0, 32),
+		FromChainID: common.GetBigInt(logData, 32, 32),
+		ToChainID:   common.GetBigInt(logData, 64, 32),
+	}, nil
+}
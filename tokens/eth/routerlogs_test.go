@@ -0,0 +1,52 @@
+package eth
+
+import (
+	"testing"
+
+	"github.com/anyswap/CrossChain-Router/v3/common"
+	"github.com/anyswap/CrossChain-Router/v3/common/hexutil"
+	"github.com/anyswap/CrossChain-Router/v3/types"
+)
+
+func TestParseSwapinLog(t *testing.T) {
+	topic := common.Keccak256Hash([]byte("LogAnySwapIn(bytes32,address,address,uint256,uint256,uint256)"))
+	if topic != common.BytesToHash(LogAnySwapInTopic) {
+		t.Fatalf("wrong swapin topic %v", topic.Hex())
+	}
+
+	txHash := common.HexToHash("0x1234")
+	swapID := common.HexToHash("0xabcd")
+	data := hexutil.Bytes(common.FromHex(
+		"0x00000000000000000000000000000000000000000000000000000000ee6b2800" +
+			"0000000000000000000000000000000000000000000000000000000000000089" +
+			"0000000000000000000000000000000000000000000000000000000000000038"))
+	blockNumber := (*hexutil.Big)(common.BigFromUint64(100))
+	logIndex := hexutil.Uint(5)
+	rlog := &types.RPCLog{
+		Topics: []common.Hash{
+			topic,
+			swapID,
+			common.HexToHash(tTokenAddress),
+			common.HexToHash("0xc8e50e55aeac372572ea4512f718189784720c39"),
+		},
+		Data:        &data,
+		TxHash:      &txHash,
+		BlockNumber: blockNumber,
+		LogIndex:    &logIndex,
+	}
+	swapin, err := parseSwapinLog(rlog)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if swapin.SwapID != swapID.Hex() || swapin.TxHash != txHash.Hex() ||
+		swapin.Token != tTokenAddress || swapin.To != "0xc8e50e55aeac372572ea4512f718189784720c39" ||
+		swapin.BlockNumber != 100 || swapin.LogIndex != 5 ||
+		swapin.Amount.Uint64() != 4000000000 || swapin.FromChainID.Uint64() != 137 || swapin.ToChainID.Uint64() != 56 {
+		t.Fatalf("wrong swapin log %+v", swapin)
+	}
+
+	rlog.Topics = rlog.Topics[:3]
+	if _, err = parseSwapinLog(rlog); err == nil {
+		t.Fatal("parse swapin log with wrong topics should fail")
+	}
+}
@@ -0,0 +1,287 @@
+package eth
+
+import (
+	"crypto/ecdsa"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/anyswap/CrossChain-Router/v3/common"
+	"github.com/anyswap/CrossChain-Router/v3/common/hexutil"
+	"github.com/anyswap/CrossChain-Router/v3/log"
+	"github.com/anyswap/CrossChain-Router/v3/mpc"
+	"github.com/anyswap/CrossChain-Router/v3/params"
+	"github.com/anyswap/CrossChain-Router/v3/router"
+	"github.com/anyswap/CrossChain-Router/v3/rpc/client"
+	"github.com/anyswap/CrossChain-Router/v3/tokens"
+	"github.com/anyswap/CrossChain-Router/v3/tools"
+	"github.com/anyswap/CrossChain-Router/v3/tools/crypto"
+	"github.com/anyswap/CrossChain-Router/v3/types"
+)
+
+var (
+	// ensure signers impl tokens.Signer
+	_ tokens.Signer = &mpcSigner{}
+	_ tokens.Signer = &localSigner{}
+	_ tokens.Signer = &remoteSigner{}
+
+	defaultRemoteSignerTimeout = 10 // seconds
+)
+
+type signerHolder struct {
+	lock   sync.Mutex
+	config *params.SignerConfig
+	signer tokens.Signer
+}
+
+// getMPCParamsOfSwap get mpc params by value tier of swap
+func (b *Bridge) getMPCParamsOfSwap(args *tokens.BuildTxArgs) *params.MPCConfig {
+	if router.IsHighValueMPCSwap(args) {
+		return params.GetHighValueMPCConfig()
+	}
+	return params.GetMPCConfig(b.UseFastMPC)
+}
+
+// getMPCConfigOfSwap get mpc config by value tier of swap
+func (b *Bridge) getMPCConfigOfSwap(args *tokens.BuildTxArgs) *mpc.Config {
+	if router.IsHighValueMPCSwap(args) {
+		return mpc.GetHighValueMPCConfig()
+	}
+	return mpc.GetMPCConfig(b.UseFastMPC)
+}
+
+// GetSigner get signer backend of this chain
+// (rebuild it if config is changed)
+func (b *Bridge) GetSigner(args *tokens.BuildTxArgs) (tokens.Signer, error) {
+	mpcParams := b.getMPCParamsOfSwap(args)
+	if mpcParams.SignWithPrivateKey {
+		priKey := mpcParams.GetSignerPrivateKey(b.ChainConfig.ChainID)
+		privKey, err := crypto.ToECDSA(common.FromHex(priKey))
+		if err != nil {
+			return nil, err
+		}
+		return &localSigner{b: b, name: "privatekey", key: privKey}, nil
+	}
+	config := params.GetSignerConfig(b.ChainConfig.ChainID)
+	if config == nil {
+		return &mpcSigner{b: b}, nil
+	}
+	holder := b.signer
+	if holder == nil {
+		return newSigner(b, config)
+	}
+	holder.lock.Lock()
+	defer holder.lock.Unlock()
+	if holder.signer == nil || holder.config != config {
+		signer, err := newSigner(b, config)
+		if err != nil {
+			return nil, err
+		}
+		holder.signer = signer
+		holder.config = config
+		log.Info("init signer", "chainID", b.ChainConfig.ChainID, "signer", signer.Name())
+	}
+	return holder.signer, nil
+}
+
+func newSigner(b *Bridge, config *params.SignerConfig) (tokens.Signer, error) {
+	switch config.Type {
+	case params.SignerTypeKeystore:
+		key, err := tools.LoadKeyStore(config.KeystoreFile, config.PasswordFile)
+		if err != nil {
+			return nil, err
+		}
+		return &localSigner{b: b, name: params.SignerTypeKeystore, key: key.PrivateKey}, nil
+	case params.SignerTypeRemote:
+		timeout := config.RemoteTimeout
+		if timeout == 0 {
+			timeout = defaultRemoteSignerTimeout
+		}
+		return &remoteSigner{b: b, url: config.RemoteURL, timeout: timeout}, nil
+	default:
+		return &mpcSigner{b: b}, nil
+	}
+}
+
+// mpcSigner sign tx by mpc
+type mpcSigner struct {
+	b *Bridge
+}
+
+func (s *mpcSigner) Name() string {
+	return params.SignerTypeMPC
+}
+
+func (s *mpcSigner) SignTransaction(rawTx interface{}, args *tokens.BuildTxArgs) (signTx interface{}, txHash string, err error) {
+	tx, ok := rawTx.(*types.Transaction)
+	if !ok {
+		return nil, "", errors.New("wrong raw tx param")
+	}
+	signedTxs, txHashes, err := s.signTransactions([]*types.Transaction{tx}, []*tokens.BuildTxArgs{args})
+	if err != nil {
+		return nil, "", err
+	}
+	return signedTxs[0], txHashes[0], nil
+}
+
+// signTransactions sign txs of the same sender in one mpc sign round
+func (s *mpcSigner) signTransactions(txs []*types.Transaction, args []*tokens.BuildTxArgs) (signedTxs []interface{}, txHashes []string, err error) {
+	b := s.b
+	sender := args[0].From
+	mpcPubkey := router.GetMPCPublicKey(sender)
+	if mpcPubkey == "" {
+		return nil, nil, tokens.ErrMissMPCPublicKey
+	}
+
+	count := len(txs)
+	txids := make([]string, count)
+	msgHashes := make([]string, count)
+	msgContexts := make([]string, count)
+	for i, tx := range txs {
+		txids[i] = args[i].SwapID
+		msgHashes[i] = b.Signer.Hash(tx).String()
+		jsondata, _ := json.Marshal(args[i].GetExtraArgs())
+		msgContexts[i] = string(jsondata)
+	}
+
+	mpcConfig := b.getMPCConfigOfSwap(args[0])
+	logPrefix := b.ChainConfig.BlockChain + " MPCSignTransaction "
+	log.Info(logPrefix+"start", "txid", txids, "msghash", msgHashes, "fastMPC", mpcConfig.IsFastMPC, "highValueMPC", mpcConfig.IsHighValueMPC)
+	keyID, rsvs, err := mpcConfig.DoSignEC(mpcPubkey, msgHashes, msgContexts)
+	if err != nil {
+		return nil, nil, err
+	}
+	log.Info(logPrefix+"finished", "keyID", keyID, "txid", txids, "msghash", msgHashes)
+
+	if len(rsvs) != count {
+		log.Warn("get sign status rsvs count mismatch",
+			"rsvs", len(rsvs), "want", count, "keyID", keyID, "txid", txids)
+		return nil, nil, errors.New("get sign status rsvs count mismatch")
+	}
+
+	signedTxs = make([]interface{}, count)
+	txHashes = make([]string, count)
+	for i, rsv := range rsvs {
+		txid := txids[i]
+		log.Trace(logPrefix+"get rsv signature success", "keyID", keyID, "txid", txid, "rsv", rsv)
+		signature := common.FromHex(rsv)
+		if len(signature) != crypto.SignatureLength {
+			log.Error("wrong signature length", "keyID", keyID, "txid", txid, "have", len(signature), "want", crypto.SignatureLength)
+			return nil, nil, errors.New("wrong signature length")
+		}
+
+		signedTx, errs := b.signTxWithSignature(txs[i], signature, common.HexToAddress(sender))
+		if errs != nil {
+			return nil, nil, errs
+		}
+		signedTxs[i] = signedTx
+		txHashes[i] = signedTx.Hash().String()
+		log.Info(logPrefix+"success", "keyID", keyID, "txid", txid, "txhash", txHashes[i], "nonce", signedTx.Nonce())
+	}
+	return signedTxs, txHashes, nil
+}
+
+// localSigner sign tx with local private key (keystore or testing)
+type localSigner struct {
+	b    *Bridge
+	name string
+	key  *ecdsa.PrivateKey
+}
+
+func (s *localSigner) Name() string {
+	return s.name
+}
+
+func (s *localSigner) SignTransaction(rawTx interface{}, args *tokens.BuildTxArgs) (signTx interface{}, txHash string, err error) {
+	signer := crypto.PubkeyToAddress(s.key.PublicKey)
+	if !strings.EqualFold(signer.String(), args.From) {
+		return nil, "", fmt.Errorf("%v signer address mismatch. have %v want %v", s.name, signer.String(), args.From)
+	}
+	return s.b.signTxWithPrivateKey(rawTx, s.key)
+}
+
+// remoteSigner sign tx by remote signer with web3signer compatible api
+type remoteSigner struct {
+	b       *Bridge
+	url     string
+	timeout int
+}
+
+// remoteSignTxArgs args of 'eth_signTransaction'
+type remoteSignTxArgs struct {
+	From                 common.Address    `json:"from"`
+	To                   *common.Address   `json:"to,omitempty"`
+	Gas                  hexutil.Uint64    `json:"gas"`
+	GasPrice             *hexutil.Big      `json:"gasPrice,omitempty"`
+	MaxFeePerGas         *hexutil.Big      `json:"maxFeePerGas,omitempty"`
+	MaxPriorityFeePerGas *hexutil.Big      `json:"maxPriorityFeePerGas,omitempty"`
+	Value                *hexutil.Big      `json:"value"`
+	Nonce                hexutil.Uint64    `json:"nonce"`
+	Data                 hexutil.Bytes     `json:"data"`
+	AccessList           *types.AccessList `json:"accessList,omitempty"`
+	ChainID              *hexutil.Big      `json:"chainId,omitempty"`
+}
+
+func (s *remoteSigner) Name() string {
+	return params.SignerTypeRemote
+}
+
+func (s *remoteSigner) SignTransaction(rawTx interface{}, args *tokens.BuildTxArgs) (signTx interface{}, txHash string, err error) {
+	b := s.b
+	tx, ok := rawTx.(*types.Transaction)
+	if !ok {
+		return nil, "", errors.New("wrong raw tx param")
+	}
+
+	signArgs := &remoteSignTxArgs{
+		From:    common.HexToAddress(args.From),
+		To:      tx.To(),
+		Gas:     hexutil.Uint64(tx.Gas()),
+		Value:   (*hexutil.Big)(tx.Value()),
+		Nonce:   hexutil.Uint64(tx.Nonce()),
+		Data:    tx.Data(),
+		ChainID: (*hexutil.Big)(b.SignerChainID),
+	}
+	switch tx.Type() {
+	case types.DynamicFeeTxType:
+		signArgs.MaxFeePerGas = (*hexutil.Big)(tx.GasFeeCap())
+		signArgs.MaxPriorityFeePerGas = (*hexutil.Big)(tx.GasTipCap())
+	default:
+		signArgs.GasPrice = (*hexutil.Big)(tx.GasPrice())
+	}
+	if tx.Type() != types.LegacyTxType {
+		accessList := tx.AccessList()
+		signArgs.AccessList = &accessList
+	}
+
+	txid := args.SwapID
+	logPrefix := b.ChainConfig.BlockChain + " RemoteSignTransaction "
+	log.Info(logPrefix+"start", "txid", txid, "nonce", tx.Nonce())
+	var result string
+	err = client.RPCPostWithTimeout(s.timeout, &result, s.url, "eth_signTransaction", signArgs)
+	if err != nil {
+		return nil, "", err
+	}
+
+	signedTx := new(types.Transaction)
+	if err = signedTx.UnmarshalBinary(common.FromHex(result)); err != nil {
+		return nil, "", fmt.Errorf("decode remote signed tx failed, %w", err)
+	}
+	// the remote signer must sign the tx as it is
+	if b.Signer.Hash(signedTx) != b.Signer.Hash(tx) {
+		return nil, "", errors.New("remote signed tx mismatch")
+	}
+	sender, err := types.Sender(b.Signer, signedTx)
+	if err != nil {
+		return nil, "", err
+	}
+	if sender != common.HexToAddress(args.From) {
+		return nil, "", fmt.Errorf("remote signer address mismatch. have %v want %v", sender.String(), args.From)
+	}
+
+	txHash = signedTx.Hash().String()
+	log.Info(logPrefix+"success", "txid", txid, "txhash", txHash, "nonce", signedTx.Nonce())
+	return signedTx, txHash, nil
+}
@@ -0,0 +1,74 @@
+package eth
+
+import (
+	"encoding/json"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/anyswap/CrossChain-Router/v3/common"
+	"github.com/anyswap/CrossChain-Router/v3/common/hexutil"
+	"github.com/anyswap/CrossChain-Router/v3/tokens"
+	"github.com/anyswap/CrossChain-Router/v3/tools/crypto"
+	"github.com/anyswap/CrossChain-Router/v3/types"
+)
+
+func TestRemoteSigner(t *testing.T) {
+	key, _ := crypto.GenerateKey()
+	from := crypto.PubkeyToAddress(key.PublicKey)
+
+	b := NewCrossChainBridge()
+	b.ChainConfig = &tokens.ChainConfig{BlockChain: "test", ChainID: "1"}
+	b.SignerChainID = big.NewInt(1)
+	b.Signer = types.MakeSigner("London", b.SignerChainID)
+
+	var tamper bool
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req struct {
+			ID     int                 `json:"id"`
+			Params []*remoteSignTxArgs `json:"params"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil || len(req.Params) != 1 {
+			http.Error(w, "wrong request", http.StatusBadRequest)
+			return
+		}
+		args := req.Params[0]
+		nonce := uint64(args.Nonce)
+		if tamper {
+			nonce++
+		}
+		tx := types.NewTransaction(nonce, *args.To, args.Value.ToInt(), uint64(args.Gas), args.GasPrice.ToInt(), args.Data)
+		signedTx, _ := types.SignTx(tx, b.Signer, key)
+		raw, _ := signedTx.MarshalBinary()
+		_ = json.NewEncoder(w).Encode(map[string]interface{}{
+			"jsonrpc": "2.0",
+			"id":      req.ID,
+			"result":  hexutil.Bytes(raw),
+		})
+	}))
+	defer server.Close()
+
+	signer := &remoteSigner{b: b, url: server.URL, timeout: 5}
+	args := &tokens.BuildTxArgs{From: from.String()}
+	rawTx := types.NewTransaction(3, common.HexToAddress(tRouterAddress), big.NewInt(0), 21000, big.NewInt(1e9), nil)
+
+	signedTx, txHash, err := signer.SignTransaction(rawTx, args)
+	if err != nil {
+		t.Fatalf("remote sign failed: %v", err)
+	}
+	if signedTx.(*types.Transaction).Hash().String() != txHash {
+		t.Errorf("tx hash mismatch")
+	}
+
+	tamper = true
+	if _, _, err = signer.SignTransaction(rawTx, args); err == nil {
+		t.Errorf("want error if remote signer changes the tx")
+	}
+
+	args.From = tRouterAddress
+	tamper = false
+	if _, _, err = signer.SignTransaction(rawTx, args); err == nil {
+		t.Errorf("want error if remote signer address mismatch")
+	}
+}
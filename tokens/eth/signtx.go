@@ -1,14 +1,13 @@
 package eth
 
 import (
-	"encoding/json"
+	"crypto/ecdsa"
 	"errors"
 	"fmt"
 	"strings"
 
 	"github.com/anyswap/CrossChain-Router/v3/common"
 	"github.com/anyswap/CrossChain-Router/v3/log"
-	"github.com/anyswap/CrossChain-Router/v3/mpc"
 	"github.com/anyswap/CrossChain-Router/v3/params"
 	"github.com/anyswap/CrossChain-Router/v3/router"
 	"github.com/anyswap/CrossChain-Router/v3/tokens"
@@ -36,67 +35,80 @@ func (b *Bridge) verifyTransactionReceiver(rawTx interface{}, tokenID string) (*
 
 // MPCSignTransaction mpc sign raw tx
 func (b *Bridge) MPCSignTransaction(rawTx interface{}, args *tokens.BuildTxArgs) (signTx interface{}, txHash string, err error) {
-	tx, err := b.verifyTransactionReceiver(rawTx, args.GetTokenID())
+	tx, err := b.verifyTransactionToSign(rawTx, args)
 	if err != nil {
 		return nil, "", err
 	}
 
-	if !params.IsDynamicFeeTxEnabled(b.ChainConfig.ChainID) {
-		gasPrice, errt := b.getGasPrice(args)
-		if errt == nil && args.Extra.EthExtra.GasPrice.Cmp(gasPrice) < 0 {
-			log.Info(b.ChainConfig.BlockChain+" MPCSignTransaction update gas price", "txid", args.SwapID, "oldGasPrice", args.Extra.EthExtra.GasPrice, "newGasPrice", gasPrice)
-			args.Extra.EthExtra.GasPrice = gasPrice
-			tx.SetGasPrice(gasPrice)
-		}
+	signer, err := b.GetSigner(args)
+	if err != nil {
+		return nil, "", err
 	}
+	return signer.SignTransaction(tx, args)
+}
 
-	mpcParams := params.GetMPCConfig(b.UseFastMPC)
-	if mpcParams.SignWithPrivateKey {
-		priKey := mpcParams.GetSignerPrivateKey(b.ChainConfig.ChainID)
-		return b.SignTransactionWithPrivateKey(rawTx, priKey)
+// MPCSignTransactions mpc sign raw txs of consecutive nonces in one sign round
+func (b *Bridge) MPCSignTransactions(rawTxs []interface{}, args []*tokens.BuildTxArgs) (signedTxs []interface{}, txHashes []string, err error) {
+	if len(rawTxs) == 0 || len(rawTxs) != len(args) {
+		return nil, nil, errors.New("[sign] wrong count of batch raw txs")
 	}
-
-	mpcPubkey := router.GetMPCPublicKey(args.From)
-	if mpcPubkey == "" {
-		return nil, "", tokens.ErrMissMPCPublicKey
+	txs := make([]*types.Transaction, len(rawTxs))
+	for i, rawTx := range rawTxs {
+		if args[i].SwapType == tokens.NonceGapFillType {
+			return nil, nil, errors.New("[sign] nonce gap fill tx can not be batched")
+		}
+		if i > 0 && !strings.EqualFold(args[i].From, args[0].From) {
+			return nil, nil, errors.New("[sign] batch txs have different senders")
+		}
+		if i > 0 && router.IsHighValueMPCSwap(args[i]) != router.IsHighValueMPCSwap(args[0]) {
+			return nil, nil, errors.New("[sign] batch txs have different mpc value tiers")
+		}
+		txs[i], err = b.verifyTransactionToSign(rawTx, args[i])
+		if err != nil {
+			return nil, nil, err
+		}
+		if i > 0 && txs[i].Nonce() != txs[i-1].Nonce()+1 {
+			return nil, nil, fmt.Errorf("[sign] batch txs have inconsecutive nonces %v and %v", txs[i-1].Nonce(), txs[i].Nonce())
+		}
 	}
 
-	signer := b.Signer
-	msgHash := signer.Hash(tx)
-	jsondata, _ := json.Marshal(args.GetExtraArgs())
-	msgContext := string(jsondata)
-
-	txid := args.SwapID
-	logPrefix := b.ChainConfig.BlockChain + " MPCSignTransaction "
-	log.Info(logPrefix+"start", "txid", txid, "msghash", msgHash.String())
-	mpcConfig := mpc.GetMPCConfig(b.UseFastMPC)
-	keyID, rsvs, err := mpcConfig.DoSignOneEC(mpcPubkey, msgHash.String(), msgContext)
+	signer, err := b.GetSigner(args[0])
 	if err != nil {
-		return nil, "", err
+		return nil, nil, err
 	}
-	log.Info(logPrefix+"finished", "keyID", keyID, "txid", txid, "msghash", msgHash.String())
-
-	if len(rsvs) != 1 {
-		log.Warn("get sign status require one rsv but return many",
-			"rsvs", len(rsvs), "keyID", keyID, "txid", txid)
-		return nil, "", errors.New("get sign status require one rsv but return many")
+	if s, ok := signer.(*mpcSigner); ok {
+		return s.signTransactions(txs, args)
 	}
-
-	rsv := rsvs[0]
-	log.Trace(logPrefix+"get rsv signature success", "keyID", keyID, "txid", txid, "rsv", rsv)
-	signature := common.FromHex(rsv)
-	if len(signature) != crypto.SignatureLength {
-		log.Error("wrong signature length", "keyID", keyID, "txid", txid, "have", len(signature), "want", crypto.SignatureLength)
-		return nil, "", errors.New("wrong signature length")
+	signedTxs = make([]interface{}, len(txs))
+	txHashes = make([]string, len(txs))
+	for i, tx := range txs {
+		signedTxs[i], txHashes[i], err = signer.SignTransaction(tx, args[i])
+		if err != nil {
+			return nil, nil, err
+		}
 	}
+	return signedTxs, txHashes, nil
+}
 
-	signedTx, err := b.signTxWithSignature(tx, signature, common.HexToAddress(args.From))
+func (b *Bridge) verifyTransactionToSign(rawTx interface{}, args *tokens.BuildTxArgs) (tx *types.Transaction, err error) {
+	if args.SwapType == tokens.NonceGapFillType {
+		tx, err = verifyNonceGapFillTx(rawTx, args)
+	} else {
+		tx, err = b.verifyTransactionReceiver(rawTx, args.GetTokenID())
+	}
 	if err != nil {
-		return nil, "", err
+		return nil, err
 	}
-	txHash = signedTx.Hash().String()
-	log.Info(logPrefix+"success", "keyID", keyID, "txid", txid, "txhash", txHash, "nonce", signedTx.Nonce())
-	return signedTx, txHash, nil
+
+	if !params.IsDynamicFeeTxEnabled(b.ChainConfig.ChainID) {
+		gasPrice, errt := b.getGasPrice(args)
+		if errt == nil && args.Extra.EthExtra.GasPrice.Cmp(gasPrice) < 0 {
+			log.Info(b.ChainConfig.BlockChain+" MPCSignTransaction update gas price", "txid", args.SwapID, "oldGasPrice", args.Extra.EthExtra.GasPrice, "newGasPrice", gasPrice)
+			args.Extra.EthExtra.GasPrice = gasPrice
+			tx.SetGasPrice(gasPrice)
+		}
+	}
+	return tx, nil
 }
 
 func (b *Bridge) signTxWithSignature(tx *types.Transaction, signature []byte, signerAddr common.Address) (*types.Transaction, error) {
@@ -124,46 +136,61 @@ func (b *Bridge) signTxWithSignature(tx *types.Transaction, signature []byte, si
 }
 
 // GetSignedTxHashOfKeyID get signed tx hash by keyID (called by oracle)
-func (b *Bridge) GetSignedTxHashOfKeyID(sender, keyID string, rawTx interface{}) (txHash string, err error) {
-	tx, ok := rawTx.(*types.Transaction)
-	if !ok {
-		return "", errors.New("wrong raw tx of keyID " + keyID)
+func (b *Bridge) GetSignedTxHashOfKeyID(args *tokens.BuildTxArgs, keyID string, rawTx interface{}) (txHash string, err error) {
+	txHashes, err := b.GetSignedTxHashesOfKeyID(args, keyID, []interface{}{rawTx})
+	if err != nil {
+		return "", err
 	}
-	mpcConfig := mpc.GetMPCConfig(b.UseFastMPC)
+	return txHashes[0], nil
+}
+
+// GetSignedTxHashesOfKeyID get signed tx hashes of batch sign by keyID (called by oracle)
+// args is one of the batch swaps, which have the same sender and mpc value tier
+func (b *Bridge) GetSignedTxHashesOfKeyID(args *tokens.BuildTxArgs, keyID string, rawTxs []interface{}) (txHashes []string, err error) {
+	mpcConfig := b.getMPCConfigOfSwap(args)
 	rsvs, err := mpcConfig.GetSignStatusByKeyID(keyID)
 	if err != nil {
-		return "", err
+		return nil, err
 	}
-	if len(rsvs) != 1 {
-		return "", errors.New("wrong number of rsvs of keyID " + keyID)
+	if len(rsvs) != len(rawTxs) {
+		return nil, errors.New("wrong number of rsvs of keyID " + keyID)
 	}
 
-	rsv := rsvs[0]
-	signature := common.FromHex(rsv)
-	if len(signature) != crypto.SignatureLength {
-		return "", errors.New("wrong signature of keyID " + keyID)
-	}
+	txHashes = make([]string, len(rawTxs))
+	for i, rawTx := range rawTxs {
+		tx, ok := rawTx.(*types.Transaction)
+		if !ok {
+			return nil, errors.New("wrong raw tx of keyID " + keyID)
+		}
 
-	signedTx, err := b.signTxWithSignature(tx, signature, common.HexToAddress(sender))
-	if err != nil {
-		return "", err
-	}
+		signature := common.FromHex(rsvs[i])
+		if len(signature) != crypto.SignatureLength {
+			return nil, errors.New("wrong signature of keyID " + keyID)
+		}
 
-	txHash = signedTx.Hash().String()
-	return txHash, nil
+		signedTx, err := b.signTxWithSignature(tx, signature, common.HexToAddress(args.From))
+		if err != nil {
+			return nil, err
+		}
+		txHashes[i] = signedTx.Hash().String()
+	}
+	return txHashes, nil
 }
 
 // SignTransactionWithPrivateKey sign tx with private key (use for testing)
 func (b *Bridge) SignTransactionWithPrivateKey(rawTx interface{}, priKey string) (signTx interface{}, txHash string, err error) {
-	tx, ok := rawTx.(*types.Transaction)
-	if !ok {
-		return nil, "", errors.New("wrong raw tx param")
-	}
-
 	privKey, err := crypto.ToECDSA(common.FromHex(priKey))
 	if err != nil {
 		return nil, "", err
 	}
+	return b.signTxWithPrivateKey(rawTx, privKey)
+}
+
+func (b *Bridge) signTxWithPrivateKey(rawTx interface{}, privKey *ecdsa.PrivateKey) (signTx interface{}, txHash string, err error) {
+	tx, ok := rawTx.(*types.Transaction)
+	if !ok {
+		return nil, "", errors.New("wrong raw tx param")
+	}
 
 	signedTx, err := types.SignTx(tx, b.Signer, privKey)
 	if err != nil {
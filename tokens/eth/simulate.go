@@ -0,0 +1,139 @@
+package eth
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+
+	"github.com/anyswap/CrossChain-Router/v3/common"
+	"github.com/anyswap/CrossChain-Router/v3/common/hexutil"
+	"github.com/anyswap/CrossChain-Router/v3/log"
+	"github.com/anyswap/CrossChain-Router/v3/params"
+	"github.com/anyswap/CrossChain-Router/v3/rpc/client"
+	"github.com/anyswap/CrossChain-Router/v3/tokens"
+	"github.com/anyswap/CrossChain-Router/v3/tokens/eth/abicoder"
+)
+
+var (
+	// Error(string)
+	revertErrorFuncHash = common.FromHex("0x08c379a0")
+	// Panic(uint256)
+	revertPanicFuncHash = common.FromHex("0x4e487b71")
+
+	// json-rpc error code of execution reverted (geth)
+	revertErrorCode = 3
+
+	revertPanicReasons = map[uint64]string{
+		0x00: "generic panic",
+		0x01: "assert(false)",
+		0x11: "arithmetic underflow or overflow",
+		0x12: "division or modulo by zero",
+		0x21: "enum overflow",
+		0x22: "invalid encoded storage byte array accessed",
+		0x31: "out-of-bounds array access; popping on an empty array",
+		0x32: "out-of-bounds access of an array or bytesN",
+		0x41: "out of memory",
+		0x51: "uninitialized function",
+	}
+)
+
+func (b *Bridge) shouldSimulateSwapTx(args *tokens.BuildTxArgs) bool {
+	// only the swap server simulate new swaps,
+	// replacing and verifying by oracles are not simulated
+	return params.IsSwapServer &&
+		args.GetReplaceNum() == 0 &&
+		!params.IsSimulateSwapTxDisabled(b.ChainConfig.ChainID)
+}
+
+// simulateSwapTx simulate swap tx with eth_call from mpc at the pending block.
+// returns error wrapping tokens.ErrSimulateTxReverted if the call reverts.
+func (b *Bridge) simulateSwapTx(args *tokens.BuildTxArgs) error {
+	reason, reverted, err := b.SimulateCall(args.From, args.To, args.Value, *args.Input)
+	if err != nil {
+		// do not block swapping if rpc is not available
+		log.Warn("simulate swap tx failed", "swapID", args.SwapID, "logIndex", args.LogIndex,
+			"from", args.From, "to", args.To, "err", err)
+		return nil
+	}
+	if reverted {
+		log.Warn("simulate swap tx reverted", "swapID", args.SwapID, "logIndex", args.LogIndex,
+			"fromChainID", args.FromChainID, "toChainID", args.ToChainID,
+			"from", args.From, "to", args.To, "reason", reason)
+		return fmt.Errorf("%w: %v", tokens.ErrSimulateTxReverted, reason)
+	}
+	return nil
+}
+
+// SimulateCall call eth_call at the pending block and check revert
+func (b *Bridge) SimulateCall(from, to string, value *big.Int, data []byte) (reason string, reverted bool, err error) {
+	reqArgs := map[string]interface{}{
+		"from": from,
+		"to":   to,
+		"data": hexutil.Bytes(data),
+	}
+	if value != nil {
+		reqArgs["value"] = (*hexutil.Big)(value)
+	}
+	gateway := b.GatewayConfig
+	var result hexutil.Bytes
+	for _, apiAddress := range gateway.APIAddress {
+		url := apiAddress
+		err = client.RPCPostWithTimeout(b.RPCClientTimeout, &result, url, "eth_call", reqArgs, "pending")
+		if err == nil {
+			return "", false, nil
+		}
+		if reason, reverted = getRevertReason(err); reverted {
+			return reason, true, nil
+		}
+	}
+	return "", false, wrapRPCQueryError(err, "eth_call", from, to)
+}
+
+func getRevertReason(err error) (reason string, reverted bool) {
+	var dataErr client.DataError
+	if !errors.As(err, &dataErr) {
+		return "", false
+	}
+	message := dataErr.Error()
+	if dataErr.ErrorCode() != revertErrorCode &&
+		!strings.Contains(strings.ToLower(message), "revert") {
+		return "", false
+	}
+	if data, ok := dataErr.ErrorData().(string); ok {
+		// parity/openethereum style: "Reverted 0x..."
+		data = strings.TrimPrefix(data, "Reverted ")
+		if reason = DecodeRevertReason(common.FromHex(data)); reason != "" {
+			return reason, true
+		}
+	}
+	return message, true
+}
+
+// DecodeRevertReason decode revert data to readable reason
+func DecodeRevertReason(data []byte) string {
+	if len(data) < 4 {
+		return ""
+	}
+	funcHash, input := data[:4], data[4:]
+	switch {
+	case bytes.Equal(funcHash, revertErrorFuncHash):
+		reason, err := abicoder.ParseStringInData(input, 0)
+		if err == nil {
+			return reason
+		}
+	case bytes.Equal(funcHash, revertPanicFuncHash):
+		if len(input) >= 32 {
+			code := common.GetBigInt(input, 0, 32)
+			desc := "unknown panic"
+			if code.IsUint64() {
+				if reason, exist := revertPanicReasons[code.Uint64()]; exist {
+					desc = reason
+				}
+			}
+			return fmt.Sprintf("panic: 0x%x (%v)", code, desc)
+		}
+	}
+	return fmt.Sprintf("custom error %v", hexutil.Bytes(data))
+}
@@ -0,0 +1,27 @@
+package eth
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/anyswap/CrossChain-Router/v3/common"
+	"github.com/anyswap/CrossChain-Router/v3/tokens/eth/abicoder"
+)
+
+func TestDecodeRevertReason(t *testing.T) {
+	tests := []struct {
+		data []byte
+		want string
+	}{
+		{nil, ""},
+		{abicoder.PackDataWithFuncHash(revertErrorFuncHash, "AnyswapV6Router: insufficient balance"), "AnyswapV6Router: insufficient balance"},
+		{abicoder.PackDataWithFuncHash(revertPanicFuncHash, big.NewInt(0x11)), "panic: 0x11 (arithmetic underflow or overflow)"},
+		{abicoder.PackDataWithFuncHash(revertPanicFuncHash, big.NewInt(0x99)), "panic: 0x99 (unknown panic)"},
+		{common.FromHex("0x12345678"), "custom error 0x12345678"},
+	}
+	for i, test := range tests {
+		if have := DecodeRevertReason(test.data); have != test.want {
+			t.Errorf("test %v: revert reason mismatch, have '%v' want '%v'", i, have, test.want)
+		}
+	}
+}
@@ -20,6 +20,9 @@ func (b *Bridge) GetTransactionStatus(txHash string) (*tokens.TxStatus, error) {
 	txStatus.Receipt = txr
 	txStatus.BlockHeight = txr.BlockNumber.ToInt().Uint64()
 	txStatus.BlockHash = txr.BlockHash.String()
+	if txr.GasUsed != nil {
+		txStatus.GasUsed = uint64(*txr.GasUsed)
+	}
 
 	if txStatus.BlockHeight != 0 {
 		for i := 0; i < 3; i++ {
@@ -11,6 +11,18 @@ type IMPCSign interface {
 	MPCSignTransaction(rawTx interface{}, args *BuildTxArgs) (signedTx interface{}, txHash string, err error)
 }
 
+// IMPCBatchSign interface (for eth-like)
+type IMPCBatchSign interface {
+	// MPCSignTransactions sign txs of consecutive nonces in one mpc sign round
+	MPCSignTransactions(rawTxs []interface{}, args []*BuildTxArgs) (signedTxs []interface{}, txHashes []string, err error)
+}
+
+// Signer interface (signer backend of IMPCSign)
+type Signer interface {
+	Name() string
+	SignTransaction(rawTx interface{}, args *BuildTxArgs) (signedTx interface{}, txHash string, err error)
+}
+
 // IBridgeConfg interface
 // implemented by 'CrossChainBridgeBase'
 type IBridgeConfg interface {
@@ -55,8 +67,21 @@ type ISwapTrade interface {
 	GetPairFor(factory, token0, token1 string) (string, error)
 }
 
+// ReplaceFeeSetter interface (for eth-like)
+type ReplaceFeeSetter interface {
+	// SetReplaceTxFees set fees of replace tx by the replace policy
+	SetReplaceTxFees(args *BuildTxArgs, oldSwapTxs []string) error
+}
+
 // NonceSetter interface (for eth-like)
 type NonceSetter interface {
 	GetPoolNonce(address, height string) (uint64, error)
 	RecycleSwapNonce(sender string, nonce uint64)
 }
+
+// RouterLogScanner interface (for eth-like)
+type RouterLogScanner interface {
+	// ScanRouterLogs scan logs of router contracts in block range [start, end],
+	// returns hashes of txs having swapout logs and the swapin logs.
+	ScanRouterLogs(start, end uint64) (swapoutTxs []string, swapins []*SwapinLog, err error)
+}
@@ -4,6 +4,7 @@ import (
 	"math/big"
 
 	"github.com/anyswap/CrossChain-Router/v3/common/hexutil"
+	"github.com/anyswap/CrossChain-Router/v3/types"
 )
 
 // SwapType type
@@ -17,6 +18,10 @@ const (
 	AnyCallSwapType
 
 	MaxValidSwapType
+
+	// NonceGapFillType is not a router swap type,
+	// it marks the zero value self transfer to fill mpc nonce gap
+	NonceGapFillType SwapType = 100
 )
 
 // SwapSubType constants
@@ -32,6 +37,8 @@ func (s SwapType) String() string {
 		return "nftswap"
 	case AnyCallSwapType:
 		return "anycallswap"
+	case NonceGapFillType:
+		return "noncegapfill"
 	default:
 		return "unknownswap"
 	}
@@ -138,6 +145,7 @@ type TxStatus struct {
 	BlockHeight   uint64      `json:"blockHeight"`
 	BlockHash     string      `json:"blockHash"`
 	BlockTime     uint64      `json:"blockTime"`
+	GasUsed       uint64      `json:"gasUsed,omitempty"`
 }
 
 // StatusInterface interface
@@ -158,6 +166,19 @@ func (s *TxStatus) IsSwapTxOnChainAndFailed() bool {
 	return false
 }
 
+// SwapinLog swapin log of router contract on dest chain
+type SwapinLog struct {
+	TxHash      string   `json:"txhash"`
+	BlockNumber uint64   `json:"blockNumber"`
+	LogIndex    int      `json:"logIndex"` // index in block
+	SwapID      string   `json:"swapID"`   // source tx hash
+	Token       string   `json:"token"`
+	To          string   `json:"to"`
+	Amount      *big.Int `json:"amount"`
+	FromChainID *big.Int `json:"fromChainID"`
+	ToChainID   *big.Int `json:"toChainID"`
+}
+
 // VerifyArgs struct
 type VerifyArgs struct {
 	SwapType      SwapType `json:"swaptype,omitempty"`
@@ -197,6 +218,7 @@ type BuildTxArgs struct {
 	Memo        string         `json:"memo,omitempty"`
 	Input       *hexutil.Bytes `json:"input,omitempty"`
 	Extra       *AllExtras     `json:"extra,omitempty"`
+	InitTime    int64          `json:"initTime,omitempty"` // milliseconds, when the swap is registered
 }
 
 // AllExtras struct
@@ -216,6 +238,8 @@ type EthExtraArgs struct {
 	GasFeeCap *big.Int `json:"gasFeeCap,omitempty"`
 	Nonce     *uint64  `json:"nonce,omitempty"`
 	Deadline  int64    `json:"deadline,omitempty"`
+
+	AccessList *types.AccessList `json:"accessList,omitempty"`
 }
 
 // GetReplaceNum get rplace swap count
@@ -235,6 +259,14 @@ func (args *BuildTxArgs) GetExtraArgs() *BuildTxArgs {
 	}
 }
 
+// GetTxGasLimit get tx gas limit (eth like chain)
+func (args *BuildTxArgs) GetTxGasLimit() uint64 {
+	if args.Extra != nil && args.Extra.EthExtra != nil && args.Extra.EthExtra.Gas != nil {
+		return *args.Extra.EthExtra.Gas
+	}
+	return 0
+}
+
 // GetTxNonce get tx nonce
 func (args *BuildTxArgs) GetTxNonce() uint64 {
 	if args.Extra != nil {
@@ -66,6 +66,13 @@ type FeeHistoryResult struct {
 	GasUsedRatio []float64        `json:"gasUsedRatio"`
 }
 
+// AccessListResult eth_createAccessList result
+type AccessListResult struct {
+	AccessList *AccessList    `json:"accessList"`
+	GasUsed    hexutil.Uint64 `json:"gasUsed"`
+	Error      string         `json:"error,omitempty"`
+}
+
 // GetAccountNonce convert
 func (tx *RPCTransaction) GetAccountNonce() uint64 {
 	if tx == nil || tx.AccountNonce == "" {
@@ -83,6 +90,11 @@ type RPCLog struct {
 	Topics  []common.Hash   `json:"topics"`
 	Data    *hexutil.Bytes  `json:"data"`
 	Removed *bool           `json:"removed"`
+
+	// position of the log in chain
+	TxHash      *common.Hash  `json:"transactionHash,omitempty"`
+	BlockNumber *hexutil.Big  `json:"blockNumber,omitempty"`
+	LogIndex    *hexutil.Uint `json:"logIndex,omitempty"`
 }
 
 // RPCTxReceipt struct
@@ -70,6 +70,41 @@ func newTransaction(nonce uint64, to *common.Address, amount *big.Int, gasLimit
 	return &Transaction{data: d}
 }
 
+// NewAccessListTx new access list tx for EIP-2930
+func NewAccessListTx(chainID *big.Int, nonce uint64, to *common.Address, amount *big.Int,
+	gasLimit uint64, gasPrice *big.Int, data []byte, accessList AccessList) *Transaction {
+	if len(data) > 0 {
+		data = common.CopyBytes(data)
+	}
+	tx := &AccessListTx{
+		ChainID:    new(big.Int),
+		Nonce:      nonce,
+		GasPrice:   new(big.Int),
+		Gas:        gasLimit,
+		To:         to,
+		Value:      new(big.Int),
+		Data:       data,
+		AccessList: make(AccessList, len(accessList)),
+		V:          new(big.Int),
+		R:          new(big.Int),
+		S:          new(big.Int),
+	}
+	if chainID != nil {
+		tx.ChainID.Set(chainID)
+	}
+	if gasPrice != nil {
+		tx.GasPrice.Set(gasPrice)
+	}
+	if amount != nil {
+		tx.Value.Set(amount)
+	}
+	if len(accessList) > 0 {
+		copy(tx.AccessList, accessList)
+	}
+
+	return &Transaction{data: *tx.getTxData()}
+}
+
 // NewDynamicFeeTx new dynamic fee tx for EIP-1559
 func NewDynamicFeeTx(chainID *big.Int, nonce uint64, to *common.Address, amount *big.Int,
 	gasLimit uint64, gasTipCap, gasFeeCap *big.Int, data []byte, accessList AccessList) *Transaction {
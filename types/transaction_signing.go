@@ -32,6 +32,8 @@ func MakeSigner(signType string, chainID *big.Int) Signer {
 	switch signType {
 	case "London":
 		signer = NewLondonSigner(chainID)
+	case "Berlin":
+		signer = NewEIP2930Signer(chainID)
 	default:
 		signer = NewEIP155Signer(chainID)
 	}
@@ -1,9 +1,11 @@
 package types
 
 import (
+	"math/big"
 	"testing"
 
 	"github.com/anyswap/CrossChain-Router/v3/common"
+	"github.com/anyswap/CrossChain-Router/v3/tools/crypto"
 )
 
 type txHashTest struct {
@@ -36,3 +38,40 @@ func TestTxHash(t *testing.T) {
 		}
 	}
 }
+
+func TestAccessListTx(t *testing.T) {
+	key, _ := crypto.GenerateKey()
+	from := crypto.PubkeyToAddress(key.PublicKey)
+	to := common.HexToAddress("0x9873d61e6bf850d0b0c2f3c6e075980683f2d9fe")
+	accessList := AccessList{{
+		Address:     to,
+		StorageKeys: []common.Hash{common.HexToHash("0x01")},
+	}}
+	signer := MakeSigner("Berlin", big.NewInt(1))
+	rawTx := NewAccessListTx(big.NewInt(1), 1, &to, big.NewInt(100), 60000, big.NewInt(1e9), nil, accessList)
+	signedTx, err := SignTx(rawTx, signer, key)
+	if err != nil {
+		t.Fatalf("sign access list tx failed: %v", err)
+	}
+	data, err := signedTx.MarshalBinary()
+	if err != nil {
+		t.Fatalf("marshal access list tx failed: %v", err)
+	}
+	tx := new(Transaction)
+	if err = tx.UnmarshalBinary(data); err != nil {
+		t.Fatalf("unmarshal access list tx failed: %v", err)
+	}
+	if tx.Type() != AccessListTxType {
+		t.Errorf("tx type mismatch, have %v want %v", tx.Type(), AccessListTxType)
+	}
+	if tx.Hash() != signedTx.Hash() {
+		t.Errorf("tx hash mismatch, have %v want %v", tx.Hash().Hex(), signedTx.Hash().Hex())
+	}
+	if len(tx.AccessList()) != 1 || tx.AccessList()[0].Address != to {
+		t.Errorf("access list mismatch, have %v", tx.AccessList())
+	}
+	sender, err := Sender(signer, tx)
+	if err != nil || sender != from {
+		t.Errorf("sender mismatch, have %v want %v, err %v", sender.Hex(), from.Hex(), err)
+	}
+}
@@ -34,24 +34,44 @@ var (
 	maxAcceptRoutines2 = int64(10)
 	curAcceptRoutines2 = int64(0)
 
+	// for high value mpc
+	acceptInfoCh3      = make(chan *mpc.SignInfoData, 10)
+	maxAcceptRoutines3 = int64(10)
+	curAcceptRoutines3 = int64(0)
+
+	acceptAgreeCount    uint64
+	acceptDisagreeCount uint64
+
 	// those errors will be ignored in accepting
 	errIdentifierMismatch = errors.New("cross chain bridge identifier mismatch")
 	errInitiatorMismatch  = errors.New("initiator mismatch")
 	errWrongMsgContext    = errors.New("wrong msg context")
+
+	errMPCValueTierMismatch = errors.New("high value swap is not signed by high value mpc")
 )
 
-func getAcceptInfoCh(isFastMPC bool) chan *mpc.SignInfoData {
-	if isFastMPC {
+func getAcceptInfoCh(mpcConfig *mpc.Config) chan *mpc.SignInfoData {
+	switch {
+	case mpcConfig.IsFastMPC:
 		return acceptInfoCh2
+	case mpcConfig.IsHighValueMPC:
+		return acceptInfoCh3
+	default:
+		return acceptInfoCh
 	}
-	return acceptInfoCh
 }
 
-func getAcceptRoutines(isFastMPC bool) (cur, max int64) {
-	if isFastMPC {
-		return curAcceptRoutines2, maxAcceptRoutines2
+// return the address of routine counter, so that the consumer and
+// the processing routines of the same mpc share one counter
+func getAcceptRoutines(mpcConfig *mpc.Config) (cur *int64, max int64) {
+	switch {
+	case mpcConfig.IsFastMPC:
+		return &curAcceptRoutines2, maxAcceptRoutines2
+	case mpcConfig.IsHighValueMPC:
+		return &curAcceptRoutines3, maxAcceptRoutines3
+	default:
+		return &curAcceptRoutines, maxAcceptRoutines
 	}
-	return curAcceptRoutines, maxAcceptRoutines
 }
 
 // StartAcceptSignJob accept job
@@ -59,6 +79,9 @@ func StartAcceptSignJob() {
 	logWorker("accept", "start accept sign job")
 
 	openLeveldb()
+	if policy := params.GetOraclePolicy(); policy != nil && policy.HasDailyCap() && lvldbHandle == nil {
+		logWorkerWarn("accept", "oracle policy daily caps need local database, swaps with daily caps will be disagreed")
+	}
 
 	if mpcConfig := mpc.GetMPCConfig(false); mpcConfig != nil {
 		go startAcceptProducer(mpcConfig)
@@ -73,6 +96,13 @@ func StartAcceptSignJob() {
 		utils.TopWaitGroup.Add(1)
 		go startAcceptConsumer(mpcConfig)
 	}
+
+	if mpcConfig := mpc.GetHighValueMPCConfig(); mpcConfig != nil {
+		go startAcceptProducer(mpcConfig)
+
+		utils.TopWaitGroup.Add(1)
+		go startAcceptConsumer(mpcConfig)
+	}
 }
 
 func startAcceptProducer(mpcConfig *mpc.Config) {
@@ -111,7 +141,7 @@ func startAcceptProducer(mpcConfig *mpc.Config) {
 				continue
 			}
 			logWorker("accept", "dispatch accept sign info", "keyID", keyID)
-			getAcceptInfoCh(mpcConfig.IsFastMPC) <- info // produce
+			getAcceptInfoCh(mpcConfig) <- info // produce
 		}
 		time.Sleep(waitInterval)
 	}
@@ -123,23 +153,23 @@ func startAcceptConsumer(mpcConfig *mpc.Config) {
 		utils.TopWaitGroup.Done()
 	}()
 
-	cur, max := getAcceptRoutines(mpcConfig.IsFastMPC)
+	cur, max := getAcceptRoutines(mpcConfig)
 
 	for {
 		select {
 		case <-utils.CleanupChan:
 			logWorker("accept", "stop accept sign job")
 			return
-		case info := <-acceptInfoCh: // consume
+		case info := <-getAcceptInfoCh(mpcConfig): // consume from the channel of this mpc
 			// loop and check, break if free worker exist
 			for {
-				if atomic.LoadInt64(&cur) < max {
+				if atomic.LoadInt64(cur) < max {
 					break
 				}
 				time.Sleep(1 * time.Second)
 			}
 
-			atomic.AddInt64(&cur, 1)
+			atomic.AddInt64(cur, 1)
 			go processAcceptInfo(mpcConfig, info)
 		}
 	}
@@ -158,7 +188,8 @@ func checkAndUpdateCachedAcceptInfoMap(keyID string) (ok bool) {
 }
 
 func processAcceptInfo(mpcConfig *mpc.Config, info *mpc.SignInfoData) {
-	defer atomic.AddInt64(&curAcceptRoutines, -1)
+	cur, _ := getAcceptRoutines(mpcConfig)
+	defer atomic.AddInt64(cur, -1) // release the counter of this mpc
 
 	keyID := info.Key
 	if !checkAndUpdateCachedAcceptInfoMap(keyID) {
@@ -170,8 +201,9 @@ func processAcceptInfo(mpcConfig *mpc.Config, info *mpc.SignInfoData) {
 			cachedAcceptInfos.Remove(keyID)
 		}
 	}()
+	defer releasePolicyUsages(keyID) // if not settled
 
-	args, err := verifySignInfo(mpcConfig, info)
+	args, verified, err := verifySignInfo(mpcConfig, info)
 
 	ctx := []interface{}{
 		"keyID", keyID,
@@ -220,12 +252,15 @@ func processAcceptInfo(mpcConfig *mpc.Config, info *mpc.SignInfoData) {
 	}
 
 	var aggreeMsgContext []string
+	var disgreeReason, disagreeCode string
 	agreeResult := acceptAgree
 	if err != nil {
+		disagreeCode = getDisagreeReasonCode(err)
+		ctx = append(ctx, "disagreeCode", disagreeCode)
 		logWorkerError("accept", "DISAGREE sign", err, ctx...)
 		agreeResult = acceptDisagree
 
-		disgreeReason := err.Error()
+		disgreeReason = err.Error()
 		if len(disgreeReason) > 1000 {
 			disgreeReason = disgreeReason[:1000]
 		}
@@ -235,40 +270,75 @@ func processAcceptInfo(mpcConfig *mpc.Config, info *mpc.SignInfoData) {
 	ctx = append(ctx, "result", agreeResult)
 
 	res, err := mpcConfig.DoAcceptSign(keyID, agreeResult, info.MsgHash, aggreeMsgContext)
+	if errp := settlePolicyUsages(keyID, agreeResult, err); errp != nil {
+		logWorkerError("accept", "record policy daily usages failed", errp, ctx...)
+	}
 	if err != nil {
 		ctx = append(ctx, "rpcResult", res)
 		logWorkerError("accept", "accept sign job failed", err, ctx...)
 	} else {
 		logWorker("accept", "accept sign job finish", ctx...)
 		isProcessed = true
+		if len(verified) == 0 {
+			verified = getMsgContextArgs(info.MsgContext)
+		}
+		if agreeResult == acceptAgree {
+			atomic.AddUint64(&acceptAgreeCount, 1)
+		} else {
+			atomic.AddUint64(&acceptDisagreeCount, 1)
+			addDisagreeReports(keyID, disagreeCode, disgreeReason, verified)
+		}
+		addAcceptLedgerRecord(mpcConfig, info, agreeResult, disagreeCode, disgreeReason, verified)
 	}
 }
 
-func verifySignInfo(mpcConfig *mpc.Config, signInfo *mpc.SignInfoData) (*tokens.BuildTxArgs, error) {
+// getMsgContextArgs get swap args in msg context (not verified)
+func getMsgContextArgs(msgContext []string) []*tokens.BuildTxArgs {
+	argsList := make([]*tokens.BuildTxArgs, 0, len(msgContext))
+	for _, ctx := range msgContext {
+		var args tokens.BuildTxArgs
+		if err := json.Unmarshal([]byte(ctx), &args); err != nil ||
+			args.FromChainID == nil || args.ToChainID == nil {
+			continue
+		}
+		argsList = append(argsList, &args)
+	}
+	return argsList
+}
+
+// verifySignInfo verify sign info, return args in msg context and the verified args
+func verifySignInfo(mpcConfig *mpc.Config, signInfo *mpc.SignInfoData) (*tokens.BuildTxArgs, []*tokens.BuildTxArgs, error) {
 	msgHash := signInfo.MsgHash
 	msgContext := signInfo.MsgContext
 	var args tokens.BuildTxArgs
 	err := json.Unmarshal([]byte(msgContext[0]), &args)
 	if err != nil {
-		return nil, errWrongMsgContext
+		return nil, nil, errWrongMsgContext
 	}
 	switch args.Identifier {
 	case params.GetIdentifier():
 	default:
-		return nil, errIdentifierMismatch
+		return nil, nil, errIdentifierMismatch
 	}
 	if !mpcConfig.IsMPCInitiator(signInfo.Account) {
-		return nil, errInitiatorMismatch
+		return nil, nil, errInitiatorMismatch
 	}
 	logWorker("accept", "verifySignInfo", "keyID", signInfo.Key, "msgHash", msgHash, "msgContext", msgContext)
+	if len(msgHash) > 1 {
+		verified, err := verifyBatchSignInfo(mpcConfig, signInfo.Key, msgHash, msgContext)
+		return &args, verified, err
+	}
 	if lvldbHandle != nil && args.GetTxNonce() > 0 { // only for eth like chain
 		err = CheckAcceptRecord(&args)
 		if err != nil {
-			return &args, err
+			return &args, nil, err
 		}
 	}
-	err = rebuildAndVerifyMsgHash(signInfo.Key, msgHash, &args)
-	return &args, err
+	verified, err := rebuildAndVerifyMsgHash(mpcConfig, signInfo.Key, msgHash, &args)
+	if err != nil {
+		return &args, nil, err
+	}
+	return &args, []*tokens.BuildTxArgs{verified}, nil
 }
 
 func getBridges(fromChainID, toChainID string) (srcBridge, dstBridge tokens.IBridge, err error) {
@@ -280,13 +350,43 @@ func getBridges(fromChainID, toChainID string) (srcBridge, dstBridge tokens.IBri
 	return
 }
 
-func rebuildAndVerifyMsgHash(keyID string, msgHash []string, args *tokens.BuildTxArgs) (err error) {
+// rebuiltSwapTx swap tx rebuilt by oracle
+type rebuiltSwapTx struct {
+	bridge tokens.IBridge
+	args   *tokens.BuildTxArgs
+	rawTx  interface{}
+	ctx    []interface{}
+}
+
+func rebuildAndVerifyMsgHash(mpcConfig *mpc.Config, keyID string, msgHash []string, args *tokens.BuildTxArgs) (verified *tokens.BuildTxArgs, err error) {
+	if args.SwapType == tokens.NonceGapFillType {
+		return args, verifyNonceGapFill(keyID, msgHash, args)
+	}
+	rebuilt, err := rebuildSwapTxAndVerifyMsgHash(keyID, msgHash, args)
+	if err != nil {
+		return nil, err
+	}
+	err = checkMPCValueTier(mpcConfig, rebuilt.args)
+	if err != nil {
+		return nil, err
+	}
+	err = checkAcceptPolicy(keyID, rebuilt.args)
+	if err != nil {
+		return nil, err
+	}
+	if lvldbHandle != nil && args.Extra != nil && args.Extra.EthExtra != nil { // only for eth like chain
+		go saveAcceptRecord(rebuilt.bridge, keyID, rebuilt.args, rebuilt.rawTx, rebuilt.ctx)
+	}
+	return rebuilt.args, nil
+}
+
+func rebuildSwapTxAndVerifyMsgHash(keyID string, msgHash []string, args *tokens.BuildTxArgs) (*rebuiltSwapTx, error) {
 	if !args.SwapType.IsValidType() {
-		return fmt.Errorf("unknown router swap type %d", args.SwapType)
+		return nil, fmt.Errorf("%w %d", errUnknownSwapType, args.SwapType)
 	}
 	srcBridge, dstBridge, err := getBridges(args.FromChainID.String(), args.ToChainID.String())
 	if err != nil {
-		return err
+		return nil, err
 	}
 
 	ctx := []interface{}{
@@ -310,13 +410,13 @@ func rebuildAndVerifyMsgHash(keyID string, msgHash []string, args *tokens.BuildT
 	swapInfo, err := srcBridge.VerifyTransaction(txid, verifyArgs)
 	if err != nil {
 		logWorkerError("accept", "verifySignInfo failed", err, ctx...)
-		return err
+		return nil, err
 	}
 	if !strings.EqualFold(args.Bind, swapInfo.Bind) {
-		return fmt.Errorf("bind mismatch: '%v' != '%v'", args.Bind, swapInfo.Bind)
+		return nil, fmt.Errorf("%w: '%v' != '%v'", errBindMismatch, args.Bind, swapInfo.Bind)
 	}
 	if args.ToChainID.Cmp(swapInfo.ToChainID) != 0 {
-		return fmt.Errorf("toChainID mismatch: '%v' != '%v'", args.ToChainID, swapInfo.ToChainID)
+		return nil, fmt.Errorf("%w: '%v' != '%v'", tokens.ErrToChainIDMismatch, args.ToChainID, swapInfo.ToChainID)
 	}
 
 	buildTxArgs := &tokens.BuildTxArgs{
@@ -340,29 +440,44 @@ func rebuildAndVerifyMsgHash(keyID string, msgHash []string, args *tokens.BuildT
 	rawTx, err := dstBridge.BuildRawTransaction(buildTxArgs)
 	if err != nil {
 		logWorkerError("accept", "build raw tx failed", err, ctx...)
-		return err
+		return nil, err
 	}
 	err = dstBridge.VerifyMsgHash(rawTx, msgHash)
 	if err != nil {
+		// rebuilt with different swap value, eg. decimals or fee config mismatch
+		if args.SwapValue != nil && buildTxArgs.SwapValue != nil &&
+			args.SwapValue.Cmp(buildTxArgs.SwapValue) != 0 {
+			err = fmt.Errorf("%w: '%v' != '%v', %v", errSwapValueMismatch, args.SwapValue, buildTxArgs.SwapValue, err)
+		}
 		logWorkerError("accept", "verify message hash failed", err, ctx...)
-		return err
+		return nil, err
 	}
 	logWorker("accept", "verify message hash success", ctx...)
-	if lvldbHandle != nil && args.GetTxNonce() > 0 { // only for eth like chain
-		go saveAcceptRecord(dstBridge, keyID, buildTxArgs, rawTx, ctx)
+	return &rebuiltSwapTx{
+		bridge: dstBridge,
+		args:   buildTxArgs,
+		rawTx:  rawTx,
+		ctx:    ctx,
+	}, nil
+}
+
+// high value swaps must be signed by high value mpc
+func checkMPCValueTier(mpcConfig *mpc.Config, args *tokens.BuildTxArgs) error {
+	if !mpcConfig.IsHighValueMPC && router.IsHighValueMPCSwap(args) {
+		return errMPCValueTierMismatch
 	}
 	return nil
 }
 
 func saveAcceptRecord(bridge tokens.IBridge, keyID string, args *tokens.BuildTxArgs, rawTx interface{}, ctx []interface{}) {
 	impl, ok := bridge.(interface {
-		GetSignedTxHashOfKeyID(sender, keyID string, rawTx interface{}) (txHash string, err error)
+		GetSignedTxHashOfKeyID(args *tokens.BuildTxArgs, keyID string, rawTx interface{}) (txHash string, err error)
 	})
 	if !ok {
 		return
 	}
 
-	swapTx, err := impl.GetSignedTxHashOfKeyID(args.From, keyID, rawTx)
+	swapTx, err := impl.GetSignedTxHashOfKeyID(args, keyID, rawTx)
 	if err != nil {
 		logWorkerError("accept", "get signed tx hash failed", err, ctx...)
 		return
@@ -375,4 +490,5 @@ func saveAcceptRecord(bridge tokens.IBridge, keyID string, args *tokens.BuildTxA
 		return
 	}
 	logWorker("accept", "save accept record to db success", ctx...)
+	setAcceptLedgerTxHash(keyID, args, swapTx)
 }
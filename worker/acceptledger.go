@@ -0,0 +1,445 @@
+package worker
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/anyswap/CrossChain-Router/v3/cmd/utils"
+	"github.com/anyswap/CrossChain-Router/v3/mongodb"
+	"github.com/anyswap/CrossChain-Router/v3/mpc"
+	"github.com/anyswap/CrossChain-Router/v3/params"
+	"github.com/anyswap/CrossChain-Router/v3/tokens"
+)
+
+const (
+	ledgerRecordPrefix = "ledger-record:"
+	ledgerTimePrefix   = "ledger-time:"
+	ledgerSwapPrefix   = "ledger-swap:"
+	ledgerFlagPrefix   = "ledger-flag:"
+	ledgerNoncePrefix  = "ledger-nonce:"
+
+	defaultLedgerRetentionDays = 90
+	maxLedgerQueryLimit        = 1000
+
+	restIntervalInPruneLedgerJob = 1 * time.Hour
+)
+
+var (
+	acceptLedgerLock sync.Mutex
+
+	errNoAcceptLedger = errors.New("accept ledger is not available")
+)
+
+// AcceptLedgerRecord accept sign decision of this oracle
+type AcceptLedgerRecord struct {
+	KeyID        string              `json:"keyID"`
+	Result       string              `json:"result"`
+	FastMPC      bool                `json:"fastMPC,omitempty"`
+	HighValueMPC bool                `json:"highValueMPC,omitempty"`
+	MsgHash      []string            `json:"msgHash"`
+	Swaps        []*AcceptLedgerSwap `json:"swaps"`
+	Reason       string              `json:"reason,omitempty"`
+	Timestamp    int64               `json:"timestamp"`
+
+	ReasonCode string `json:"reasonCode,omitempty"`
+}
+
+// AcceptLedgerSwap swap of accept sign decision
+type AcceptLedgerSwap struct {
+	SwapKey   string `json:"swapKey"` // fromChainID:txid:logindex
+	SwapType  string `json:"swapType"`
+	ToChainID string `json:"toChainID"`
+	TokenID   string `json:"tokenID,omitempty"`
+	Value     string `json:"value,omitempty"` // verified origin value (only in agreed records)
+	Nonce     uint64 `json:"nonce,omitempty"`
+	TxHash    string `json:"txHash,omitempty"` // signed tx hash
+
+	MPC string `json:"mpc,omitempty"` // set if the swap is allocated with nonce of mpc
+}
+
+// AcceptLedgerFlag swap tx of server which is not agreed by this oracle
+type AcceptLedgerFlag struct {
+	SwapKey   string `json:"swapKey"` // fromChainID:txid:logindex
+	ToChainID string `json:"toChainID"`
+	SwapTx    string `json:"swaptx"`
+	SwapNonce uint64 `json:"swapnonce"`
+	Status    uint32 `json:"status"`
+	Timestamp int64  `json:"timestamp"` // update time in server
+	FlagTime  int64  `json:"flagTime"`
+}
+
+func getLedgerRecordKey(keyID string) []byte {
+	return []byte(ledgerRecordPrefix + keyID)
+}
+
+func getLedgerTimeKey(timestamp int64, keyID string) []byte {
+	return []byte(fmt.Sprintf("%s%016x:%s", ledgerTimePrefix, timestamp, keyID))
+}
+
+func getLedgerSwapKey(swapKey, keyID string) []byte {
+	return []byte(ledgerSwapPrefix + swapKey + ":" + keyID)
+}
+
+func getLedgerFlagKey(toChainID, swapTx string) []byte {
+	return []byte(ledgerFlagPrefix + toChainID + ":" + strings.ToLower(swapTx))
+}
+
+func getLedgerNonceKey(chainID, mpc string, nonce uint64) []byte {
+	return []byte(fmt.Sprintf("%s%s:%s:%d", ledgerNoncePrefix, chainID, strings.ToLower(mpc), nonce))
+}
+
+func newAcceptLedgerSwap(args *tokens.BuildTxArgs, isVerified bool) *AcceptLedgerSwap {
+	swap := &AcceptLedgerSwap{
+		SwapKey:   mongodb.GetRouterSwapKey(args.FromChainID.String(), args.SwapID, args.LogIndex),
+		SwapType:  args.SwapType.String(),
+		ToChainID: args.ToChainID.String(),
+		TokenID:   args.GetTokenID(),
+		Nonce:     args.GetTxNonce(),
+	}
+	if isVerified && args.OriginValue != nil {
+		swap.Value = args.OriginValue.String()
+	}
+	if args.SwapType != tokens.NonceGapFillType &&
+		args.Extra != nil && args.Extra.EthExtra != nil && args.Extra.EthExtra.Nonce != nil {
+		swap.MPC = strings.ToLower(args.From)
+	}
+	return swap
+}
+
+func getAcceptLedgerRecord(keyID string) (*AcceptLedgerRecord, error) {
+	data, err := lvldbHandle.Get(getLedgerRecordKey(keyID))
+	if err != nil {
+		return nil, err
+	}
+	var record AcceptLedgerRecord
+	if err = json.Unmarshal(data, &record); err != nil {
+		return nil, err
+	}
+	return &record, nil
+}
+
+// putAcceptLedgerRecord put record and its indexes (must hold lock)
+func putAcceptLedgerRecord(record *AcceptLedgerRecord) error {
+	data, err := json.Marshal(record)
+	if err != nil {
+		return err
+	}
+	batch := lvldbHandle.NewBatch()
+	_ = batch.Put(getLedgerRecordKey(record.KeyID), data)
+	_ = batch.Put(getLedgerTimeKey(record.Timestamp, record.KeyID), []byte(record.KeyID))
+	for _, swap := range record.Swaps {
+		_ = batch.Put(getLedgerSwapKey(swap.SwapKey, record.KeyID), []byte(record.KeyID))
+		if record.Result == acceptAgree && swap.MPC != "" {
+			_ = batch.Put(getLedgerNonceKey(swap.ToChainID, swap.MPC, swap.Nonce), []byte(swap.SwapKey))
+		}
+	}
+	return batch.Write()
+}
+
+// getAcceptLedgerNonceSwap get key of swap agreed with nonce of mpc
+func getAcceptLedgerNonceSwap(chainID, mpc string, nonce uint64) string {
+	if lvldbHandle == nil {
+		return ""
+	}
+	data, err := lvldbHandle.Get(getLedgerNonceKey(chainID, mpc, nonce))
+	if err != nil {
+		return ""
+	}
+	return string(data)
+}
+
+// addAcceptLedgerRecord add decision of accept sign to ledger
+func addAcceptLedgerRecord(mpcConfig *mpc.Config, info *mpc.SignInfoData, result, reasonCode, reason string, argsList []*tokens.BuildTxArgs) {
+	if lvldbHandle == nil {
+		return
+	}
+	record := &AcceptLedgerRecord{
+		KeyID:        info.Key,
+		Result:       result,
+		FastMPC:      mpcConfig.IsFastMPC,
+		HighValueMPC: mpcConfig.IsHighValueMPC,
+		MsgHash:      info.MsgHash,
+		Swaps:        make([]*AcceptLedgerSwap, 0, len(argsList)),
+		Reason:       reason,
+		Timestamp:    now(),
+
+		ReasonCode: reasonCode,
+	}
+	for _, args := range argsList {
+		// args of disagreed swaps may be not verified
+		record.Swaps = append(record.Swaps, newAcceptLedgerSwap(args, result == acceptAgree))
+	}
+
+	acceptLedgerLock.Lock()
+	defer acceptLedgerLock.Unlock()
+
+	// keep tx hashes saved before (eg. saved by saveAcceptRecord)
+	if old, err := getAcceptLedgerRecord(info.Key); err == nil {
+		for _, oldSwap := range old.Swaps {
+			if oldSwap.TxHash == "" {
+				continue
+			}
+			for _, swap := range record.Swaps {
+				if swap.SwapKey == oldSwap.SwapKey {
+					swap.TxHash = oldSwap.TxHash
+				}
+			}
+		}
+	}
+
+	if err := putAcceptLedgerRecord(record); err != nil {
+		logWorkerError("ledger", "add accept ledger record failed", err, "keyID", info.Key)
+	}
+}
+
+// setAcceptLedgerTxHash set signed tx hash of swap in ledger record
+func setAcceptLedgerTxHash(keyID string, args *tokens.BuildTxArgs, txHash string) {
+	if lvldbHandle == nil {
+		return
+	}
+	acceptLedgerLock.Lock()
+	defer acceptLedgerLock.Unlock()
+
+	swap := newAcceptLedgerSwap(args, true)
+	swap.TxHash = txHash
+	record, err := getAcceptLedgerRecord(keyID)
+	if err != nil { // record is not added yet
+		record = &AcceptLedgerRecord{
+			KeyID:     keyID,
+			Result:    acceptAgree,
+			Swaps:     []*AcceptLedgerSwap{swap},
+			Timestamp: now(),
+		}
+	} else {
+		found := false
+		for i, old := range record.Swaps {
+			if old.SwapKey == swap.SwapKey {
+				record.Swaps[i] = swap
+				found = true
+				break
+			}
+		}
+		if !found {
+			record.Swaps = append(record.Swaps, swap)
+		}
+	}
+	if err = putAcceptLedgerRecord(record); err != nil {
+		logWorkerError("ledger", "set accept ledger tx hash failed", err, "keyID", keyID, "txHash", txHash)
+	}
+}
+
+// GetAcceptLedgerRecord get accept ledger record by keyID
+func GetAcceptLedgerRecord(keyID string) (*AcceptLedgerRecord, error) {
+	if lvldbHandle == nil {
+		return nil, errNoAcceptLedger
+	}
+	return getAcceptLedgerRecord(keyID)
+}
+
+func getLedgerLimit(limit int) int {
+	if limit <= 0 || limit > maxLedgerQueryLimit {
+		return maxLedgerQueryLimit
+	}
+	return limit
+}
+
+// GetAcceptLedgerRecords get accept ledger records in time range [start, end]
+func GetAcceptLedgerRecords(start, end int64, limit int) ([]*AcceptLedgerRecord, error) {
+	if lvldbHandle == nil {
+		return nil, errNoAcceptLedger
+	}
+	if end <= 0 {
+		end = now()
+	}
+	limit = getLedgerLimit(limit)
+	result := make([]*AcceptLedgerRecord, 0)
+	iter := lvldbHandle.NewIterator([]byte(ledgerTimePrefix), []byte(fmt.Sprintf("%016x", start)))
+	defer iter.Release()
+	for iter.Next() && len(result) < limit {
+		var timestamp int64
+		if _, err := fmt.Sscanf(string(iter.Key()[len(ledgerTimePrefix):]), "%016x", &timestamp); err != nil {
+			continue
+		}
+		if timestamp > end {
+			break
+		}
+		record, err := getAcceptLedgerRecord(string(iter.Value()))
+		if err != nil || record.Timestamp != timestamp {
+			continue // stale time index
+		}
+		result = append(result, record)
+	}
+	return result, nil
+}
+
+// GetAcceptLedgerRecordsOfSwap get accept ledger records of swap
+func GetAcceptLedgerRecordsOfSwap(fromChainID, txid string, logIndex int) ([]*AcceptLedgerRecord, error) {
+	if lvldbHandle == nil {
+		return nil, errNoAcceptLedger
+	}
+	swapKey := mongodb.GetRouterSwapKey(fromChainID, txid, logIndex)
+	result := make([]*AcceptLedgerRecord, 0)
+	iter := lvldbHandle.NewIterator(getLedgerSwapKey(swapKey, ""), nil)
+	defer iter.Release()
+	for iter.Next() {
+		record, err := getAcceptLedgerRecord(string(iter.Value()))
+		if err != nil {
+			continue
+		}
+		result = append(result, record)
+	}
+	return result, nil
+}
+
+// hasAcceptLedgerAgree check if this oracle has agreed to sign the swap
+func hasAcceptLedgerAgree(swapKey string) bool {
+	iter := lvldbHandle.NewIterator(getLedgerSwapKey(swapKey, ""), nil)
+	defer iter.Release()
+	for iter.Next() {
+		record, err := getAcceptLedgerRecord(string(iter.Value()))
+		if err == nil && record.Result == acceptAgree {
+			return true
+		}
+	}
+	return false
+}
+
+// GetAcceptLedgerFlags get swap txs flagged by reconciliation
+func GetAcceptLedgerFlags(limit int) ([]*AcceptLedgerFlag, error) {
+	if lvldbHandle == nil {
+		return nil, errNoAcceptLedger
+	}
+	limit = getLedgerLimit(limit)
+	result := make([]*AcceptLedgerFlag, 0)
+	iter := lvldbHandle.NewIterator([]byte(ledgerFlagPrefix), nil)
+	defer iter.Release()
+	for iter.Next() && len(result) < limit {
+		var flag AcceptLedgerFlag
+		if err := json.Unmarshal(iter.Value(), &flag); err != nil {
+			continue
+		}
+		result = append(result, &flag)
+	}
+	return result, nil
+}
+
+func addAcceptLedgerFlag(flag *AcceptLedgerFlag) error {
+	key := getLedgerFlagKey(flag.ToChainID, flag.SwapTx)
+	if exist, err := lvldbHandle.Has(key); err != nil || exist {
+		return err
+	}
+	data, err := json.Marshal(flag)
+	if err != nil {
+		return err
+	}
+	return lvldbHandle.Put(key, data)
+}
+
+func getLedgerRetentionDays() uint64 {
+	if oracleCfg := params.GetRouterOracleConfig(); oracleCfg != nil && oracleCfg.LedgerRetentionDays > 0 {
+		return oracleCfg.LedgerRetentionDays
+	}
+	return defaultLedgerRetentionDays
+}
+
+// StartAcceptLedgerJob start prune and reconcile jobs of accept ledger (oracle only)
+func StartAcceptLedgerJob() {
+	if lvldbHandle == nil || params.GetRouterOracleConfig() == nil {
+		return
+	}
+	logWorker("ledger", "start accept ledger job", "retentionDays", getLedgerRetentionDays())
+	go startPruneAcceptLedgerJob()
+	go startReconcileJob()
+}
+
+func startPruneAcceptLedgerJob() {
+	for {
+		if utils.IsCleanuping() {
+			return
+		}
+		pruneAcceptLedger()
+		restInJob(restIntervalInPruneLedgerJob)
+	}
+}
+
+// pruneAcceptLedger delete ledger records and policy usages out of retention
+func pruneAcceptLedger() {
+	retention := int64(getLedgerRetentionDays()) * 24 * 3600
+	cutoff := now() - retention
+	cutoffDay := time.Unix(cutoff, 0).UTC().Format("20060102")
+
+	acceptLedgerLock.Lock()
+	defer acceptLedgerLock.Unlock()
+	acceptPolicyLock.Lock()
+	defer acceptPolicyLock.Unlock()
+
+	deleted := 0
+	batch := lvldbHandle.NewBatch()
+
+	iter := lvldbHandle.NewIterator([]byte(ledgerTimePrefix), nil)
+	for iter.Next() {
+		var timestamp int64
+		if _, err := fmt.Sscanf(string(iter.Key()[len(ledgerTimePrefix):]), "%016x", &timestamp); err != nil {
+			continue
+		}
+		if timestamp >= cutoff {
+			break
+		}
+		keyID := string(iter.Value())
+		if record, err := getAcceptLedgerRecord(keyID); err == nil && record.Timestamp < cutoff {
+			_ = batch.Delete(getLedgerRecordKey(keyID))
+			for _, swap := range record.Swaps {
+				_ = batch.Delete(getLedgerSwapKey(swap.SwapKey, keyID))
+				if swap.MPC != "" {
+					_ = batch.Delete(getLedgerNonceKey(swap.ToChainID, swap.MPC, swap.Nonce))
+				}
+			}
+		}
+		_ = batch.Delete(append([]byte{}, iter.Key()...))
+		deleted++
+	}
+	iter.Release()
+
+	iter = lvldbHandle.NewIterator([]byte(ledgerFlagPrefix), nil)
+	for iter.Next() {
+		var flag AcceptLedgerFlag
+		if err := json.Unmarshal(iter.Value(), &flag); err == nil && flag.FlagTime < cutoff {
+			_ = batch.Delete(append([]byte{}, iter.Key()...))
+			deleted++
+		}
+	}
+	iter.Release()
+
+	iter = lvldbHandle.NewIterator([]byte(policyDailyUsagePrefix), nil)
+	for iter.Next() {
+		day := strings.SplitN(string(iter.Key()[len(policyDailyUsagePrefix):]), ":", 2)[0]
+		if day < cutoffDay {
+			_ = batch.Delete(append([]byte{}, iter.Key()...))
+			deleted++
+		}
+	}
+	iter.Release()
+	iter = lvldbHandle.NewIterator([]byte(policyCountedPrefix), nil)
+	for iter.Next() {
+		if string(iter.Value()) < cutoffDay {
+			_ = batch.Delete(append([]byte{}, iter.Key()...))
+			deleted++
+		}
+	}
+	iter.Release()
+	if err := batch.Write(); err != nil {
+		logWorkerError("ledger", "prune accept ledger failed", err)
+		return
+	}
+	if deleted == 0 {
+		return
+	}
+	if err := lvldbHandle.Compact(nil, nil); err != nil {
+		logWorkerError("ledger", "compact accept database failed", err)
+	}
+	logWorker("ledger", "prune accept ledger success", "deleted", deleted, "cutoff", cutoff)
+}
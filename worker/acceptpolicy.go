@@ -0,0 +1,325 @@
+package worker
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+	"sync"
+	"time"
+
+	"github.com/anyswap/CrossChain-Router/v3/leveldb"
+	"github.com/anyswap/CrossChain-Router/v3/params"
+	"github.com/anyswap/CrossChain-Router/v3/router"
+	"github.com/anyswap/CrossChain-Router/v3/tokens"
+)
+
+const (
+	policyDailyUsagePrefix = "policy-daily:"
+	policyCountedPrefix    = "policy-counted:"
+)
+
+var (
+	acceptPolicyLock sync.Mutex
+
+	// usages reserved by signs in accepting, which are recorded in database
+	// after agreed successfully, and released otherwise
+	policyReservations   = make(map[string][]*policyUsage) // key is sign key ID
+	reservedPolicyUsages = make(map[string]*big.Int)       // key is daily usage key
+
+	errPolicyChainPaused     = errors.New("oracle policy: chain is paused")
+	errPolicyReceiverDenied  = errors.New("oracle policy: receiver is denied")
+	errPolicyUnknownDecimals = errors.New("oracle policy: unknown token decimals")
+	errPolicyExceedMaxValue  = errors.New("oracle policy: exceed max value")
+	errPolicyExceedDailyCap  = errors.New("oracle policy: exceed daily cap")
+	errPolicyNoLocalDatabase = errors.New("oracle policy: no local database to count daily cap")
+)
+
+// policyLimit value limit applied to a swap
+type policyLimit struct {
+	key    string
+	config *params.AcceptLimitConfig
+}
+
+// policyUsage value of a swap reserved in daily usages
+type policyUsage struct {
+	day        string
+	countedKey []byte
+	limitKeys  []string
+	value      *big.Int
+}
+
+// checkAcceptPolicy check oracle local policy of swaps to sign (rebuilt args),
+// and reserve their values in daily usages if all passed.
+func checkAcceptPolicy(keyID string, argsList ...*tokens.BuildTxArgs) error {
+	policy := params.GetOraclePolicy()
+	if policy == nil {
+		return nil
+	}
+	values := make([]*big.Int, len(argsList))
+	limits := make([][]*policyLimit, len(argsList))
+	for i, args := range argsList {
+		value, limit, err := checkAcceptPolicyRules(policy, args)
+		if err != nil {
+			return err
+		}
+		values[i] = value
+		limits[i] = limit
+	}
+	return checkAndReserveDailyUsages(keyID, getPolicyDay(now()), argsList, values, limits)
+}
+
+func checkAcceptPolicyRules(policy *params.OraclePolicy, args *tokens.BuildTxArgs) (value *big.Int, limits []*policyLimit, err error) {
+	fromChainID := args.FromChainID.String()
+	toChainID := args.ToChainID.String()
+	if policy.IsChainPaused(fromChainID) || policy.IsChainPaused(toChainID) {
+		return nil, nil, errPolicyChainPaused
+	}
+	if policy.IsReceiverDenied(args.Bind) {
+		return nil, nil, errPolicyReceiverDenied
+	}
+
+	srcBridge := router.GetBridgeByChainID(fromChainID)
+	if srcBridge == nil {
+		return nil, nil, tokens.ErrNoBridgeForChainID
+	}
+
+	if extra := policy.GetExtraConfirmations(fromChainID); extra > 0 {
+		required := srcBridge.GetChainConfig().Confirmations + extra
+		txStatus, errt := srcBridge.GetTransactionStatus(args.SwapID)
+		if errt != nil {
+			return nil, nil, errt
+		}
+		if txStatus == nil || txStatus.Confirmations < required {
+			var have uint64
+			if txStatus != nil {
+				have = txStatus.Confirmations
+			}
+			return nil, nil, fmt.Errorf("%w: oracle policy require %v confirmations, have %v", tokens.ErrTxNotStable, required, have)
+		}
+	}
+
+	if args.ERC20SwapInfo == nil || args.OriginValue == nil {
+		return nil, nil, nil
+	}
+	tokenID := args.ERC20SwapInfo.TokenID
+	if c := policy.GetTokenLimit(tokenID); c != nil {
+		limits = append(limits, &policyLimit{key: "token:" + tokenID, config: c})
+	}
+	if c := policy.GetRouteLimit(tokenID, fromChainID, toChainID); c != nil {
+		limits = append(limits, &policyLimit{key: "route:" + params.GetRouteLimitKey(tokenID, fromChainID, toChainID), config: c})
+	}
+	if len(limits) == 0 {
+		return nil, nil, nil
+	}
+
+	tokenCfg := srcBridge.GetTokenConfig(args.ERC20SwapInfo.Token)
+	if tokenCfg == nil {
+		return nil, nil, errPolicyUnknownDecimals
+	}
+	value = tokens.ConvertTokenValue(args.OriginValue, tokenCfg.Decimals, 18)
+	for _, limit := range limits {
+		if maxValue := limit.config.GetMaxValue(); maxValue != nil && value.Cmp(maxValue) > 0 {
+			return nil, nil, fmt.Errorf("%w of %v: %v > %v", errPolicyExceedMaxValue, limit.key, value, maxValue)
+		}
+	}
+	return value, limits, nil
+}
+
+// daily usages are counted by UTC day
+func getPolicyDay(timestamp int64) string {
+	return time.Unix(timestamp, 0).UTC().Format("20060102")
+}
+
+func getPolicyDailyUsageKey(day, limitKey string) []byte {
+	return []byte(policyDailyUsagePrefix + day + ":" + limitKey)
+}
+
+func getPolicyCountedKey(args *tokens.BuildTxArgs) []byte {
+	return []byte(policyCountedPrefix + getSwapKeyPrefix(args))
+}
+
+func getPolicyDailyUsage(key []byte) (*big.Int, error) {
+	data, err := lvldbHandle.Get(key)
+	if err != nil {
+		if leveldb.IsNotFoundErr(err) {
+			return big.NewInt(0), nil
+		}
+		return nil, err
+	}
+	return new(big.Int).SetBytes(data), nil
+}
+
+func getPolicyDailyUsageWithReserved(day, limitKey string) (*big.Int, error) {
+	usage, err := getPolicyDailyUsage(getPolicyDailyUsageKey(day, limitKey))
+	if err != nil {
+		return nil, err
+	}
+	if reserved := reservedPolicyUsages[string(getPolicyDailyUsageKey(day, limitKey))]; reserved != nil {
+		usage.Add(usage, reserved)
+	}
+	return usage, nil
+}
+
+// checkAndReserveDailyUsages check daily caps (include reserved usages)
+// and reserve values of swaps not counted before
+func checkAndReserveDailyUsages(keyID, day string, argsList []*tokens.BuildTxArgs, values []*big.Int, limits [][]*policyLimit) error {
+	hasDailyCap := false
+	for _, limit := range limits {
+		for _, l := range limit {
+			if l.config.GetDailyCap() != nil {
+				hasDailyCap = true
+			}
+		}
+	}
+	if !hasDailyCap {
+		return nil
+	}
+	if lvldbHandle == nil {
+		return errPolicyNoLocalDatabase
+	}
+
+	acceptPolicyLock.Lock()
+	defer acceptPolicyLock.Unlock()
+
+	releasePolicyUsagesLocked(keyID) // eg. retry of the same sign
+
+	usages := make(map[string]*big.Int)
+	caps := make(map[string]*big.Int)
+	keys := make([]string, 0)
+	reservations := make([]*policyUsage, 0, len(argsList))
+	for i, args := range argsList {
+		countedKey := getPolicyCountedKey(args)
+		counted, err := lvldbHandle.Has(countedKey)
+		if err != nil {
+			return err
+		}
+		if counted { // eg. replace or reswap of the same swap
+			continue
+		}
+		reservation := &policyUsage{day: day, countedKey: countedKey, value: values[i]}
+		for _, limit := range limits[i] {
+			dailyCap := limit.config.GetDailyCap()
+			if dailyCap == nil {
+				continue
+			}
+			usage, exist := usages[limit.key]
+			if !exist {
+				usage, err = getPolicyDailyUsageWithReserved(day, limit.key)
+				if err != nil {
+					return err
+				}
+				usages[limit.key] = usage
+				caps[limit.key] = dailyCap
+				keys = append(keys, limit.key)
+			}
+			usage.Add(usage, values[i])
+			reservation.limitKeys = append(reservation.limitKeys, limit.key)
+		}
+		if len(reservation.limitKeys) > 0 {
+			reservations = append(reservations, reservation)
+		}
+	}
+	for _, key := range keys {
+		if usages[key].Cmp(caps[key]) > 0 {
+			return fmt.Errorf("%w of %v: %v > %v", errPolicyExceedDailyCap, key, usages[key], caps[key])
+		}
+	}
+
+	if len(reservations) == 0 {
+		return nil
+	}
+	for _, reservation := range reservations {
+		for _, limitKey := range reservation.limitKeys {
+			usageKey := string(getPolicyDailyUsageKey(reservation.day, limitKey))
+			reserved := reservedPolicyUsages[usageKey]
+			if reserved == nil {
+				reserved = big.NewInt(0)
+				reservedPolicyUsages[usageKey] = reserved
+			}
+			reserved.Add(reserved, reservation.value)
+		}
+	}
+	policyReservations[keyID] = reservations
+	return nil
+}
+
+// recordPolicyUsages record the reserved usages of sign after agreed successfully
+func recordPolicyUsages(keyID string) error {
+	acceptPolicyLock.Lock()
+	defer acceptPolicyLock.Unlock()
+
+	reservations := policyReservations[keyID]
+	releasePolicyUsagesLocked(keyID)
+	if len(reservations) == 0 || lvldbHandle == nil {
+		return nil
+	}
+
+	usages := make(map[string]*big.Int)
+	keys := make([]string, 0)
+	batch := lvldbHandle.NewBatch()
+	for _, reservation := range reservations {
+		counted, err := lvldbHandle.Has(reservation.countedKey)
+		if err != nil {
+			return err
+		}
+		if counted { // counted by another sign of the same swap
+			continue
+		}
+		for _, limitKey := range reservation.limitKeys {
+			usageKey := string(getPolicyDailyUsageKey(reservation.day, limitKey))
+			usage, exist := usages[usageKey]
+			if !exist {
+				usage, err = getPolicyDailyUsage([]byte(usageKey))
+				if err != nil {
+					return err
+				}
+				usages[usageKey] = usage
+				keys = append(keys, usageKey)
+			}
+			usage.Add(usage, reservation.value)
+		}
+		if err = batch.Put(reservation.countedKey, []byte(reservation.day)); err != nil {
+			return err
+		}
+	}
+	for _, key := range keys {
+		if err := batch.Put([]byte(key), usages[key].Bytes()); err != nil {
+			return err
+		}
+	}
+	return batch.Write()
+}
+
+// settlePolicyUsages record the reserved usages of sign if agreed successfully,
+// otherwise release them
+func settlePolicyUsages(keyID, agreeResult string, acceptErr error) error {
+	if acceptErr != nil || agreeResult != acceptAgree {
+		releasePolicyUsages(keyID)
+		return nil
+	}
+	return recordPolicyUsages(keyID)
+}
+
+// releasePolicyUsages release the reserved usages of sign if not recorded
+func releasePolicyUsages(keyID string) {
+	acceptPolicyLock.Lock()
+	defer acceptPolicyLock.Unlock()
+	releasePolicyUsagesLocked(keyID)
+}
+
+func releasePolicyUsagesLocked(keyID string) {
+	for _, reservation := range policyReservations[keyID] {
+		for _, limitKey := range reservation.limitKeys {
+			usageKey := string(getPolicyDailyUsageKey(reservation.day, limitKey))
+			reserved := reservedPolicyUsages[usageKey]
+			if reserved == nil {
+				continue
+			}
+			reserved.Sub(reserved, reservation.value)
+			if reserved.Sign() <= 0 {
+				delete(reservedPolicyUsages, usageKey)
+			}
+		}
+	}
+	delete(policyReservations, keyID)
+}
@@ -0,0 +1,242 @@
+package worker
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+	"sync"
+	"sync/atomic"
+	"testing"
+
+	"github.com/anyswap/CrossChain-Router/v3/leveldb"
+	"github.com/anyswap/CrossChain-Router/v3/params"
+	"github.com/anyswap/CrossChain-Router/v3/router"
+	"github.com/anyswap/CrossChain-Router/v3/tokens"
+)
+
+const (
+	tPolicyFromChainID = "1"
+	tPolicyTokenID     = "USDC"
+	tPolicyLimitKey    = "token:" + tPolicyTokenID
+)
+
+// token of 6 decimals, max value is 200 and daily cap is 250 (in 18 decimals)
+type testPolicyBridge struct {
+	tokens.IBridge
+}
+
+func (b *testPolicyBridge) GetTokenConfig(tokenAddr string) *tokens.TokenConfig {
+	return &tokens.TokenConfig{Decimals: 6}
+}
+
+func setupTestAcceptPolicy(t *testing.T) {
+	db, err := leveldb.New(t.TempDir(), 16, 16, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	lvldbHandle = db
+	err = params.SetOraclePolicy(&params.OraclePolicy{
+		TokenLimits: map[string]*params.AcceptLimitConfig{
+			tPolicyTokenID: {
+				MaxValue: "200000000000000000000",
+				DailyCap: "250000000000000000000",
+			},
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	router.SetBridge(tPolicyFromChainID, &testPolicyBridge{})
+
+	t.Cleanup(func() {
+		router.SetBridge(tPolicyFromChainID, nil)
+		_ = params.SetOraclePolicy(nil)
+		_ = lvldbHandle.Close()
+		lvldbHandle = nil
+		policyReservations = make(map[string][]*policyUsage)
+		reservedPolicyUsages = make(map[string]*big.Int)
+	})
+}
+
+// value is in unit of the token (6 decimals)
+func newTestPolicyArgs(swapID string, value int64) *tokens.BuildTxArgs {
+	return &tokens.BuildTxArgs{
+		SwapArgs: tokens.SwapArgs{
+			SwapID:      swapID,
+			SwapType:    tokens.ERC20SwapType,
+			FromChainID: big.NewInt(1),
+			ToChainID:   big.NewInt(56),
+			SwapInfo: tokens.SwapInfo{ERC20SwapInfo: &tokens.ERC20SwapInfo{
+				TokenID: tPolicyTokenID,
+			}},
+		},
+		OriginValue: new(big.Int).Mul(big.NewInt(value), big.NewInt(1e6)),
+	}
+}
+
+func getTestPolicyUsage(t *testing.T, day string) string {
+	usage, err := getPolicyDailyUsage(getPolicyDailyUsageKey(day, tPolicyLimitKey))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return new(big.Int).Div(usage, big.NewInt(1e18)).String()
+}
+
+func TestAcceptPolicyMaxValue(t *testing.T) {
+	setupTestAcceptPolicy(t)
+
+	// 200 with 6 decimals is converted to 200 with 18 decimals
+	if err := checkAcceptPolicy("k1", newTestPolicyArgs("s1", 200)); err != nil {
+		t.Fatalf("swap of max value is disagreed: %v", err)
+	}
+	args := newTestPolicyArgs("s2", 200)
+	args.OriginValue.Add(args.OriginValue, big.NewInt(1))
+	if err := checkAcceptPolicy("k2", args); !errors.Is(err, errPolicyExceedMaxValue) {
+		t.Fatalf("swap exceed max value is not disagreed: %v", err)
+	}
+}
+
+func TestAcceptPolicyDailyCapInBatch(t *testing.T) {
+	setupTestAcceptPolicy(t)
+
+	err := checkAcceptPolicy("k1", newTestPolicyArgs("s1", 100), newTestPolicyArgs("s2", 100), newTestPolicyArgs("s3", 100))
+	if !errors.Is(err, errPolicyExceedDailyCap) {
+		t.Fatalf("batch exceed daily cap is not disagreed: %v", err)
+	}
+	if len(policyReservations) != 0 || len(reservedPolicyUsages) != 0 {
+		t.Fatal("disagreed batch reserves usages")
+	}
+	err = checkAcceptPolicy("k2", newTestPolicyArgs("s1", 100), newTestPolicyArgs("s2", 100))
+	if err != nil {
+		t.Fatalf("batch under daily cap is disagreed: %v", err)
+	}
+}
+
+func TestAcceptPolicyConcurrentReservations(t *testing.T) {
+	setupTestAcceptPolicy(t)
+
+	if err := checkAcceptPolicy("k1", newTestPolicyArgs("s1", 100), newTestPolicyArgs("s2", 100)); err != nil {
+		t.Fatal(err)
+	}
+	if err := checkAcceptPolicy("k2", newTestPolicyArgs("s3", 100)); !errors.Is(err, errPolicyExceedDailyCap) {
+		t.Fatalf("reserved usages of other sign are not counted: %v", err)
+	}
+
+	// 50 is left, only one of the concurrent signs can be agreed
+	var wg sync.WaitGroup
+	var passed int64
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			keyID := fmt.Sprintf("c%d", i)
+			if checkAcceptPolicy(keyID, newTestPolicyArgs(keyID, 30)) == nil {
+				atomic.AddInt64(&passed, 1)
+			}
+		}(i)
+	}
+	wg.Wait()
+	if passed != 1 {
+		t.Fatalf("concurrent reservations exceed daily cap, passed %v", passed)
+	}
+}
+
+func TestAcceptPolicyReleaseUnagreed(t *testing.T) {
+	setupTestAcceptPolicy(t)
+	day := getPolicyDay(now())
+
+	if err := checkAcceptPolicy("k1", newTestPolicyArgs("s1", 200)); err != nil {
+		t.Fatal(err)
+	}
+	if err := settlePolicyUsages("k1", acceptDisagree, nil); err != nil {
+		t.Fatal(err)
+	}
+	if err := checkAcceptPolicy("k2", newTestPolicyArgs("s2", 200)); err != nil {
+		t.Fatalf("usages are not released after disagree: %v", err)
+	}
+	if err := settlePolicyUsages("k2", acceptAgree, errors.New("accept sign failed")); err != nil {
+		t.Fatal(err)
+	}
+	if usage := getTestPolicyUsage(t, day); usage != "0" {
+		t.Fatalf("unagreed usages are recorded, usage %v", usage)
+	}
+
+	if err := checkAcceptPolicy("k3", newTestPolicyArgs("s3", 200)); err != nil {
+		t.Fatalf("usages are not released after accept failed: %v", err)
+	}
+	if err := settlePolicyUsages("k3", acceptAgree, nil); err != nil {
+		t.Fatal(err)
+	}
+	if usage := getTestPolicyUsage(t, day); usage != "200" {
+		t.Fatalf("agreed usages are not recorded, usage %v", usage)
+	}
+	if len(policyReservations) != 0 || len(reservedPolicyUsages) != 0 {
+		t.Fatal("reservations are left after settled")
+	}
+	if err := checkAcceptPolicy("k4", newTestPolicyArgs("s4", 100)); !errors.Is(err, errPolicyExceedDailyCap) {
+		t.Fatalf("recorded usages are not counted: %v", err)
+	}
+}
+
+func TestAcceptPolicyReplacedSwapNotCounted(t *testing.T) {
+	setupTestAcceptPolicy(t)
+	day := getPolicyDay(now())
+
+	if err := checkAcceptPolicy("k1", newTestPolicyArgs("s1", 100)); err != nil {
+		t.Fatal(err)
+	}
+	if err := settlePolicyUsages("k1", acceptAgree, nil); err != nil {
+		t.Fatal(err)
+	}
+
+	// replace the counted swap
+	for _, keyID := range []string{"k2", "k3"} {
+		if err := checkAcceptPolicy(keyID, newTestPolicyArgs("s1", 100)); err != nil {
+			t.Fatalf("replace of counted swap is disagreed: %v", err)
+		}
+		if err := settlePolicyUsages(keyID, acceptAgree, nil); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// two signs of the same swap in process are recorded once
+	if err := checkAcceptPolicy("k4", newTestPolicyArgs("s2", 50)); err != nil {
+		t.Fatal(err)
+	}
+	if err := checkAcceptPolicy("k5", newTestPolicyArgs("s2", 50)); err != nil {
+		t.Fatal(err)
+	}
+	_ = settlePolicyUsages("k4", acceptAgree, nil)
+	_ = settlePolicyUsages("k5", acceptAgree, nil)
+
+	if usage := getTestPolicyUsage(t, day); usage != "150" {
+		t.Fatalf("replaced swaps are double counted, usage %v", usage)
+	}
+}
+
+func TestAcceptPolicyDailyUsagesByUTCDay(t *testing.T) {
+	setupTestAcceptPolicy(t)
+
+	day1 := getPolicyDay(1700006399) // 2023-11-14 23:59:59 UTC
+	day2 := getPolicyDay(1700006400)
+	if day1 != "20231114" || day2 != "20231115" {
+		t.Fatalf("wrong utc days %v %v", day1, day2)
+	}
+
+	value := new(big.Int).Mul(big.NewInt(200), big.NewInt(1e18))
+	limits := [][]*policyLimit{{{key: tPolicyLimitKey, config: params.GetOraclePolicy().GetTokenLimit(tPolicyTokenID)}}}
+	argsList := []*tokens.BuildTxArgs{newTestPolicyArgs("s1", 200)}
+	if err := checkAndReserveDailyUsages("k1", day1, argsList, []*big.Int{value}, limits); err != nil {
+		t.Fatal(err)
+	}
+	if err := recordPolicyUsages("k1"); err != nil {
+		t.Fatal(err)
+	}
+	argsList = []*tokens.BuildTxArgs{newTestPolicyArgs("s2", 200)}
+	if err := checkAndReserveDailyUsages("k2", day1, argsList, []*big.Int{value}, limits); !errors.Is(err, errPolicyExceedDailyCap) {
+		t.Fatalf("usages of the same day are not counted: %v", err)
+	}
+	if err := checkAndReserveDailyUsages("k2", day2, argsList, []*big.Int{value}, limits); err != nil {
+		t.Fatalf("usages of the previous day are counted: %v", err)
+	}
+}
@@ -0,0 +1,55 @@
+package worker
+
+import (
+	"time"
+
+	"github.com/anyswap/CrossChain-Router/v3/cmd/utils"
+	"github.com/anyswap/CrossChain-Router/v3/mongodb"
+	"github.com/anyswap/CrossChain-Router/v3/params"
+)
+
+var (
+	restIntervalInArchiveJob = 3600 * time.Second
+	archiveBatchSize         = int64(1000)
+)
+
+// StartArchiveJob archive job
+func StartArchiveJob() {
+	serverCfg := params.GetRouterServerConfig()
+	if serverCfg == nil || serverCfg.ArchiveSwapDays == 0 {
+		return
+	}
+	if !mongodb.HasClient() {
+		logWorkerWarn("archive", "stop archive job as it is only for mongodb")
+		return
+	}
+	logWorker("archive", "start archive job", "archiveSwapDays", serverCfg.ArchiveSwapDays)
+
+	mongodb.MgoWaitGroup.Add(1)
+	go doArchiveJob(serverCfg.ArchiveSwapDays * 24 * 3600)
+}
+
+func doArchiveJob(archiveAge int64) {
+	defer mongodb.MgoWaitGroup.Done()
+	for {
+		before := getSepTimeInFind(archiveAge)
+		for !utils.IsCleanuping() {
+			count, err := mongodb.ArchiveRouterSwaps(before, archiveBatchSize)
+			if err != nil {
+				logWorkerError("archive", "archive router swaps error", err)
+			}
+			if count > 0 {
+				logWorker("archive", "archive router swaps", "count", count, "before", before)
+			}
+			if err != nil || int64(count) < archiveBatchSize {
+				break
+			}
+		}
+		select {
+		case <-utils.CleanupChan:
+			logWorker("archive", "stop archive job")
+			return
+		case <-time.After(restIntervalInArchiveJob):
+		}
+	}
+}
@@ -0,0 +1,230 @@
+package worker
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/anyswap/CrossChain-Router/v3/mongodb"
+	"github.com/anyswap/CrossChain-Router/v3/mpc"
+	"github.com/anyswap/CrossChain-Router/v3/params"
+	"github.com/anyswap/CrossChain-Router/v3/router"
+	"github.com/anyswap/CrossChain-Router/v3/tokens"
+)
+
+type swapTxToSign struct {
+	rawTx interface{}
+	args  *tokens.BuildTxArgs
+}
+
+// collectSwapTasks collect more swap tasks in channel to sign in batch
+func collectSwapTasks(chainID string, first *tokens.BuildTxArgs, swapChan <-chan *tokens.BuildTxArgs, size int) []*tokens.BuildTxArgs {
+	batch := []*tokens.BuildTxArgs{first}
+	for len(batch) < size {
+		select {
+		case args := <-swapChan:
+			if args.ToChainID.String() != chainID {
+				logWorkerWarn("doSwap", "ignore swap task as toChainID mismatch", "want", chainID, "args", args)
+				continue
+			}
+			batch = append(batch, args)
+		default:
+			return batch
+		}
+	}
+	return batch
+}
+
+func doSwapBatch(batch []*tokens.BuildTxArgs) {
+	swapTxs := make([]*swapTxToSign, 0, len(batch))
+	for _, args := range batch {
+		rawTx, err := buildSwapTxParallel(args)
+		switch {
+		case err == nil:
+			swapTxs = append(swapTxs, &swapTxToSign{rawTx: rawTx, args: args})
+		case errors.Is(err, errAlreadySwapped),
+			errors.Is(err, tokens.ErrNoBridgeForChainID):
+		default:
+			logWorkerError("doSwap", "process router swap failed", err, "args", args)
+		}
+	}
+	for _, group := range groupSwapTxsByConsecutiveNonces(swapTxs) {
+		go signAndSendTxs(group)
+	}
+}
+
+// groupSwapTxsByConsecutiveNonces group swap txs of the same sender
+// into batches with consecutive nonces, the same source chain and mpc value tier
+func groupSwapTxsByConsecutiveNonces(swapTxs []*swapTxToSign) (groups [][]*swapTxToSign) {
+	senderTxs := make(map[string][]*swapTxToSign)
+	senders := make([]string, 0)
+	for _, swapTx := range swapTxs {
+		sender := strings.ToLower(swapTx.args.From)
+		if _, exist := senderTxs[sender]; !exist {
+			senders = append(senders, sender)
+		}
+		senderTxs[sender] = append(senderTxs[sender], swapTx)
+	}
+	for _, sender := range senders {
+		txs := senderTxs[sender]
+		sort.SliceStable(txs, func(i, j int) bool {
+			return txs[i].args.GetTxNonce() < txs[j].args.GetTxNonce()
+		})
+		start := 0
+		for i := 1; i <= len(txs); i++ {
+			if i == len(txs) ||
+				txs[i].args.GetTxNonce() != txs[i-1].args.GetTxNonce()+1 ||
+				txs[i].args.FromChainID.Cmp(txs[i-1].args.FromChainID) != 0 ||
+				router.IsHighValueMPCSwap(txs[i].args) != router.IsHighValueMPCSwap(txs[i-1].args) {
+				groups = append(groups, txs[start:i])
+				start = i
+			}
+		}
+	}
+	return groups
+}
+
+func signAndSendTxs(swapTxs []*swapTxToSign) {
+	first := swapTxs[0].args
+	resBridge := router.GetBridgeByChainID(first.ToChainID.String())
+	batchSigner, ok := resBridge.(tokens.IMPCBatchSign)
+	if len(swapTxs) == 1 || !ok {
+		for _, swapTx := range swapTxs {
+			_ = signAndSendTx(swapTx.rawTx, swapTx.args)
+		}
+		return
+	}
+
+	rawTxs := make([]interface{}, len(swapTxs))
+	argsList := make([]*tokens.BuildTxArgs, len(swapTxs))
+	txids := make([]string, len(swapTxs))
+	for i, swapTx := range swapTxs {
+		rawTxs[i] = swapTx.rawTx
+		argsList[i] = swapTx.args
+		txids[i] = swapTx.args.SwapID
+	}
+	ctx := []interface{}{
+		"toChainID", first.ToChainID, "mpc", first.From, "firstNonce", first.GetTxNonce(), "count", len(swapTxs), "txids", txids,
+	}
+
+	signedTxs, txHashes, err := batchSigner.MPCSignTransactions(rawTxs, argsList)
+	if err != nil {
+		logWorkerError("doSwap", "batch sign txs failed", err, ctx...)
+		if errors.Is(err, mpc.ErrGetSignStatusHasDisagree) {
+			for _, args := range argsList {
+				reverifySwap(args)
+			}
+		}
+		return
+	}
+	logWorker("doSwap", "batch sign txs success", ctx...)
+
+	for i, args := range argsList {
+		_ = updateAndSendSignedTx(resBridge, signedTxs[i], txHashes[i], args)
+	}
+}
+
+// verifyBatchSignInfo verify every tx of batch sign (called by oracle)
+func verifyBatchSignInfo(mpcConfig *mpc.Config, keyID string, msgHash, msgContext []string) ([]*tokens.BuildTxArgs, error) {
+	if len(msgContext) < len(msgHash) {
+		return nil, errWrongMsgContext
+	}
+	argsList := make([]*tokens.BuildTxArgs, len(msgHash))
+	swapKeys := make(map[string]struct{}, len(msgHash))
+	for i := range msgHash {
+		var args tokens.BuildTxArgs
+		if err := json.Unmarshal([]byte(msgContext[i]), &args); err != nil {
+			return nil, errWrongMsgContext
+		}
+		if args.Identifier != params.GetIdentifier() {
+			return nil, errIdentifierMismatch
+		}
+		if args.FromChainID == nil || args.ToChainID == nil {
+			return nil, errWrongMsgContext
+		}
+		if args.SwapType == tokens.NonceGapFillType || args.GetTxNonce() == 0 {
+			return nil, errors.New("batch sign of unsupported tx")
+		}
+		if i > 0 {
+			prev := argsList[i-1]
+			if !strings.EqualFold(args.From, prev.From) ||
+				args.FromChainID.Cmp(prev.FromChainID) != 0 ||
+				args.ToChainID.Cmp(prev.ToChainID) != 0 {
+				return nil, errors.New("batch sign with different sender or chain")
+			}
+			if router.IsHighValueMPCSwap(&args) != router.IsHighValueMPCSwap(prev) {
+				return nil, errors.New("batch sign with different mpc value tiers")
+			}
+			if args.GetTxNonce() != prev.GetTxNonce()+1 {
+				return nil, errors.New("batch sign with inconsecutive nonces")
+			}
+		}
+		swapKey := mongodb.GetRouterSwapKey(args.FromChainID.String(), args.SwapID, args.LogIndex)
+		if _, exist := swapKeys[swapKey]; exist {
+			return nil, errors.New("batch sign with duplicate swaps")
+		}
+		swapKeys[swapKey] = struct{}{}
+		argsList[i] = &args
+	}
+
+	rebuilts := make([]*rebuiltSwapTx, len(msgHash))
+	for i, args := range argsList {
+		if lvldbHandle != nil {
+			if err := CheckAcceptRecord(args); err != nil {
+				return nil, err
+			}
+		}
+		rebuilt, err := rebuildSwapTxAndVerifyMsgHash(keyID, []string{msgHash[i]}, args)
+		if err == nil {
+			err = checkMPCValueTier(mpcConfig, rebuilt.args)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("batch sign tx %v: %w", i, err)
+		}
+		rebuilts[i] = rebuilt
+	}
+	rebuiltArgs := make([]*tokens.BuildTxArgs, len(rebuilts))
+	for i, rebuilt := range rebuilts {
+		rebuiltArgs[i] = rebuilt.args
+	}
+	if err := checkAcceptPolicy(keyID, rebuiltArgs...); err != nil {
+		return nil, err
+	}
+	logWorker("accept", "verify batch sign success", "keyID", keyID, "count", len(rebuilts))
+	if lvldbHandle != nil {
+		go saveBatchAcceptRecords(keyID, rebuilts)
+	}
+	return rebuiltArgs, nil
+}
+
+func saveBatchAcceptRecords(keyID string, rebuilts []*rebuiltSwapTx) {
+	impl, ok := rebuilts[0].bridge.(interface {
+		GetSignedTxHashesOfKeyID(args *tokens.BuildTxArgs, keyID string, rawTxs []interface{}) (txHashes []string, err error)
+	})
+	if !ok {
+		return
+	}
+
+	rawTxs := make([]interface{}, len(rebuilts))
+	for i, rebuilt := range rebuilts {
+		rawTxs[i] = rebuilt.rawTx
+	}
+	swapTxs, err := impl.GetSignedTxHashesOfKeyID(rebuilts[0].args, keyID, rawTxs)
+	if err != nil {
+		logWorkerError("accept", "get signed tx hashes failed", err, "keyID", keyID)
+		return
+	}
+
+	for i, rebuilt := range rebuilts {
+		ctx := append(rebuilt.ctx, "swaptx", swapTxs[i])
+		err = AddAcceptRecord(rebuilt.args, swapTxs[i])
+		if err != nil {
+			logWorkerError("accept", "save accept record to db failed", err, ctx...)
+			continue
+		}
+		logWorker("accept", "save accept record to db success", ctx...)
+		setAcceptLedgerTxHash(keyID, rebuilt.args, swapTxs[i])
+	}
+}
@@ -0,0 +1,137 @@
+package worker
+
+import (
+	"encoding/json"
+	"errors"
+	"math/big"
+	"strings"
+	"testing"
+
+	"github.com/anyswap/CrossChain-Router/v3/params"
+	"github.com/anyswap/CrossChain-Router/v3/tokens"
+)
+
+const (
+	tBatchIdentifier = "routerswap#batchtest"
+	tBatchSender     = "0x1111111111111111111111111111111111111111"
+	tBatchSender2    = "0x2222222222222222222222222222222222222222"
+)
+
+func newTestBatchArgs(swapID string, from string, fromChainID, nonce uint64) *tokens.BuildTxArgs {
+	return &tokens.BuildTxArgs{
+		SwapArgs: tokens.SwapArgs{
+			Identifier:  tBatchIdentifier,
+			SwapID:      swapID,
+			SwapType:    tokens.ERC20SwapType,
+			FromChainID: new(big.Int).SetUint64(fromChainID),
+			ToChainID:   big.NewInt(56),
+			SwapInfo: tokens.SwapInfo{ERC20SwapInfo: &tokens.ERC20SwapInfo{
+				TokenID: "USDT",
+			}},
+		},
+		From:        from,
+		OriginValue: big.NewInt(1000),
+		Extra: &tokens.AllExtras{EthExtra: &tokens.EthExtraArgs{
+			Nonce: &nonce,
+		}},
+	}
+}
+
+// thresholds are initialized from the current extra config in checking
+func setTestHighValueMPCThresholds(t *testing.T) {
+	setExtra := func(extra *params.ExtraConfig) error {
+		params.GetRouterConfig().Extra = extra
+		return extra.CheckConfig()
+	}
+	err := setExtra(&params.ExtraConfig{
+		HighValueMPCThresholds: map[string]string{"USDC": "100"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { _ = setExtra(&params.ExtraConfig{}) })
+}
+
+func TestGroupSwapTxsByConsecutiveNonces(t *testing.T) {
+	setTestHighValueMPCThresholds(t)
+
+	highValue := newTestBatchArgs("h5", tBatchSender, 1, 5)
+	highValue.ERC20SwapInfo.TokenID = "USDC" // no bridge, always high value
+
+	swapTxs := []*swapTxToSign{
+		{args: newTestBatchArgs("a3", tBatchSender, 1, 3)},
+		{args: newTestBatchArgs("a1", tBatchSender, 1, 1)},
+		{args: newTestBatchArgs("b1", tBatchSender2, 1, 1)},
+		{args: newTestBatchArgs("a2", tBatchSender, 1, 2)},
+		{args: newTestBatchArgs("a4", strings.ToUpper(tBatchSender), 10, 4)}, // different source chain
+		{args: highValue}, // different mpc value tier
+		{args: newTestBatchArgs("a6", tBatchSender, 1, 6)},
+		{args: newTestBatchArgs("a8", tBatchSender, 1, 8)}, // nonce gap
+		{args: newTestBatchArgs("b2", tBatchSender2, 1, 2)},
+	}
+	var have []string
+	for _, group := range groupSwapTxsByConsecutiveNonces(swapTxs) {
+		ids := make([]string, len(group))
+		for i, swapTx := range group {
+			ids[i] = swapTx.args.SwapID
+		}
+		have = append(have, strings.Join(ids, ","))
+	}
+	want := []string{"a1,a2,a3", "a4", "h5", "a6", "a8", "b1,b2"}
+	if strings.Join(have, " ") != strings.Join(want, " ") {
+		t.Errorf("groups mismatch, have %v want %v", have, want)
+	}
+}
+
+func TestVerifyBatchSignInfo(t *testing.T) {
+	params.GetRouterConfig().Identifier = tBatchIdentifier
+	defer func() { params.GetRouterConfig().Identifier = "" }()
+	setTestHighValueMPCThresholds(t)
+
+	toContext := func(argsList ...*tokens.BuildTxArgs) []string {
+		msgContext := make([]string, len(argsList))
+		for i, args := range argsList {
+			data, err := json.Marshal(args)
+			if err != nil {
+				t.Fatal(err)
+			}
+			msgContext[i] = string(data)
+		}
+		return msgContext
+	}
+	noToChain := newTestBatchArgs("a2", tBatchSender, 1, 2)
+	noToChain.ToChainID = nil
+	noFromChain := newTestBatchArgs("a1", tBatchSender, 1, 1)
+	noFromChain.FromChainID = nil
+	highValue := newTestBatchArgs("a2", tBatchSender, 1, 2)
+	highValue.ERC20SwapInfo.TokenID = "USDC"
+
+	tests := []struct {
+		name    string
+		context []string
+		wantErr string
+	}{
+		{"nonce gap", toContext(newTestBatchArgs("a1", tBatchSender, 1, 1), newTestBatchArgs("a3", tBatchSender, 1, 3)), "inconsecutive nonces"},
+		{"mixed senders", toContext(newTestBatchArgs("a1", tBatchSender, 1, 1), newTestBatchArgs("b2", tBatchSender2, 1, 2)), "different sender or chain"},
+		{"mixed source chains", toContext(newTestBatchArgs("a1", tBatchSender, 1, 1), newTestBatchArgs("a2", tBatchSender, 10, 2)), "different sender or chain"},
+		{"mixed value tiers", toContext(newTestBatchArgs("a1", tBatchSender, 1, 1), highValue), "different mpc value tiers"},
+		{"duplicate swaps", toContext(newTestBatchArgs("a1", tBatchSender, 1, 1), newTestBatchArgs("a1", tBatchSender, 1, 2)), "duplicate swaps"},
+		{"missing to chain", toContext(newTestBatchArgs("a1", tBatchSender, 1, 1), noToChain), errWrongMsgContext.Error()},
+		{"missing from chain", toContext(noFromChain, newTestBatchArgs("a2", tBatchSender, 1, 2)), errWrongMsgContext.Error()},
+		{"short context", toContext(newTestBatchArgs("a1", tBatchSender, 1, 1)), errWrongMsgContext.Error()},
+		{"valid batch", toContext(newTestBatchArgs("a1", tBatchSender, 1, 1), newTestBatchArgs("a2", tBatchSender, 1, 2)), tokens.ErrNoBridgeForChainID.Error()},
+	}
+	for _, test := range tests {
+		_, err := verifyBatchSignInfo(nil, "keyID", []string{"0x01", "0x02"}, test.context)
+		if err == nil || !strings.Contains(err.Error(), test.wantErr) {
+			t.Errorf("%v: error mismatch, have %v want %v", test.name, err, test.wantErr)
+		}
+	}
+
+	wrongIdentifier := newTestBatchArgs("a2", tBatchSender, 1, 2)
+	wrongIdentifier.Identifier = "routerswap#other"
+	context := toContext(newTestBatchArgs("a1", tBatchSender, 1, 1), wrongIdentifier)
+	if _, err := verifyBatchSignInfo(nil, "keyID", []string{"0x01", "0x02"}, context); !errors.Is(err, errIdentifierMismatch) {
+		t.Errorf("identifier mismatch: have %v", err)
+	}
+}
@@ -7,6 +7,7 @@ import (
 	"github.com/anyswap/CrossChain-Router/v3/common"
 	"github.com/anyswap/CrossChain-Router/v3/mongodb"
 	"github.com/anyswap/CrossChain-Router/v3/router"
+	"github.com/anyswap/CrossChain-Router/v3/storage"
 	"github.com/anyswap/CrossChain-Router/v3/tokens"
 )
 
@@ -22,7 +23,7 @@ func doCheckFailedSwapJob() {
 	defer mongodb.MgoWaitGroup.Done()
 	for {
 		septime := getSepTimeInFind(maxCheckFailedSwapLifetime)
-		res, err := mongodb.FindRouterSwapResultsWithStatus(mongodb.MatchTxFailed, septime)
+		res, err := storage.Get().FindRouterSwapResultsWithStatus(mongodb.MatchTxFailed, septime)
 		if err != nil {
 			logWorkerError("checkfailedswap", "find failed router swap error", err)
 		}
@@ -81,9 +82,9 @@ func checkFailedRouterSwap(swap *mongodb.MgoSwapResult) error {
 			"swaptx", swap.SwapTx, "swapnonce", swap.SwapNonce,
 			"swapheight", txStatus.BlockHeight, "confirmations", txStatus.Confirmations)
 		if txStatus.Confirmations < resBridge.GetChainConfig().Confirmations {
-			return markSwapResultUnstable(swap.FromChainID, swap.TxID, swap.LogIndex)
+			return markSwapResultUnstable(swap.FromChainID, swap.TxID, swap.LogIndex, "checkfailedswap")
 		}
-		return markSwapResultStable(swap.FromChainID, swap.TxID, swap.LogIndex)
+		return markSwapResultStable(swap.FromChainID, swap.TxID, swap.LogIndex, "checkfailedswap")
 	}
 
 	nonce, err := nonceSetter.GetPoolNonce(swap.MPC, "latest")
@@ -96,7 +97,7 @@ func checkFailedRouterSwap(swap *mongodb.MgoSwapResult) error {
 			"fromChainID", swap.FromChainID, "toChainID", swap.ToChainID,
 			"txid", swap.TxID, "logIndex", swap.LogIndex,
 			"swaptx", swap.SwapTx, "swapnonce", swap.SwapNonce, "latestnonce", nonce)
-		return markSwapResultUnstable(swap.FromChainID, swap.TxID, swap.LogIndex)
+		return markSwapResultUnstable(swap.FromChainID, swap.TxID, swap.LogIndex, "checkfailedswap")
 	}
 	return nil
 }
@@ -1,13 +1,36 @@
 package worker
 
 import (
+	"errors"
 	"strings"
+	"time"
 
 	"github.com/anyswap/CrossChain-Router/v3/mongodb"
 	"github.com/anyswap/CrossChain-Router/v3/params"
+	"github.com/anyswap/CrossChain-Router/v3/storage"
 	"github.com/anyswap/CrossChain-Router/v3/tokens"
 )
 
+const (
+	maxVersionConflictRetries  = 3
+	versionConflictRetryPeriod = 100 * time.Millisecond
+)
+
+// retryOnVersionConflict retry if the swap document is modified concurrently.
+// storage re-reads the document and re-checks the update conditions on each try,
+// updates of swaptx should set the expected swaptx, so a racing job never
+// overwrites the swaptx updated by others.
+func retryOnVersionConflict(update func() error) (err error) {
+	for i := 0; i < maxVersionConflictRetries; i++ {
+		err = update()
+		if !errors.Is(err, mongodb.ErrVersionConflict) {
+			return err
+		}
+		time.Sleep(versionConflictRetryPeriod)
+	}
+	return err
+}
+
 // MatchTx struct
 type MatchTx struct {
 	MPC        string
@@ -16,6 +39,11 @@ type MatchTx struct {
 	SwapTime   uint64
 	SwapValue  string
 	SwapNonce  uint64
+
+	SwapGasLimit uint64
+	SwapGasUsed  uint64
+
+	OldSwapTx string // current swaptx when the tx is matched
 }
 
 // AddInitialSwapResult add initial result
@@ -47,7 +75,7 @@ func AddInitialSwapResult(swapInfo *tokens.SwapTxInfo, status mongodb.SwapStatus
 		Memo:        "",
 	}
 	swapResult.SwapInfo = mongodb.ConvertToSwapInfo(&swapInfo.SwapInfo)
-	err = mongodb.AddRouterSwapResult(swapResult)
+	err = storage.Get().AddRouterSwapResult(swapResult)
 	if err != nil {
 		logWorkerError("add", "addInitialSwapResult failed", err, "chainid", swapInfo.FromChainID, "txid", swapInfo.Hash, "logIndex", swapInfo.LogIndex)
 	} else {
@@ -58,8 +86,10 @@ func AddInitialSwapResult(swapInfo *tokens.SwapTxInfo, status mongodb.SwapStatus
 
 func updateRouterSwapResult(fromChainID, txid string, logIndex int, mtx *MatchTx) (err error) {
 	updates := &mongodb.SwapResultUpdateItems{
-		Status:    mongodb.KeepStatus,
-		Timestamp: now(),
+		Status:       mongodb.KeepStatus,
+		Timestamp:    now(),
+		SwapGasLimit: mtx.SwapGasLimit,
+		SwapGasUsed:  mtx.SwapGasUsed,
 	}
 	if mtx.SwapHeight == 0 {
 		updates.SwapValue = mtx.SwapValue
@@ -70,6 +100,7 @@ func updateRouterSwapResult(fromChainID, txid string, logIndex int, mtx *MatchTx
 			updates.MPC = mtx.MPC
 			updates.SwapTx = mtx.SwapTx
 			updates.Status = mongodb.MatchTxNotStable
+			updates.Actor = "swap"
 		}
 	} else {
 		updates.SwapNonce = mtx.SwapNonce
@@ -78,8 +109,12 @@ func updateRouterSwapResult(fromChainID, txid string, logIndex int, mtx *MatchTx
 		if mtx.SwapTx != "" {
 			updates.SwapTx = mtx.SwapTx
 		}
+		// the height is of the old swaptx or one of its replaced txs
+		updates.ExpectedSwapTx = &mtx.OldSwapTx
 	}
-	err = mongodb.UpdateRouterSwapResult(fromChainID, txid, logIndex, updates)
+	err = retryOnVersionConflict(func() error {
+		return storage.Get().UpdateRouterSwapResult(fromChainID, txid, logIndex, updates)
+	})
 	if err != nil {
 		logWorkerError("update", "updateSwapResult failed", err,
 			"chainid", fromChainID, "txid", txid, "logIndex", logIndex,
@@ -101,7 +136,9 @@ func updateSwapTimestamp(fromChainID, txid string, logIndex int) (err error) {
 		Status:    mongodb.KeepStatus,
 		Timestamp: now(),
 	}
-	err = mongodb.UpdateRouterSwapResult(fromChainID, txid, logIndex, updates)
+	err = retryOnVersionConflict(func() error {
+		return storage.Get().UpdateRouterSwapResult(fromChainID, txid, logIndex, updates)
+	})
 	if err != nil {
 		logWorkerError("update", "updateSwapTimestamp failed", err, "chainid", fromChainID, "txid", txid, "logIndex", logIndex)
 	} else {
@@ -110,26 +147,59 @@ func updateSwapTimestamp(fromChainID, txid string, logIndex int) (err error) {
 	return err
 }
 
-func updateSwapTx(fromChainID, txid string, logIndex int, swapTx string) (err error) {
+func updateSwapTx(fromChainID, txid string, logIndex int, oldSwapTx, swapTx string) (err error) {
+	return updateSwapTxWithGasLimit(fromChainID, txid, logIndex, oldSwapTx, swapTx, 0)
+}
+
+func updateSwapTxWithGasLimit(fromChainID, txid string, logIndex int, oldSwapTx, swapTx string, gasLimit uint64) (err error) {
 	updates := &mongodb.SwapResultUpdateItems{
-		Status:    mongodb.KeepStatus,
-		SwapTx:    swapTx,
-		Timestamp: now(),
+		Status:         mongodb.KeepStatus,
+		SwapTx:         swapTx,
+		SwapGasLimit:   gasLimit,
+		Timestamp:      now(),
+		ExpectedSwapTx: &oldSwapTx,
 	}
-	err = mongodb.UpdateRouterSwapResult(fromChainID, txid, logIndex, updates)
+	err = retryOnVersionConflict(func() error {
+		return storage.Get().UpdateRouterSwapResult(fromChainID, txid, logIndex, updates)
+	})
 	if err != nil {
-		logWorkerError("update", "updateSwapTx failed", err, "chainid", fromChainID, "txid", txid, "logIndex", logIndex, "swaptx", swapTx)
+		logWorkerError("update", "updateSwapTx failed", err, "chainid", fromChainID, "txid", txid, "logIndex", logIndex, "swaptx", swapTx, "gasLimit", gasLimit)
 	} else {
-		logWorker("update", "updateSwapTx success", "chainid", fromChainID, "txid", txid, "logIndex", logIndex, "swaptx", swapTx)
+		logWorker("update", "updateSwapTx success", "chainid", fromChainID, "txid", txid, "logIndex", logIndex, "swaptx", swapTx, "gasLimit", gasLimit)
 	}
 	return err
 }
 
-func markSwapResultUnstable(fromChainID, txid string, logIndex int) (err error) {
+func addSwapTxAttempt(args *tokens.BuildTxArgs, swapTx string) {
+	attempt := &mongodb.SwapTxAttempt{
+		SwapTx:     swapTx,
+		Nonce:      args.GetTxNonce(),
+		ReplaceNum: args.GetReplaceNum(),
+		GasLimit:   args.GetTxGasLimit(),
+		Timestamp:  now(),
+	}
+	if args.Extra != nil && args.Extra.EthExtra != nil {
+		extra := args.Extra.EthExtra
+		if extra.GasPrice != nil {
+			attempt.GasPrice = extra.GasPrice.String()
+		}
+		if extra.GasTipCap != nil {
+			attempt.GasTipCap = extra.GasTipCap.String()
+		}
+		if extra.GasFeeCap != nil {
+			attempt.GasFeeCap = extra.GasFeeCap.String()
+		}
+	}
+	_ = storage.Get().AddRouterSwapTxAttempt(args.FromChainID.String(), args.SwapID, args.LogIndex, attempt)
+}
+
+func markSwapResultUnstable(fromChainID, txid string, logIndex int, actor string) (err error) {
 	status := mongodb.MatchTxNotStable
 	timestamp := now()
 	memo := "" // unchange
-	err = mongodb.UpdateRouterSwapResultStatus(fromChainID, txid, logIndex, status, timestamp, memo)
+	err = retryOnVersionConflict(func() error {
+		return storage.Get().UpdateRouterSwapResultStatus(fromChainID, txid, logIndex, status, timestamp, memo, actor)
+	})
 	if err != nil {
 		logWorkerError("checkfailedswap", "markSwapResultUnstable failed", err, "chainid", fromChainID, "txid", txid, "logIndex", logIndex)
 	} else {
@@ -138,11 +208,13 @@ func markSwapResultUnstable(fromChainID, txid string, logIndex int) (err error)
 	return err
 }
 
-func markSwapResultStable(fromChainID, txid string, logIndex int) (err error) {
+func markSwapResultStable(fromChainID, txid string, logIndex int, actor string) (err error) {
 	status := mongodb.MatchTxStable
 	timestamp := now()
 	memo := "" // unchange
-	err = mongodb.UpdateRouterSwapResultStatus(fromChainID, txid, logIndex, status, timestamp, memo)
+	err = retryOnVersionConflict(func() error {
+		return storage.Get().UpdateRouterSwapResultStatus(fromChainID, txid, logIndex, status, timestamp, memo, actor)
+	})
 	if err != nil {
 		logWorkerError("stable", "markSwapResultStable failed", err, "chainid", fromChainID, "txid", txid, "logIndex", logIndex)
 	} else {
@@ -151,11 +223,13 @@ func markSwapResultStable(fromChainID, txid string, logIndex int) (err error) {
 	return err
 }
 
-func markSwapResultFailed(fromChainID, txid string, logIndex int) (err error) {
+func markSwapResultFailed(fromChainID, txid string, logIndex int, actor string) (err error) {
 	status := mongodb.MatchTxFailed
 	timestamp := now()
 	memo := "" // unchange
-	err = mongodb.UpdateRouterSwapResultStatus(fromChainID, txid, logIndex, status, timestamp, memo)
+	err = retryOnVersionConflict(func() error {
+		return storage.Get().UpdateRouterSwapResultStatus(fromChainID, txid, logIndex, status, timestamp, memo, actor)
+	})
 	if err != nil {
 		logWorkerError("stable", "markSwapResultFailed failed", err, "chainid", fromChainID, "txid", txid, "logIndex", logIndex)
 	} else {
@@ -0,0 +1,176 @@
+package worker
+
+import (
+	"errors"
+	"sync"
+
+	"github.com/anyswap/CrossChain-Router/v3/tokens"
+)
+
+// disagree reason codes reported to server
+const (
+	reasonTxNotStable         = "TxNotStable"
+	reasonTxNotFound          = "TxNotFound"
+	reasonRPCQueryError       = "RPCQueryError"
+	reasonInitiatorMismatch   = "InitiatorMismatch"
+	reasonWrongContract       = "WrongContract"
+	reasonNoBridge            = "NoBridge"
+	reasonUnknownSwapType     = "UnknownSwapType"
+	reasonBindMismatch        = "BindMismatch"
+	reasonToChainIDMismatch   = "ToChainIDMismatch"
+	reasonSwapValueMismatch   = "SwapValueMismatch" // eg. decimals or fee config mismatch
+	reasonMsgHashMismatch     = "MsgHashMismatch"
+	reasonAlreadySwapped      = "AlreadySwapped"
+	reasonMPCValueTier        = "MPCValueTier"
+	reasonPolicyViolation     = "PolicyViolation"
+	reasonInvalidNonceGapFill = "InvalidNonceGapFill"
+	reasonVerifyTxFailed      = "VerifyTxFailed"
+	reasonOther               = "Other"
+
+	maxPendingDisagrees = 1000
+)
+
+var (
+	pendingDisagrees     []*disagreeReport
+	pendingDisagreesLock sync.Mutex
+
+	errUnknownSwapType     = errors.New("unknown router swap type")
+	errBindMismatch        = errors.New("bind mismatch")
+	errSwapValueMismatch   = errors.New("swap value mismatch")
+	errInvalidNonceGapFill = errors.New("invalid nonce gap fill")
+
+	policyErrors = []error{
+		errPolicyChainPaused,
+		errPolicyReceiverDenied,
+		errPolicyUnknownDecimals,
+		errPolicyExceedMaxValue,
+		errPolicyExceedDailyCap,
+		errPolicyNoLocalDatabase,
+	}
+
+	verifyTxErrors = []error{
+		tokens.ErrLogIndexOutOfRange,
+		tokens.ErrTxWithWrongReceipt,
+		tokens.ErrTxWithWrongReceiver,
+		tokens.ErrTxWithWrongTopics,
+		tokens.ErrSwapoutLogNotFound,
+		tokens.ErrTxWithRemovedLog,
+		tokens.ErrWrongBindAddress,
+		tokens.ErrTxWithWrongSender,
+		tokens.ErrTxWithWrongStatus,
+		tokens.ErrTxWithWrongValue,
+		tokens.ErrTxWithWrongPath,
+		tokens.ErrSwapInBlacklist,
+		tokens.ErrTxBeforeInitialHeight,
+		tokens.ErrFromChainIDMismatch,
+		tokens.ErrMissTokenConfig,
+		tokens.ErrEmptyTokenID,
+	}
+)
+
+// disagreeReport disagree reason of a swap reported to server
+type disagreeReport struct {
+	FromChainID string `json:"fromChainID"`
+	TxID        string `json:"txid"`
+	LogIndex    int    `json:"logIndex"`
+	KeyID       string `json:"keyID"`
+	Code        string `json:"code"`
+	Reason      string `json:"reason"`
+	Timestamp   int64  `json:"timestamp"`
+}
+
+// getDisagreeReasonCode get reason code of disagree error
+func getDisagreeReasonCode(err error) string {
+	switch {
+	case errors.Is(err, tokens.ErrTxNotStable):
+		return reasonTxNotStable
+	case errors.Is(err, tokens.ErrTxNotFound):
+		return reasonTxNotFound
+	case tokens.IsRPCQueryOrNotFoundError(err):
+		return reasonRPCQueryError
+	case errors.Is(err, errInitiatorMismatch):
+		return reasonInitiatorMismatch
+	case errors.Is(err, tokens.ErrTxWithWrongContract):
+		return reasonWrongContract
+	case errors.Is(err, tokens.ErrNoBridgeForChainID):
+		return reasonNoBridge
+	case errors.Is(err, errUnknownSwapType):
+		return reasonUnknownSwapType
+	case errors.Is(err, errBindMismatch):
+		return reasonBindMismatch
+	case errors.Is(err, tokens.ErrToChainIDMismatch):
+		return reasonToChainIDMismatch
+	case errors.Is(err, errSwapValueMismatch):
+		return reasonSwapValueMismatch
+	case errors.Is(err, tokens.ErrMsgHashMismatch),
+		errors.Is(err, tokens.ErrWrongCountOfMsgHashes):
+		return reasonMsgHashMismatch
+	case errors.Is(err, errAlreadySwapped):
+		return reasonAlreadySwapped
+	case errors.Is(err, errMPCValueTierMismatch):
+		return reasonMPCValueTier
+	case errors.Is(err, errInvalidNonceGapFill):
+		return reasonInvalidNonceGapFill
+	}
+	for _, perr := range policyErrors {
+		if errors.Is(err, perr) {
+			return reasonPolicyViolation
+		}
+	}
+	for _, verr := range verifyTxErrors {
+		if errors.Is(err, verr) {
+			return reasonVerifyTxFailed
+		}
+	}
+	return reasonOther
+}
+
+// addDisagreeReports queue disagree reason of swaps to report to server
+func addDisagreeReports(keyID, code, reason string, argsList []*tokens.BuildTxArgs) {
+	if len(argsList) == 0 {
+		return
+	}
+	timestamp := now()
+
+	pendingDisagreesLock.Lock()
+	defer pendingDisagreesLock.Unlock()
+
+	for _, args := range argsList {
+		pendingDisagrees = append(pendingDisagrees, &disagreeReport{
+			FromChainID: args.FromChainID.String(),
+			TxID:        args.SwapID,
+			LogIndex:    args.LogIndex,
+			KeyID:       keyID,
+			Code:        code,
+			Reason:      reason,
+			Timestamp:   timestamp,
+		})
+	}
+	if overflow := len(pendingDisagrees) - maxPendingDisagrees; overflow > 0 {
+		pendingDisagrees = pendingDisagrees[overflow:]
+	}
+}
+
+// takePendingDisagrees take out queued disagree reasons
+func takePendingDisagrees() []*disagreeReport {
+	pendingDisagreesLock.Lock()
+	defer pendingDisagreesLock.Unlock()
+
+	reports := pendingDisagrees
+	pendingDisagrees = nil
+	return reports
+}
+
+// requeueDisagrees put back disagree reasons failed to report
+func requeueDisagrees(reports []*disagreeReport) {
+	if len(reports) == 0 {
+		return
+	}
+	pendingDisagreesLock.Lock()
+	defer pendingDisagreesLock.Unlock()
+
+	pendingDisagrees = append(reports, pendingDisagrees...)
+	if overflow := len(pendingDisagrees) - maxPendingDisagrees; overflow > 0 {
+		pendingDisagrees = pendingDisagrees[overflow:]
+	}
+}
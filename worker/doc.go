@@ -13,5 +13,9 @@
 //		replace swap with the same tx nonce value when the sent swaptx is not packed into block because of lack fee or other reasons.
 //	passbigvalue
 //		pass big value swap if the swap value is too large.
+//	archive
+//		move swaps in terminal statuses to archive collections (optional).
+//	watch
+//		watch database changes to wake up verify/swap/stable/replace jobs immediately (optional).
 // Most the above jobs is assigned to the `server` node, the `oracle` node mainly do the `accept` job.
 package worker
@@ -0,0 +1,415 @@
+package worker
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+	"time"
+
+	"github.com/anyswap/CrossChain-Router/v3/cmd/utils"
+	"github.com/anyswap/CrossChain-Router/v3/common"
+	"github.com/anyswap/CrossChain-Router/v3/mongodb"
+	"github.com/anyswap/CrossChain-Router/v3/params"
+	"github.com/anyswap/CrossChain-Router/v3/router"
+	"github.com/anyswap/CrossChain-Router/v3/storage"
+	"github.com/anyswap/CrossChain-Router/v3/tokens"
+)
+
+var (
+	restIntervalInNonceAuditJob = 600 * time.Second
+
+	// allocated nonce of swap result with timestamp after it is treated as in processing
+	nonceGapGraceTime = int64(300) // seconds
+	// max number of nonces to check in one audit
+	maxNonceAuditRange = uint64(1000)
+	// max number of fill records to report in one audit
+	maxNonceGapFillsInReport = int64(20)
+
+	errNotNonceAuditBridge = errors.New("bridge does not support nonce audit")
+)
+
+// nonce gap reasons
+const (
+	NonceGapUnallocated = "unallocated" // no swap result is allocated with this nonce
+	NonceGapNotInPool   = "notinpool"   // allocated swap tx is not in the tx pool
+)
+
+type nonceAuditBridge interface {
+	tokens.IBridge
+	tokens.NonceSetter
+	GetSwapNonceAccounts() []string
+	RemoveRecycleSwapNonce(sender string, nonce uint64)
+}
+
+// NonceAuditReport nonce audit report of mpc
+type NonceAuditReport struct {
+	ChainID       string                     `json:"chainid"`
+	MPC           string                     `json:"mpc"`
+	LatestNonce   uint64                     `json:"latestnonce"`
+	PendingNonce  uint64                     `json:"pendingnonce"`
+	NextSwapNonce uint64                     `json:"nextswapnonce"`
+	Gaps          []*NonceGap                `json:"gaps,omitempty"`
+	Orphans       []*NonceOrphan             `json:"orphans,omitempty"`
+	Fills         []*mongodb.MgoNonceGapFill `json:"fills,omitempty"`
+	Timestamp     int64                      `json:"timestamp"`
+}
+
+// NonceGap nonce which blocks the following swaps
+type NonceGap struct {
+	Nonce       uint64 `json:"nonce"`
+	Reason      string `json:"reason"`
+	FromChainID string `json:"fromChainID,omitempty"`
+	TxID        string `json:"txid,omitempty"`
+	LogIndex    int    `json:"logIndex,omitempty"`
+	SwapTx      string `json:"swaptx,omitempty"`
+}
+
+// NonceOrphan swap whose nonce is passed but its swap tx is not on chain
+type NonceOrphan struct {
+	Nonce       uint64 `json:"nonce"`
+	FromChainID string `json:"fromChainID"`
+	TxID        string `json:"txid"`
+	LogIndex    int    `json:"logIndex"`
+	SwapTx      string `json:"swaptx"`
+	Timestamp   int64  `json:"timestamp"`
+}
+
+// HasProblem has nonce gaps or orphans
+func (r *NonceAuditReport) HasProblem() bool {
+	return len(r.Gaps) > 0 || len(r.Orphans) > 0
+}
+
+// GetGap get nonce gap
+func (r *NonceAuditReport) GetGap(nonce uint64) *NonceGap {
+	for _, gap := range r.Gaps {
+		if gap.Nonce == nonce {
+			return gap
+		}
+	}
+	return nil
+}
+
+func getNonceAuditBridge(chainID string) (nonceAuditBridge, error) {
+	bridge := router.GetBridgeByChainID(chainID)
+	if bridge == nil {
+		return nil, tokens.ErrNoBridgeForChainID
+	}
+	auditBridge, ok := bridge.(nonceAuditBridge)
+	if !ok {
+		return nil, errNotNonceAuditBridge
+	}
+	return auditBridge, nil
+}
+
+// StartNonceAuditJob nonce audit job
+func StartNonceAuditJob() {
+	logWorker("nonceaudit", "start router nonce audit job")
+
+	mongodb.MgoWaitGroup.Add(1)
+	go doNonceAuditJob()
+}
+
+func doNonceAuditJob() {
+	defer mongodb.MgoWaitGroup.Done()
+	for {
+		for _, chainID := range router.AllChainIDs {
+			if utils.IsCleanuping() {
+				logWorker("nonceaudit", "stop router nonce audit job")
+				return
+			}
+			reports, err := AuditSwapNonce(chainID.String(), "")
+			if err != nil {
+				if !errors.Is(err, errNotNonceAuditBridge) {
+					logWorkerError("nonceaudit", "audit swap nonce failed", err, "chainID", chainID)
+				}
+				continue
+			}
+			for _, report := range reports {
+				if report.HasProblem() {
+					logWorkerWarn("nonceaudit", "found nonce problems", "chainID", report.ChainID, "mpc", report.MPC,
+						"latestNonce", report.LatestNonce, "pendingNonce", report.PendingNonce, "nextSwapNonce", report.NextSwapNonce,
+						"gaps", len(report.Gaps), "orphans", len(report.Orphans))
+				}
+			}
+		}
+		if utils.IsCleanuping() {
+			logWorker("nonceaudit", "stop router nonce audit job")
+			return
+		}
+		restInJob(restIntervalInNonceAuditJob)
+	}
+}
+
+// AuditSwapNonce audit swap nonce of mpc on chain (all mpc if mpc is empty)
+func AuditSwapNonce(chainID, mpc string) ([]*NonceAuditReport, error) {
+	bridge, err := getNonceAuditBridge(chainID)
+	if err != nil {
+		return nil, err
+	}
+	var accounts []string
+	if mpc != "" {
+		accounts = []string{mpc}
+	} else {
+		accounts = bridge.GetSwapNonceAccounts()
+	}
+	reports := make([]*NonceAuditReport, 0, len(accounts))
+	for _, account := range accounts {
+		report, err := auditSwapNonce(bridge, chainID, account)
+		if err != nil {
+			return nil, err
+		}
+		reports = append(reports, report)
+	}
+	return reports, nil
+}
+
+//nolint:gocyclo // ok
+func auditSwapNonce(bridge nonceAuditBridge, chainID, mpc string) (*NonceAuditReport, error) {
+	mpc = strings.ToLower(mpc)
+	latestNonce, err := bridge.GetPoolNonce(mpc, "latest")
+	if err != nil {
+		return nil, fmt.Errorf("get latest nonce failed, %w", err)
+	}
+	pendingNonce, err := bridge.GetPoolNonce(mpc, "pending")
+	if err != nil {
+		return nil, fmt.Errorf("get pending nonce failed, %w", err)
+	}
+	nextSwapNonce, err := storage.Get().FindNextSwapNonce(chainID, mpc)
+	if err != nil && !errors.Is(err, mongodb.ErrItemNotFound) {
+		return nil, err
+	}
+
+	report := &NonceAuditReport{
+		ChainID:       chainID,
+		MPC:           mpc,
+		LatestNonce:   latestNonce,
+		PendingNonce:  pendingNonce,
+		NextSwapNonce: nextSwapNonce,
+		Timestamp:     now(),
+	}
+
+	if mongodb.HasClient() {
+		report.Fills, err = mongodb.FindNonceGapFills(chainID, mpc, maxNonceGapFillsInReport)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if nextSwapNonce > latestNonce {
+		filled := make(map[uint64]struct{}, len(report.Fills))
+		for _, fill := range report.Fills {
+			filled[fill.Nonce] = struct{}{}
+		}
+		endNonce := nextSwapNonce
+		if endNonce-latestNonce > maxNonceAuditRange {
+			endNonce = latestNonce + maxNonceAuditRange
+		}
+		results, errf := storage.Get().FindRouterSwapResultsWithNonceRange(chainID, mpc, latestNonce, endNonce)
+		if errf != nil {
+			return nil, errf
+		}
+		allocated := make(map[uint64]*mongodb.MgoSwapResult, len(results))
+		for _, res := range results {
+			allocated[res.SwapNonce] = res
+		}
+		graceTime := getSepTimeInFind(nonceGapGraceTime)
+		for nonce := latestNonce; nonce < endNonce; nonce++ {
+			if _, exist := filled[nonce]; exist {
+				continue
+			}
+			res, exist := allocated[nonce]
+			switch {
+			case !exist && nonce < pendingNonce:
+				// held by a tx in pool, filling it will replace that tx
+			case !exist:
+				report.Gaps = append(report.Gaps, &NonceGap{
+					Nonce:  nonce,
+					Reason: NonceGapUnallocated,
+				})
+			case nonce == pendingNonce && res.Timestamp < graceTime:
+				report.Gaps = append(report.Gaps, &NonceGap{
+					Nonce:       nonce,
+					Reason:      NonceGapNotInPool,
+					FromChainID: res.FromChainID,
+					TxID:        res.TxID,
+					LogIndex:    res.LogIndex,
+					SwapTx:      res.SwapTx,
+				})
+			}
+		}
+	}
+
+	orphans, err := storage.Get().FindRouterSwapResultsWithPassedNonce(chainID, mpc, latestNonce)
+	if err != nil {
+		return nil, err
+	}
+	for _, res := range orphans {
+		if txStatus := getSwapTxStatus(bridge, res); txStatus != nil && txStatus.BlockHeight > 0 {
+			continue
+		}
+		report.Orphans = append(report.Orphans, &NonceOrphan{
+			Nonce:       res.SwapNonce,
+			FromChainID: res.FromChainID,
+			TxID:        res.TxID,
+			LogIndex:    res.LogIndex,
+			SwapTx:      res.SwapTx,
+			Timestamp:   res.Timestamp,
+		})
+	}
+
+	return report, nil
+}
+
+// FillSwapNonceGap fill nonce gap of mpc with zero value self transfer (approved by admin)
+func FillSwapNonceGap(chainID, mpc string, nonce uint64) (txHash string, err error) {
+	bridge, err := getNonceAuditBridge(chainID)
+	if err != nil {
+		return "", err
+	}
+	if !router.IsRouterMPC(mpc) {
+		return "", fmt.Errorf("%v is not router mpc", mpc)
+	}
+	report, err := auditSwapNonce(bridge, chainID, mpc)
+	if err != nil {
+		return "", err
+	}
+	gap := report.GetGap(nonce)
+	if gap == nil {
+		return "", fmt.Errorf("nonce %v of %v is not a gap (latest %v, pending %v, next %v)",
+			nonce, mpc, report.LatestNonce, report.PendingNonce, report.NextSwapNonce)
+	}
+	if gap.Reason != NonceGapUnallocated {
+		return "", fmt.Errorf("nonce %v of %v is allocated to swap %v:%v:%v, replace or reswap it instead",
+			nonce, mpc, gap.FromChainID, gap.TxID, gap.LogIndex)
+	}
+	// prevent the nonce from being recycled and allocated to swap while filling
+	bridge.RemoveRecycleSwapNonce(report.MPC, nonce)
+	if err = checkNonceNotAllocated(chainID, report.MPC, nonce); err != nil {
+		return "", err
+	}
+
+	chainIDBig, _ := new(big.Int).SetString(chainID, 0)
+	args := &tokens.BuildTxArgs{
+		SwapArgs: tokens.SwapArgs{
+			Identifier:  params.GetIdentifier(),
+			SwapID:      fmt.Sprintf("noncegap:%v:%v", report.MPC, nonce),
+			SwapType:    tokens.NonceGapFillType,
+			FromChainID: chainIDBig,
+			ToChainID:   chainIDBig,
+		},
+		From:  report.MPC,
+		To:    report.MPC,
+		Value: big.NewInt(0),
+		Extra: &tokens.AllExtras{
+			EthExtra: &tokens.EthExtraArgs{
+				Nonce: &nonce,
+			},
+		},
+	}
+	rawTx, err := bridge.BuildRawTransaction(args)
+	if err != nil {
+		return "", err
+	}
+	signedTx, txHash, err := bridge.MPCSignTransaction(rawTx, args)
+	if err != nil {
+		return "", err
+	}
+	for i := 0; i < 3; i++ {
+		_, err = bridge.SendTransaction(signedTx)
+		if err == nil {
+			break
+		}
+		sleepSeconds(1)
+	}
+	if err != nil {
+		logWorkerError("nonceaudit", "send nonce gap fill tx failed", err, "chainID", chainID, "mpc", mpc, "nonce", nonce, "txHash", txHash)
+		return txHash, err
+	}
+	logWorker("nonceaudit", "send nonce gap fill tx success", "chainID", chainID, "mpc", mpc, "nonce", nonce,
+		"reason", gap.Reason, "fromChainID", gap.FromChainID, "txid", gap.TxID, "logIndex", gap.LogIndex, "txHash", txHash)
+
+	if !mongodb.HasClient() {
+		return txHash, nil
+	}
+	_ = mongodb.AddNonceGapFill(&mongodb.MgoNonceGapFill{
+		ChainID:   chainID,
+		MPC:       report.MPC,
+		Nonce:     nonce,
+		TxHash:    txHash,
+		Timestamp: now(),
+	})
+	return txHash, nil
+}
+
+func checkNonceNotAllocated(chainID, mpc string, nonce uint64) error {
+	results, err := storage.Get().FindRouterSwapResultsWithNonceRange(chainID, mpc, nonce, nonce+1)
+	if err != nil {
+		return err
+	}
+	if len(results) > 0 {
+		res := results[0]
+		return fmt.Errorf("nonce %v of %v is allocated to swap %v:%v:%v", nonce, mpc, res.FromChainID, res.TxID, res.LogIndex)
+	}
+	return nil
+}
+
+// verifyNonceGapFill verify nonce gap fill tx before accepting the sign (called by oracle)
+func verifyNonceGapFill(keyID string, msgHash []string, args *tokens.BuildTxArgs) error {
+	if args.FromChainID == nil || args.ToChainID == nil || args.FromChainID.Cmp(args.ToChainID) != 0 {
+		return fmt.Errorf("%w: tx with different chain ids", errInvalidNonceGapFill)
+	}
+	if !common.IsEqualIgnoreCase(args.From, args.To) {
+		return fmt.Errorf("%w: tx is not self transfer", errInvalidNonceGapFill)
+	}
+	chainID := args.ToChainID.String()
+	bridge, err := getNonceAuditBridge(chainID)
+	if err != nil {
+		return err
+	}
+	nonce := args.GetTxNonce()
+	pendingNonce, err := bridge.GetPoolNonce(args.From, "pending")
+	if err != nil {
+		return err
+	}
+	if nonce < pendingNonce {
+		return fmt.Errorf("%w: tx nonce %v is lower than pending nonce %v", errInvalidNonceGapFill, nonce, pendingNonce)
+	}
+	if swapKey := getAcceptLedgerNonceSwap(chainID, args.From, nonce); swapKey != "" {
+		return fmt.Errorf("%w: tx nonce %v is allocated to swap %v", errInvalidNonceGapFill, nonce, swapKey)
+	}
+
+	ctx := []interface{}{
+		"keyID", keyID,
+		"swapType", args.SwapType.String(),
+		"chainID", chainID,
+		"swapID", args.SwapID,
+		"mpc", args.From,
+		"nonce", nonce,
+	}
+
+	buildTxArgs := &tokens.BuildTxArgs{
+		SwapArgs: tokens.SwapArgs{
+			Identifier:  params.GetIdentifier(),
+			SwapID:      args.SwapID,
+			SwapType:    tokens.NonceGapFillType,
+			FromChainID: args.FromChainID,
+			ToChainID:   args.ToChainID,
+		},
+		From:  args.From,
+		To:    args.From,
+		Extra: args.Extra,
+	}
+	rawTx, err := bridge.BuildRawTransaction(buildTxArgs)
+	if err != nil {
+		logWorkerError("accept", "build nonce gap fill tx failed", err, ctx...)
+		return err
+	}
+	err = bridge.VerifyMsgHash(rawTx, msgHash)
+	if err != nil {
+		logWorkerError("accept", "verify nonce gap fill message hash failed", err, ctx...)
+		return err
+	}
+	logWorker("accept", "verify nonce gap fill message hash success", ctx...)
+	return nil
+}
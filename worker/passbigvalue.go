@@ -7,6 +7,7 @@ import (
 	"github.com/anyswap/CrossChain-Router/v3/mongodb"
 	"github.com/anyswap/CrossChain-Router/v3/params"
 	"github.com/anyswap/CrossChain-Router/v3/router"
+	"github.com/anyswap/CrossChain-Router/v3/storage"
 	"github.com/anyswap/CrossChain-Router/v3/tokens"
 )
 
@@ -70,7 +71,7 @@ func doPassBigValueJob() {
 func findBigValRouterSwaps() ([]*mongodb.MgoSwap, error) {
 	status := mongodb.TxWithBigValue
 	septime := getSepTimeInFind(maxPassBigValueLifetime)
-	return mongodb.FindRouterSwapsWithStatus(status, septime)
+	return storage.Get().FindRouterSwapsWithStatus(status, septime)
 }
 
 func processPassBigValRouterSwap(swap *mongodb.MgoSwap) (err error) {
@@ -88,7 +89,7 @@ func processPassBigValRouterSwap(swap *mongodb.MgoSwap) (err error) {
 	txid := swap.TxID
 	logIndex := swap.LogIndex
 
-	_, err = mongodb.FindRouterSwapResult(fromChainID, txid, logIndex)
+	_, err = storage.Get().FindRouterSwapResult(fromChainID, txid, logIndex)
 	if err == nil {
 		return nil // result exist
 	}
@@ -107,7 +108,7 @@ func processPassBigValRouterSwap(swap *mongodb.MgoSwap) (err error) {
 		return err
 	}
 
-	err = mongodb.RouterAdminPassBigValue(fromChainID, txid, logIndex)
+	err = storage.RouterAdminPassBigValue(fromChainID, txid, logIndex, "passbigvalue")
 	if err != nil {
 		return err
 	}
@@ -0,0 +1,181 @@
+package worker
+
+import (
+	"strings"
+	"time"
+
+	"github.com/anyswap/CrossChain-Router/v3/cmd/utils"
+	"github.com/anyswap/CrossChain-Router/v3/common"
+	"github.com/anyswap/CrossChain-Router/v3/leveldb"
+	"github.com/anyswap/CrossChain-Router/v3/mongodb"
+	"github.com/anyswap/CrossChain-Router/v3/params"
+	"github.com/anyswap/CrossChain-Router/v3/rpc/client"
+	"github.com/anyswap/CrossChain-Router/v3/tokens"
+)
+
+const (
+	reconcileCursorKey     = "ledger-reconcile-cursor"
+	reconcileCursorSwapKey = "ledger-reconcile-cursor-swap"
+
+	reconcilePageSize = 100
+	// wait accept records of swap txs to be saved
+	reconcileDelay = int64(600) // seconds
+)
+
+// reconcileSwapResult swap result returned by server api 'swap.GetSwapResultsSince'
+type reconcileSwapResult struct {
+	SwapType    uint32   `json:"swaptype"`
+	TxID        string   `json:"txid"`
+	LogIndex    int      `json:"logIndex"`
+	FromChainID string   `json:"fromChainID"`
+	ToChainID   string   `json:"toChainID"`
+	SwapTx      string   `json:"swaptx"`
+	SwapNonce   uint64   `json:"swapnonce"`
+	Status      uint32   `json:"status"`
+	Timestamp   int64    `json:"timestamp"`
+	OldSwapTxs  []string `json:"oldswaptxs"`
+}
+
+func startReconcileJob() {
+	interval := params.GetRouterOracleConfig().ReconcileInterval
+	if interval == 0 {
+		return
+	}
+	logWorker("reconcile", "start reconcile job", "interval", interval)
+	for {
+		if utils.IsCleanuping() {
+			return
+		}
+		reconcileSwapResults()
+		restInJob(time.Duration(interval) * time.Second)
+	}
+}
+
+// reconcile cursor is the update time and key of the last checked swap result
+func getReconcileCursor() (timestamp int64, swapKey string) {
+	data, err := lvldbHandle.Get([]byte(reconcileCursorKey))
+	if err != nil {
+		if !leveldb.IsNotFoundErr(err) {
+			logWorkerError("reconcile", "get reconcile cursor failed", err)
+		}
+		return now() - reconcileDelay, ""
+	}
+	keyData, err := lvldbHandle.Get([]byte(reconcileCursorSwapKey))
+	if err != nil && !leveldb.IsNotFoundErr(err) {
+		logWorkerError("reconcile", "get reconcile cursor failed", err)
+	}
+	return bytesToInt64(data), string(keyData)
+}
+
+func saveReconcileCursor(timestamp int64, swapKey string) error {
+	batch := lvldbHandle.NewBatch()
+	_ = batch.Put([]byte(reconcileCursorKey), int64ToBytes(timestamp))
+	_ = batch.Put([]byte(reconcileCursorSwapKey), []byte(swapKey))
+	return batch.Write()
+}
+
+// reconcileSwapResults check every swap tx of server is agreed by this oracle
+func reconcileSwapResults() {
+	url := params.GetRouterOracleConfig().ServerAPIAddress
+	cursor, cursorKey := getReconcileCursor()
+	maxTime := now() - reconcileDelay
+	checked, flagged := 0, 0
+
+PAGE_LOOP:
+	for cursor < maxTime {
+		if utils.IsCleanuping() {
+			break
+		}
+		var results []*reconcileSwapResult
+		args := map[string]interface{}{
+			"since":    cursor,
+			"afterKey": cursorKey,
+			"limit":    reconcilePageSize,
+		}
+		err := client.RPCPostWithTimeout(60, &results, url, "swap.GetSwapResultsSince", args)
+		if err != nil {
+			logWorkerError("reconcile", "get swap results from server failed", err, "since", cursor, "afterKey", cursorKey)
+			break
+		}
+		for _, res := range results {
+			if res.Timestamp >= maxTime {
+				cursor, cursorKey = maxTime, ""
+				break PAGE_LOOP
+			}
+			checked++
+			flagged += reconcileSwapTxs(res)
+			cursor = res.Timestamp
+			cursorKey = mongodb.GetRouterSwapKey(res.FromChainID, res.TxID, res.LogIndex)
+		}
+		if len(results) < reconcilePageSize {
+			// results updated later have timestamp not less than max time
+			cursor, cursorKey = maxTime, ""
+			break
+		}
+	}
+
+	if err := saveReconcileCursor(cursor, cursorKey); err != nil {
+		logWorkerError("reconcile", "save reconcile cursor failed", err)
+	}
+	logWorker("reconcile", "reconcile swap results finished", "checked", checked, "flagged", flagged, "cursor", cursor, "cursorKey", cursorKey)
+}
+
+func reconcileSwapTxs(res *reconcileSwapResult) (flagged int) {
+	if res.SwapTx == "" {
+		return 0
+	}
+	// swaps signed by sign groups without this oracle are unknown to it
+	swapKey := mongodb.GetRouterSwapKey(res.FromChainID, res.TxID, res.LogIndex)
+	if !hasAcceptLedgerAgree(swapKey) {
+		return 0
+	}
+	fromChainID, err := common.GetBigIntFromStr(res.FromChainID)
+	if err != nil {
+		return 0
+	}
+	args := &tokens.BuildTxArgs{
+		SwapArgs: tokens.SwapArgs{
+			SwapID:      res.TxID,
+			LogIndex:    res.LogIndex,
+			FromChainID: fromChainID,
+			SwapType:    tokens.SwapType(res.SwapType),
+		},
+	}
+	records := FindAcceptRecords(args)
+	// accept records are only saved for eth like chains
+	if len(records) == 0 {
+		return 0
+	}
+	prefixLen := len(getSwapKeyPrefix(args))
+
+	swapTxs := append([]string{res.SwapTx}, res.OldSwapTxs...)
+	for _, swapTx := range swapTxs {
+		if swapTx == "" || isSwapTxAccepted(records, prefixLen, swapTx) {
+			continue
+		}
+		flag := &AcceptLedgerFlag{
+			SwapKey:   swapKey,
+			ToChainID: res.ToChainID,
+			SwapTx:    swapTx,
+			SwapNonce: res.SwapNonce,
+			Status:    res.Status,
+			Timestamp: res.Timestamp,
+			FlagTime:  now(),
+		}
+		logWorkerWarn("reconcile", "found swap tx not agreed by this oracle", "swapKey", flag.SwapKey, "toChainID", flag.ToChainID, "swaptx", swapTx, "nonce", res.SwapNonce)
+		if err := addAcceptLedgerFlag(flag); err != nil {
+			logWorkerError("reconcile", "save reconcile flag failed", err, "swaptx", swapTx)
+		}
+		flagged++
+	}
+	return flagged
+}
+
+func isSwapTxAccepted(records map[string]int64, prefixLen int, swapTx string) bool {
+	for key := range records {
+		if strings.EqualFold(key[prefixLen:], swapTx) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,322 @@
+package worker
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/anyswap/CrossChain-Router/v3/mongodb"
+	"github.com/anyswap/CrossChain-Router/v3/router"
+	"github.com/anyswap/CrossChain-Router/v3/storage"
+	"github.com/anyswap/CrossChain-Router/v3/tokens"
+	"github.com/anyswap/CrossChain-Router/v3/types"
+)
+
+const (
+	recoverActor = "recover"
+	recoverMemo  = "recovered from chain logs"
+)
+
+// RecoverRange block range [Start, End] of chain to scan in recovery
+type RecoverRange struct {
+	ChainID string
+	Start   uint64
+	End     uint64
+}
+
+// RecoverArgs args of recovering swaps from chain logs
+type RecoverArgs struct {
+	Ranges    []*RecoverRange
+	BatchSize uint64 // count of blocks in each log query
+	DryRun    bool   // only match and report without writing database
+}
+
+// RecoverReport report of recovering swaps from chain logs
+type RecoverReport struct {
+	Recovered     int                 `json:"recovered"`     // swaps matched with swapin logs and rebuilt
+	Existing      int                 `json:"existing"`      // swaps already in database
+	Unmatched     []*UnmatchedSwap    `json:"unmatched"`     // source swaps without swapin log found, need review
+	OrphanSwapins []*tokens.SwapinLog `json:"orphanSwapins"` // swapin logs without source swap found
+}
+
+// UnmatchedSwap source swap without swapin log found
+type UnmatchedSwap struct {
+	FromChainID string `json:"fromChainID"`
+	TxID        string `json:"txid"`
+	LogIndex    int    `json:"logIndex"`
+	ToChainID   string `json:"toChainID,omitempty"`
+	TokenID     string `json:"tokenID,omitempty"`
+	Bind        string `json:"bind,omitempty"`
+	Value       string `json:"value,omitempty"`
+	Memo        string `json:"memo,omitempty"` // verify error
+}
+
+type recoveredSwap struct {
+	swapInfo *tokens.SwapTxInfo
+	swapin   *tokens.SwapinLog
+}
+
+// RecoverRouterSwaps rebuild swaps and swap results after database is lost.
+// it scans source swapout logs and dest swapin logs (with source tx hash)
+// of router contracts in the block ranges, and matches them to rebuild
+// swaps in processed status and swap results in stable status with swaptx.
+// source swaps without matched swapin are only reported, as their swapin
+// may be out of the scanned ranges, and registering them risks double swap.
+func RecoverRouterSwaps(args *RecoverArgs) (*RecoverReport, error) {
+	if !args.DryRun && !storage.HasStorage() {
+		return nil, errors.New("storage is not initialized")
+	}
+	var chainIDs []string
+	swapoutTxs := make(map[string][]string) // key is chainID
+	scannedTxs := make(map[string]struct{})
+	swapins := make(map[string][]*tokens.SwapinLog)
+	var allSwapins []*tokens.SwapinLog
+	for _, r := range args.Ranges {
+		txs, logs, err := scanRouterLogs(r, args.BatchSize)
+		if err != nil {
+			return nil, err
+		}
+		if _, exist := swapoutTxs[r.ChainID]; !exist {
+			chainIDs = append(chainIDs, r.ChainID)
+			swapoutTxs[r.ChainID] = nil
+		}
+		for _, txHash := range txs {
+			if _, exist := scannedTxs[r.ChainID+":"+txHash]; !exist {
+				scannedTxs[r.ChainID+":"+txHash] = struct{}{}
+				swapoutTxs[r.ChainID] = append(swapoutTxs[r.ChainID], txHash)
+			}
+		}
+		for _, swapin := range logs {
+			key := getSwapinMatchKey(swapin.FromChainID.String(), swapin.SwapID)
+			if !containsSwapinLog(swapins[key], swapin) {
+				swapins[key] = append(swapins[key], swapin)
+				allSwapins = append(allSwapins, swapin)
+			}
+		}
+	}
+
+	report := &RecoverReport{}
+	var recovered []*recoveredSwap
+	matched := make(map[*tokens.SwapinLog]struct{})
+	for _, chainID := range chainIDs {
+		bridge := router.GetBridgeByChainID(chainID)
+		for _, txHash := range swapoutTxs[chainID] {
+			registerArgs := &tokens.RegisterArgs{SwapType: tokens.GetRouterSwapType()}
+			swapInfos, errs := bridge.RegisterSwap(txHash, registerArgs)
+			for i, swapInfo := range swapInfos {
+				if swapInfo.FromChainID == nil || swapInfo.ToChainID == nil {
+					logWorkerWarn("recover", "skip wrong swapout", "chainid", chainID, "txid", txHash, "logIndex", swapInfo.LogIndex, "err", errs[i])
+					continue
+				}
+				key := getSwapinMatchKey(swapInfo.FromChainID.String(), swapInfo.Hash)
+				swapin := matchSwapinLog(swapInfo, swapins[key], matched)
+				if swapin == nil {
+					report.Unmatched = append(report.Unmatched, newUnmatchedSwap(swapInfo, errs[i]))
+					continue
+				}
+				matched[swapin] = struct{}{}
+				recovered = append(recovered, &recoveredSwap{swapInfo: swapInfo, swapin: swapin})
+			}
+		}
+	}
+	for _, swapin := range allSwapins {
+		if _, exist := matched[swapin]; !exist {
+			report.OrphanSwapins = append(report.OrphanSwapins, swapin)
+		}
+	}
+
+	for _, rs := range recovered {
+		swapInfo := rs.swapInfo
+		fromChainID := swapInfo.FromChainID.String()
+		if storage.HasStorage() {
+			if res, _ := storage.Get().FindRouterSwapResultAuto(fromChainID, swapInfo.Hash, swapInfo.LogIndex); res != nil {
+				report.Existing++
+				continue
+			}
+		}
+		if args.DryRun {
+			logWorker("recover", "match swap (dry run)", "chainid", fromChainID, "txid", swapInfo.Hash, "logIndex", swapInfo.LogIndex, "swaptx", rs.swapin.TxHash)
+			report.Recovered++
+			continue
+		}
+		err := addRecoveredSwap(rs)
+		if errors.Is(err, mongodb.ErrItemIsDup) {
+			report.Existing++
+			continue
+		}
+		if err != nil {
+			return report, err
+		}
+		report.Recovered++
+	}
+	return report, nil
+}
+
+func scanRouterLogs(r *RecoverRange, batchSize uint64) (swapoutTxs []string, swapins []*tokens.SwapinLog, err error) {
+	bridge := router.GetBridgeByChainID(r.ChainID)
+	if bridge == nil {
+		return nil, nil, fmt.Errorf("%w %v", tokens.ErrNoBridgeForChainID, r.ChainID)
+	}
+	scanner, ok := bridge.(tokens.RouterLogScanner)
+	if !ok {
+		return nil, nil, fmt.Errorf("chain %v does not support scanning router logs", r.ChainID)
+	}
+	if batchSize == 0 {
+		batchSize = 1
+	}
+	for start := r.Start; start <= r.End; start += batchSize {
+		end := start + batchSize - 1
+		if end > r.End {
+			end = r.End
+		}
+		txs, logs, errf := scanner.ScanRouterLogs(start, end)
+		if errf != nil {
+			return nil, nil, fmt.Errorf("scan chain %v blocks [%v, %v] failed: %w", r.ChainID, start, end, errf)
+		}
+		logWorker("recover", "scan router logs", "chainid", r.ChainID, "start", start, "end", end, "swapouts", len(txs), "swapins", len(logs))
+		swapoutTxs = append(swapoutTxs, txs...)
+		swapins = append(swapins, logs...)
+	}
+	return swapoutTxs, swapins, nil
+}
+
+func getSwapinMatchKey(fromChainID, txid string) string {
+	return strings.ToLower(fromChainID + ":" + txid)
+}
+
+// match swapin to the same dest chain, bind address and multichain token exactly.
+// swaps in one tx with the same dest are swapped in order of log index.
+func matchSwapinLog(swapInfo *tokens.SwapTxInfo, swapins []*tokens.SwapinLog, matched map[*tokens.SwapinLog]struct{}) *tokens.SwapinLog {
+	if swapInfo.ERC20SwapInfo == nil {
+		return nil
+	}
+	toChainID := swapInfo.ToChainID.String()
+	multichainToken := router.GetCachedMultichainToken(swapInfo.ERC20SwapInfo.TokenID, toChainID)
+	if multichainToken == "" {
+		return nil
+	}
+	for _, swapin := range swapins {
+		if _, exist := matched[swapin]; exist {
+			continue
+		}
+		if swapin.ToChainID.String() == toChainID &&
+			strings.EqualFold(swapin.To, swapInfo.Bind) &&
+			strings.EqualFold(swapin.Token, multichainToken) {
+			return swapin
+		}
+	}
+	return nil
+}
+
+// the same log may be scanned twice if the ranges are overlapped
+func containsSwapinLog(swapins []*tokens.SwapinLog, swapin *tokens.SwapinLog) bool {
+	for _, s := range swapins {
+		if s.TxHash == swapin.TxHash && s.LogIndex == swapin.LogIndex {
+			return true
+		}
+	}
+	return false
+}
+
+func newUnmatchedSwap(swapInfo *tokens.SwapTxInfo, verifyErr error) *UnmatchedSwap {
+	swap := &UnmatchedSwap{
+		FromChainID: swapInfo.FromChainID.String(),
+		TxID:        swapInfo.Hash,
+		LogIndex:    swapInfo.LogIndex,
+		ToChainID:   swapInfo.ToChainID.String(),
+		TokenID:     swapInfo.GetTokenID(),
+		Bind:        swapInfo.Bind,
+	}
+	if swapInfo.Value != nil {
+		swap.Value = swapInfo.Value.String()
+	}
+	if verifyErr != nil {
+		swap.Memo = verifyErr.Error()
+	}
+	return swap
+}
+
+func addRecoveredSwap(rs *recoveredSwap) error {
+	swapInfo, swapin := rs.swapInfo, rs.swapin
+	valueStr := "0"
+	if swapInfo.Value != nil {
+		valueStr = swapInfo.Value.String()
+	}
+	// init time is the source tx time, so the recovered swaps are in place in paging
+	initTime := int64(swapInfo.Timestamp) * 1000
+	swap := &mongodb.MgoSwap{
+		SwapType:    uint32(swapInfo.SwapType),
+		TxID:        swapInfo.Hash,
+		TxTo:        swapInfo.TxTo,
+		From:        swapInfo.From,
+		Bind:        swapInfo.Bind,
+		Value:       valueStr,
+		LogIndex:    swapInfo.LogIndex,
+		FromChainID: swapInfo.FromChainID.String(),
+		ToChainID:   swapInfo.ToChainID.String(),
+		Status:      mongodb.TxProcessed,
+		InitTime:    initTime,
+		Timestamp:   now(),
+		Memo:        recoverMemo,
+	}
+	swap.SwapInfo = mongodb.ConvertToSwapInfo(&swapInfo.SwapInfo)
+
+	swapResult := &mongodb.MgoSwapResult{
+		SwapType:    uint32(swapInfo.SwapType),
+		TxID:        swapInfo.Hash,
+		TxTo:        swapInfo.TxTo,
+		TxHeight:    swapInfo.Height,
+		TxTime:      swapInfo.Timestamp,
+		From:        swapInfo.From,
+		To:          swapInfo.To,
+		Bind:        swapInfo.Bind,
+		Value:       valueStr,
+		LogIndex:    swapInfo.LogIndex,
+		FromChainID: swapInfo.FromChainID.String(),
+		ToChainID:   swapInfo.ToChainID.String(),
+		SwapTx:      swapin.TxHash,
+		SwapHeight:  swapin.BlockNumber,
+		SwapValue:   swapin.Amount.String(),
+		Status:      mongodb.MatchTxStable,
+		InitTime:    initTime,
+		Timestamp:   now(),
+		Memo:        recoverMemo,
+	}
+	swapResult.SwapInfo = swap.SwapInfo
+	fillRecoveredSwapTxInfo(swapResult)
+
+	// add swap result first, so a halfway failure will not leave
+	// a processed swap without result which will never be swapped.
+	err := storage.Get().AddRouterSwapResult(swapResult)
+	if err != nil {
+		logWorkerError("recover", "add swap result failed", err, "chainid", swap.FromChainID, "txid", swap.TxID, "logIndex", swap.LogIndex)
+		return err
+	}
+	err = storage.Get().AddRouterSwap(swap)
+	if err != nil && !errors.Is(err, mongodb.ErrItemIsDup) {
+		logWorkerError("recover", "add swap failed", err, "chainid", swap.FromChainID, "txid", swap.TxID, "logIndex", swap.LogIndex)
+		return err
+	}
+	logWorker("recover", "recover swap success", "chainid", swap.FromChainID, "txid", swap.TxID, "logIndex", swap.LogIndex, "swaptx", swapResult.SwapTx, "actor", recoverActor)
+	return nil
+}
+
+// fill mpc, nonce and swap time of swaptx if possible (eth-like)
+func fillRecoveredSwapTxInfo(res *mongodb.MgoSwapResult) {
+	bridge := router.GetBridgeByChainID(res.ToChainID)
+	if bridge == nil {
+		return
+	}
+	if txStatus, err := bridge.GetTransactionStatus(res.SwapTx); err == nil && txStatus != nil {
+		res.MPC = txStatus.Sender
+		res.SwapTime = txStatus.BlockTime
+	}
+	if tx, err := bridge.GetTransaction(res.SwapTx); err == nil {
+		if etx, ok := tx.(*types.RPCTransaction); ok {
+			res.SwapNonce = etx.GetAccountNonce()
+			if etx.From != nil {
+				res.MPC = etx.From.LowerHex()
+			}
+		}
+	}
+}
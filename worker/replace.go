@@ -11,6 +11,7 @@ import (
 	"github.com/anyswap/CrossChain-Router/v3/mpc"
 	"github.com/anyswap/CrossChain-Router/v3/params"
 	"github.com/anyswap/CrossChain-Router/v3/router"
+	"github.com/anyswap/CrossChain-Router/v3/storage"
 	"github.com/anyswap/CrossChain-Router/v3/tokens"
 )
 
@@ -45,6 +46,7 @@ func StartReplaceJob() {
 func doReplaceJob(toChainID string) {
 	defer mongodb.MgoWaitGroup.Done()
 	logWorker("replace", "start router swap replace job", "toChainID", toChainID)
+	trigger := getJobTrigger(&replaceTriggers, toChainID)
 	for {
 		res, err := findRouterSwapResultToReplace(toChainID)
 		if err != nil {
@@ -64,13 +66,13 @@ func doReplaceJob(toChainID string) {
 			logWorker("replace", "stop router swap replace job", "toChainID", toChainID)
 			return
 		}
-		restInJob(restIntervalInReplaceSwapJob)
+		trigger.wait(restIntervalInReplaceSwapJob)
 	}
 }
 
 func findRouterSwapResultToReplace(toChainID string) ([]*mongodb.MgoSwapResult, error) {
 	septime := getSepTimeInFind(maxReplaceSwapLifetime)
-	return mongodb.FindRouterSwapResultsToReplace(toChainID, septime)
+	return storage.Get().FindRouterSwapResultsToReplace(toChainID, septime)
 }
 
 func processRouterSwapReplace(res *mongodb.MgoSwapResult) error {
@@ -170,11 +172,23 @@ func ReplaceRouterSwap(res *mongodb.MgoSwapResult, gasPrice *big.Int, isManual b
 			Sequence:   &nonce,
 			ReplaceNum: replaceNum,
 		},
+		InitTime: res.InitTime,
 	}
 	args.SwapInfo, err = mongodb.ConvertFromSwapInfo(&swap.SwapInfo)
 	if err != nil {
 		return err
 	}
+	if feeSetter, ok := resBridge.(tokens.ReplaceFeeSetter); ok {
+		oldSwapTxs := res.OldSwapTxs
+		if len(oldSwapTxs) == 0 {
+			oldSwapTxs = []string{res.SwapTx}
+		}
+		err = feeSetter.SetReplaceTxFees(args, oldSwapTxs)
+		if err != nil {
+			logWorkerError("replaceSwap", "set replace tx fees failed", err, "chainID", res.ToChainID, "txid", txid, "logIndex", res.LogIndex, "nonce", nonce)
+			return err
+		}
+	}
 	rawTx, err := resBridge.BuildRawTransaction(args)
 	if err != nil {
 		logWorkerError("replaceSwap", "build tx failed", err, "chainID", res.ToChainID, "txid", txid, "logIndex", res.LogIndex)
@@ -198,23 +212,24 @@ func signAndSendReplaceTx(resBridge tokens.IBridge, rawTx interface{}, args *tok
 	txid := res.TxID
 	logIndex := res.LogIndex
 
-	err = mongodb.UpdateRouterOldSwapTxs(fromChainID, txid, logIndex, txHash)
+	err = storage.Get().UpdateRouterOldSwapTxs(fromChainID, txid, logIndex, txHash)
 	if err != nil {
 		return
 	}
+	addSwapTxAttempt(args, txHash)
 
 	sentTxHash, err := sendSignedTransaction(resBridge, signedTx, args)
 	if err == nil && txHash != sentTxHash {
 		logWorkerError("replaceSwap", "send tx success but with different hash", errSendTxWithDiffHash,
 			"fromChainID", fromChainID, "toChainID", res.ToChainID, "txid", txid, "nonce", res.SwapNonce,
 			"logIndex", logIndex, "txHash", txHash, "sentTxHash", sentTxHash)
-		_ = mongodb.UpdateRouterOldSwapTxs(fromChainID, txid, logIndex, sentTxHash)
+		_ = storage.Get().UpdateRouterOldSwapTxs(fromChainID, txid, logIndex, sentTxHash)
 	}
 }
 
 func verifyReplaceSwap(res *mongodb.MgoSwapResult, isManual bool) (*mongodb.MgoSwap, error) {
 	fromChainID, txid, logIndex := res.FromChainID, res.TxID, res.LogIndex
-	swap, err := mongodb.FindRouterSwap(fromChainID, txid, logIndex)
+	swap, err := storage.Get().FindRouterSwap(fromChainID, txid, logIndex)
 	if err != nil {
 		return nil, err
 	}
@@ -259,11 +274,11 @@ func checkIfSwapNonceHasPassed(bridge tokens.IBridge, res *mongodb.MgoSwapResult
 		return nil
 	}
 	if nonce > res.SwapNonce && res.SwapNonce > 0 {
-		var iden string
+		var iden, actor string
 		if isReplace {
-			iden = "[replace]"
+			iden, actor = "[replace]", "replace"
 		} else {
-			iden = "[stable]"
+			iden, actor = "[stable]", "stable"
 		}
 		fromChainID, txid, logIndex := res.FromChainID, res.TxID, res.LogIndex
 		noncePassedInterval := params.GetNoncePassedConfirmInterval(res.FromChainID)
@@ -280,7 +295,7 @@ func checkIfSwapNonceHasPassed(bridge tokens.IBridge, res *mongodb.MgoSwapResult
 					return nil
 				}
 			}
-			oldRes, errf := mongodb.FindRouterSwapResult(fromChainID, txid, logIndex)
+			oldRes, errf := storage.Get().FindRouterSwapResult(fromChainID, txid, logIndex)
 			if errf != nil {
 				return errf
 			}
@@ -290,7 +305,7 @@ func checkIfSwapNonceHasPassed(bridge tokens.IBridge, res *mongodb.MgoSwapResult
 			logWorker(iden, "mark swap result nonce passed",
 				"fromChainID", fromChainID, "txid", txid, "logIndex", logIndex,
 				"swaptime", res.Timestamp, "nowtime", now())
-			_ = markSwapResultFailed(fromChainID, txid, logIndex)
+			_ = markSwapResultFailed(fromChainID, txid, logIndex, actor)
 		}
 		if isReplace {
 			return fmt.Errorf("swap nonce (%v) is lower than latest nonce (%v)", res.SwapNonce, nonce)
@@ -1,11 +1,16 @@
 package worker
 
 import (
+	"encoding/json"
+	"errors"
 	"sync"
+	"sync/atomic"
 	"time"
 
+	"github.com/anyswap/CrossChain-Router/v3/admin"
 	"github.com/anyswap/CrossChain-Router/v3/mpc"
 	"github.com/anyswap/CrossChain-Router/v3/params"
+	"github.com/anyswap/CrossChain-Router/v3/router"
 	"github.com/anyswap/CrossChain-Router/v3/rpc/client"
 )
 
@@ -13,6 +18,9 @@ var (
 	reportStatStarter sync.Once
 
 	reportInterval = 120 * time.Second
+
+	// disagrees are reported by admin call signed with mpc user keystore
+	canReportDisagrees bool
 )
 
 // StartReportStatJob report stat job
@@ -22,6 +30,11 @@ func StartReportStatJob() {
 	}
 	reportStatStarter.Do(func() {
 		logWorker("reportstat", "start report stat job")
+		if err := loadReportKeyStore(); err != nil {
+			logWorkerWarn("reportstat", "load keystore failed, will not report disagrees", "err", err)
+		} else {
+			canReportDisagrees = true
+		}
 		go reportStat()
 	})
 }
@@ -41,6 +54,9 @@ func doReport() {
 		"enode":     mpc.GetMPCConfig(false).GetSelfEnode(),
 		"timestamp": timestamp,
 	}
+	for k, v := range getOracleStat() {
+		args[k] = v
+	}
 	url := params.GetRouterOracleConfig().ServerAPIAddress
 	var result string
 	var err error
@@ -54,5 +70,81 @@ func doReport() {
 		logWorkerWarn("reportstat", "report stat failed", "err", err)
 	} else {
 		logWorker("reportstat", "report stat success", "timestamp", timestamp)
+		reportDisagrees()
+	}
+}
+
+func loadReportKeyStore() error {
+	nodeCfg := params.GetRouterConfig().MPC.DefaultNode
+	if nodeCfg == nil || nodeCfg.KeystoreFile == nil || nodeCfg.PasswordFile == nil {
+		return errors.New("mpc keystore is not configed")
+	}
+	return admin.LoadKeyStore(*nodeCfg.KeystoreFile, *nodeCfg.PasswordFile)
+}
+
+// reportDisagrees report disagree reasons of swaps to server,
+// the mpc user of this oracle should be configed in 'OracleReporters' of server.
+func reportDisagrees() {
+	if !canReportDisagrees {
+		return
+	}
+	reports := takePendingDisagrees()
+	if len(reports) == 0 {
+		return
+	}
+	data, err := json.Marshal(reports)
+	if err != nil {
+		logWorkerWarn("reportstat", "marshal disagrees failed", "err", err)
+		return
+	}
+	rawTx, err := admin.Sign("reportdisagrees", []string{string(data)})
+	if err != nil {
+		logWorkerWarn("reportstat", "sign disagrees report failed", "err", err)
+		requeueDisagrees(reports)
+		return
+	}
+	url := params.GetRouterOracleConfig().ServerAPIAddress
+	var result string
+	err = client.RPCPostWithTimeout(20, &result, url, "swap.AdminCall", rawTx)
+	if err != nil {
+		logWorkerWarn("reportstat", "report disagrees failed", "count", len(reports), "err", err)
+		requeueDisagrees(reports)
+	} else {
+		logWorker("reportstat", "report disagrees success", "count", len(reports))
+	}
+}
+
+// getOracleStat get telemetry of this oracle
+func getOracleStat() map[string]interface{} {
+	stat := map[string]interface{}{
+		"version":        params.VersionWithMeta,
+		"configHash":     router.GetCurrentConfigSnapshot().Hash(),
+		"mpcConnected":   mpc.GetMPCConfig(false).CheckMPCNodeConnection() == nil,
+		"acceptQueue":    len(acceptInfoCh) + len(acceptInfoCh2) + len(acceptInfoCh3),
+		"acceptRoutines": atomic.LoadInt64(&curAcceptRoutines) + atomic.LoadInt64(&curAcceptRoutines2) + atomic.LoadInt64(&curAcceptRoutines3),
+		"agreeCount":     atomic.LoadUint64(&acceptAgreeCount),
+		"disagreeCount":  atomic.LoadUint64(&acceptDisagreeCount),
+	}
+	if fastmpcConfig := mpc.GetMPCConfig(true); fastmpcConfig != nil {
+		stat["fastMPCConnected"] = fastmpcConfig.CheckMPCNodeConnection() == nil
+	}
+	if highValueMPCConfig := mpc.GetHighValueMPCConfig(); highValueMPCConfig != nil {
+		stat["highValueMPCConnected"] = highValueMPCConfig.CheckMPCNodeConnection() == nil
+	}
+
+	chainHeights := make(map[string]uint64, len(router.AllChainIDs))
+	for _, chainID := range router.AllChainIDs {
+		bridge := router.GetBridgeByChainID(chainID.String())
+		if bridge == nil {
+			continue
+		}
+		height, err := bridge.GetLatestBlockNumber()
+		if err != nil {
+			logWorkerWarn("reportstat", "get latest block number failed", "chainID", chainID, "err", err)
+			continue
+		}
+		chainHeights[chainID.String()] = height
 	}
+	stat["chainHeights"] = chainHeights
+	return stat
 }
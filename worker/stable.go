@@ -7,6 +7,7 @@ import (
 	"github.com/anyswap/CrossChain-Router/v3/cmd/utils"
 	"github.com/anyswap/CrossChain-Router/v3/mongodb"
 	"github.com/anyswap/CrossChain-Router/v3/router"
+	"github.com/anyswap/CrossChain-Router/v3/storage"
 	"github.com/anyswap/CrossChain-Router/v3/tokens"
 )
 
@@ -39,6 +40,7 @@ func StartStableJob() {
 
 func startStableJob(chainID string) {
 	defer mongodb.MgoWaitGroup.Done()
+	trigger := getJobTrigger(&stableTriggers, chainID)
 	for {
 		res, err := findRouterSwapResultsToStable(chainID)
 		if err != nil {
@@ -61,13 +63,13 @@ func startStableJob(chainID string) {
 			logWorker("stable", "stop router swap stable job", "chainID", chainID)
 			return
 		}
-		restInJob(restIntervalInStableJob)
+		trigger.wait(restIntervalInStableJob)
 	}
 }
 
 func findRouterSwapResultsToStable(chainID string) ([]*mongodb.MgoSwapResult, error) {
 	septime := getSepTimeInFind(maxStableLifetime)
-	return mongodb.FindRouterSwapResultsToStable(chainID, septime)
+	return storage.Get().FindRouterSwapResultsToStable(chainID, septime)
 }
 
 func isTxOnChain(txStatus *tokens.TxStatus) bool {
@@ -158,20 +160,29 @@ func processRouterSwapStable(swap *mongodb.MgoSwapResult) (err error) {
 			return nil
 		}
 		if swap.SwapTx != oldSwapTx {
-			_ = updateSwapTx(swap.FromChainID, swap.TxID, swap.LogIndex, swap.SwapTx)
+			_ = updateSwapTx(swap.FromChainID, swap.TxID, swap.LogIndex, oldSwapTx, swap.SwapTx)
 		}
 		if txStatus.IsSwapTxOnChainAndFailed() {
 			logWorker("stable", "mark swap result onchain failed",
 				"fromChainID", swap.FromChainID, "txid", swap.TxID, "logIndex", swap.LogIndex,
 				"swaptime", swap.Timestamp, "nowtime", now())
-			return markSwapResultFailed(swap.FromChainID, swap.TxID, swap.LogIndex)
+			return markSwapResultFailed(swap.FromChainID, swap.TxID, swap.LogIndex, "stable")
 		}
-		return markSwapResultStable(swap.FromChainID, swap.TxID, swap.LogIndex)
+		return markSwapResultStable(swap.FromChainID, swap.TxID, swap.LogIndex, "stable")
 	}
 
 	matchTx := &MatchTx{
-		SwapHeight: txStatus.BlockHeight,
-		SwapTime:   txStatus.BlockTime,
+		SwapHeight:  txStatus.BlockHeight,
+		SwapTime:    txStatus.BlockTime,
+		SwapGasUsed: txStatus.GasUsed,
+		OldSwapTx:   oldSwapTx,
+	}
+	if txStatus.GasUsed > 0 && swap.SwapGasLimit > 0 {
+		// used to tune the gas limit estimation config
+		logWorker("stable", "swap tx gas used", "chainID", swap.ToChainID, "swapType", tokens.SwapType(swap.SwapType).String(),
+			"txid", swap.TxID, "logIndex", swap.LogIndex, "swaptx", swap.SwapTx,
+			"gasLimit", swap.SwapGasLimit, "gasUsed", txStatus.GasUsed,
+			"usedPercent", txStatus.GasUsed*100/swap.SwapGasLimit)
 	}
 	if swap.SwapTx != oldSwapTx {
 		matchTx.SwapTx = swap.SwapTx
@@ -13,6 +13,7 @@ import (
 	"github.com/anyswap/CrossChain-Router/v3/mpc"
 	"github.com/anyswap/CrossChain-Router/v3/params"
 	"github.com/anyswap/CrossChain-Router/v3/router"
+	"github.com/anyswap/CrossChain-Router/v3/storage"
 	"github.com/anyswap/CrossChain-Router/v3/tokens"
 	mapset "github.com/deckarep/golang-set"
 )
@@ -54,6 +55,7 @@ func StartSwapJob() {
 func startRouterSwapJob(chainID string) {
 	defer mongodb.MgoWaitGroup.Done()
 	logWorker("swap", "start router swap job", "chainID", chainID)
+	trigger := getJobTrigger(&swapTriggers, chainID)
 	for {
 		res, err := findRouterSwapToSwap(chainID)
 		if err != nil {
@@ -80,14 +82,14 @@ func startRouterSwapJob(chainID string) {
 			logWorker("swap", "stop router swap job", "chainID", chainID)
 			return
 		}
-		restInJob(restIntervalInDoSwapJob)
+		trigger.wait(restIntervalInDoSwapJob)
 	}
 }
 
 func findRouterSwapToSwap(chainID string) ([]*mongodb.MgoSwap, error) {
 	status := mongodb.TxNotSwapped
 	septime := getSepTimeInFind(maxDoSwapLifetime)
-	return mongodb.FindRouterSwapsWithChainIDAndStatus(chainID, status, septime)
+	return storage.Get().FindRouterSwapsWithChainIDAndStatus(chainID, status, septime)
 }
 
 func processRouterSwap(swap *mongodb.MgoSwap) (err error) {
@@ -110,11 +112,11 @@ func processRouterSwap(swap *mongodb.MgoSwap) (err error) {
 		logWorkerTrace("swap", "swap is in black list", "txid", txid, "logIndex", logIndex,
 			"fromChainID", fromChainID, "toChainID", toChainID, "token", swap.GetToken(), "tokenID", swap.GetTokenID())
 		err = tokens.ErrSwapInBlacklist
-		_ = mongodb.UpdateRouterSwapStatus(fromChainID, txid, logIndex, mongodb.SwapInBlacklist, now(), err.Error())
+		_ = storage.Get().UpdateRouterSwapStatus(fromChainID, txid, logIndex, mongodb.SwapInBlacklist, now(), err.Error(), "swap")
 		return nil
 	}
 
-	res, err := mongodb.FindRouterSwapResult(fromChainID, txid, logIndex)
+	res, err := storage.Get().FindRouterSwapResult(fromChainID, txid, logIndex)
 	if err != nil {
 		return err
 	}
@@ -156,6 +158,7 @@ func processRouterSwap(swap *mongodb.MgoSwap) (err error) {
 		OriginFrom:  swap.From,
 		OriginTxTo:  swap.TxTo,
 		OriginValue: biValue,
+		InitTime:    swap.InitTime,
 	}
 	args.SwapInfo, err = mongodb.ConvertFromSwapInfo(&swap.SwapInfo)
 	if err != nil {
@@ -198,7 +201,7 @@ func processNonEmptySwapResult(res *mongodb.MgoSwapResult) error {
 		res.SwapTx != "" ||
 		res.SwapHeight != 0 ||
 		len(res.OldSwapTxs) > 0 {
-		_ = mongodb.UpdateRouterSwapStatus(res.FromChainID, res.TxID, res.LogIndex, mongodb.TxProcessed, now(), "")
+		_ = storage.Get().UpdateRouterSwapStatus(res.FromChainID, res.TxID, res.LogIndex, mongodb.TxProcessed, now(), "", "swap")
 		return errAlreadySwapped
 	}
 	return nil
@@ -215,7 +218,7 @@ func processHistory(res *mongodb.MgoSwapResult) error {
 	if history == nil {
 		return nil
 	}
-	_ = mongodb.UpdateRouterSwapStatus(chainID, txid, logIndex, mongodb.TxProcessed, now(), "")
+	_ = storage.Get().UpdateRouterSwapStatus(chainID, txid, logIndex, mongodb.TxProcessed, now(), "", "swap")
 	logWorker("swap", "ignore swapped router swap", "fromChainID", res.FromChainID, "toChainID", res.ToChainID, "txid", txid, "logIndex", logIndex, "matchTx", history.matchTx)
 	return errAlreadySwapped
 }
@@ -257,6 +260,10 @@ func processSwapTask(chainID string, swapChan <-chan *tokens.BuildTxArgs) {
 				logWorkerWarn("doSwap", "ignore swap task as toChainID mismatch", "want", chainID, "args", args)
 				continue
 			}
+			if batchSize := params.GetBatchSignSize(chainID); batchSize > 1 && params.IsParallelSwapEnabled() {
+				doSwapBatch(collectSwapTasks(chainID, args, swapChan, batchSize))
+				continue
+			}
 			err := doSwap(args)
 			switch {
 			case err == nil,
@@ -316,6 +323,7 @@ func doSwap(args *tokens.BuildTxArgs) (err error) {
 	rawTx, err := resBridge.BuildRawTransaction(args)
 	if err != nil {
 		logWorkerError("doSwap", "build tx failed", err, "fromChainID", fromChainID, "toChainID", toChainID, "txid", txid, "logIndex", logIndex)
+		processBuildTxError(args, err)
 		return err
 	}
 
@@ -329,7 +337,7 @@ func doSwap(args *tokens.BuildTxArgs) (err error) {
 	}
 
 	// recheck reswap before update db
-	res, err := mongodb.FindRouterSwapResult(fromChainID, txid, logIndex)
+	res, err := storage.Get().FindRouterSwapResult(fromChainID, txid, logIndex)
 	if err != nil {
 		return err
 	}
@@ -343,9 +351,10 @@ func doSwap(args *tokens.BuildTxArgs) (err error) {
 	// update database before sending transaction
 	addSwapHistory(fromChainID, txid, logIndex, txHash)
 	matchTx := &MatchTx{
-		SwapTx:    txHash,
-		SwapNonce: swapTxNonce,
-		MPC:       args.From,
+		SwapTx:       txHash,
+		SwapNonce:    swapTxNonce,
+		MPC:          args.From,
+		SwapGasLimit: args.GetTxGasLimit(),
 	}
 	if args.SwapValue != nil {
 		matchTx.SwapValue = args.SwapValue.String()
@@ -355,9 +364,10 @@ func doSwap(args *tokens.BuildTxArgs) (err error) {
 		logWorkerError("doSwap", "update router swap result failed", err, "fromChainID", fromChainID, "toChainID", toChainID, "txid", txid, "logIndex", logIndex, "swapNonce", swapTxNonce)
 		return err
 	}
+	addSwapTxAttempt(args, txHash)
 	isCachedSwapProcessed = true
 
-	err = mongodb.UpdateRouterSwapStatus(fromChainID, txid, logIndex, mongodb.TxProcessed, now(), "")
+	err = storage.Get().UpdateRouterSwapStatus(fromChainID, txid, logIndex, mongodb.TxProcessed, now(), "", "swap")
 	if err != nil {
 		logWorkerError("doSwap", "update router swap status failed", err, "fromChainID", fromChainID, "toChainID", toChainID, "txid", txid, "logIndex", logIndex)
 		return err
@@ -368,12 +378,23 @@ func doSwap(args *tokens.BuildTxArgs) (err error) {
 		logWorkerError("doSwap", "send tx success but with different hash", errSendTxWithDiffHash,
 			"fromChainID", fromChainID, "toChainID", toChainID, "txid", txid, "logIndex", logIndex,
 			"txHash", txHash, "sentTxHash", sentTxHash, "swapNonce", swapTxNonce)
-		_ = mongodb.UpdateRouterOldSwapTxs(fromChainID, txid, logIndex, sentTxHash)
+		_ = storage.Get().UpdateRouterOldSwapTxs(fromChainID, txid, logIndex, sentTxHash)
 	}
 	return err
 }
 
 func doSwapParallel(args *tokens.BuildTxArgs) (err error) {
+	rawTx, err := buildSwapTxParallel(args)
+	if err != nil {
+		return err
+	}
+	go func() {
+		_ = signAndSendTx(rawTx, args)
+	}()
+	return nil
+}
+
+func buildSwapTxParallel(args *tokens.BuildTxArgs) (rawTx interface{}, err error) {
 	fromChainID := args.FromChainID.String()
 	toChainID := args.ToChainID.String()
 	txid := args.SwapID
@@ -383,7 +404,7 @@ func doSwapParallel(args *tokens.BuildTxArgs) (err error) {
 	cacheKey := mongodb.GetRouterSwapKey(fromChainID, txid, logIndex)
 	err = checkAndUpdateProcessSwapTaskCache(cacheKey)
 	if err != nil {
-		return err
+		return nil, err
 	}
 	logWorker("doSwap", "add swap cache", "fromChainID", fromChainID, "toChainID", toChainID, "txid", txid, "logIndex", logIndex, "value", originValue)
 	isCachedSwapProcessed := false
@@ -398,20 +419,31 @@ func doSwapParallel(args *tokens.BuildTxArgs) (err error) {
 
 	resBridge := router.GetBridgeByChainID(toChainID)
 	if resBridge == nil {
-		return tokens.ErrNoBridgeForChainID
+		return nil, tokens.ErrNoBridgeForChainID
 	}
 
-	rawTx, err := resBridge.BuildRawTransaction(args)
+	rawTx, err = resBridge.BuildRawTransaction(args)
 	if err != nil {
 		logWorkerError("doSwap", "build tx failed", err, "fromChainID", fromChainID, "toChainID", toChainID, "txid", txid, "logIndex", logIndex)
-		return err
+		processBuildTxError(args, err)
+		return nil, err
 	}
 
 	isCachedSwapProcessed = true
-	go func() {
-		_ = signAndSendTx(rawTx, args)
-	}()
-	return nil
+	return rawTx, nil
+}
+
+// mark swap which will revert on dest chain, instead of signing it
+func processBuildTxError(args *tokens.BuildTxArgs, err error) {
+	if !errors.Is(err, tokens.ErrSimulateTxReverted) {
+		return
+	}
+	fromChainID := args.FromChainID.String()
+	txid := args.SwapID
+	logIndex := args.LogIndex
+	memo := err.Error()
+	_ = storage.Get().UpdateRouterSwapStatus(fromChainID, txid, logIndex, mongodb.TxSimulateFailed, now(), memo, "swap")
+	_ = storage.Get().UpdateRouterSwapResultStatus(fromChainID, txid, logIndex, mongodb.MatchTxEmpty, now(), memo, "swap")
 }
 
 func signAndSendTx(rawTx interface{}, args *tokens.BuildTxArgs) error {
@@ -431,16 +463,32 @@ func signAndSendTx(rawTx interface{}, args *tokens.BuildTxArgs) error {
 		return err
 	}
 
+	return updateAndSendSignedTx(resBridge, signedTx, txHash, args)
+}
+
+func updateAndSendSignedTx(resBridge tokens.IBridge, signedTx interface{}, txHash string, args *tokens.BuildTxArgs) error {
+	fromChainID := args.FromChainID.String()
+	toChainID := args.ToChainID.String()
+	txid := args.SwapID
+	logIndex := args.LogIndex
+	swapTxNonce := args.GetTxNonce()
+
 	// update database before sending transaction
+	// swaptx is empty as the swap nonce is allocated when building tx in parallel mode
 	addSwapHistory(fromChainID, txid, logIndex, txHash)
-	_ = updateSwapTx(fromChainID, txid, logIndex, txHash)
+	err := updateSwapTxWithGasLimit(fromChainID, txid, logIndex, "", txHash, args.GetTxGasLimit())
+	if err != nil {
+		logWorkerError("doSwap", "update router swap result failed", err, "fromChainID", fromChainID, "toChainID", toChainID, "txid", txid, "logIndex", logIndex, "swapNonce", swapTxNonce)
+		return err
+	}
+	addSwapTxAttempt(args, txHash)
 
 	sentTxHash, err := sendSignedTransaction(resBridge, signedTx, args)
 	if err == nil && txHash != sentTxHash {
 		logWorkerError("doSwap", "send tx success but with different hash", errSendTxWithDiffHash,
 			"fromChainID", fromChainID, "toChainID", toChainID, "txid", txid, "logIndex", logIndex,
 			"txHash", txHash, "sentTxHash", sentTxHash, "swapNonce", swapTxNonce)
-		_ = mongodb.UpdateRouterOldSwapTxs(fromChainID, txid, logIndex, sentTxHash)
+		_ = storage.Get().UpdateRouterOldSwapTxs(fromChainID, txid, logIndex, sentTxHash)
 	}
 	return err
 }
@@ -467,8 +515,8 @@ func reverifySwap(args *tokens.BuildTxArgs) {
 		// ignore the above situations
 	default:
 		logWorkerWarn("reverify swap after get sign status has disagree", "fromChainID", fromChainID, "toChainID", toChainID, "txid", txid, "logIndex", logIndex, "err", err)
-		_ = mongodb.UpdateRouterSwapStatus(fromChainID, txid, logIndex, mongodb.TxNotStable, now(), "")
-		_ = mongodb.UpdateRouterSwapResultStatus(fromChainID, txid, logIndex, mongodb.TxNotStable, now(), err.Error())
+		_ = storage.Get().UpdateRouterSwapStatus(fromChainID, txid, logIndex, mongodb.TxNotStable, now(), "", "swap")
+		_ = storage.Get().UpdateRouterSwapResultStatus(fromChainID, txid, logIndex, mongodb.TxNotStable, now(), err.Error(), "swap")
 	}
 }
 
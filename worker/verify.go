@@ -10,6 +10,7 @@ import (
 	"github.com/anyswap/CrossChain-Router/v3/mongodb"
 	"github.com/anyswap/CrossChain-Router/v3/params"
 	"github.com/anyswap/CrossChain-Router/v3/router"
+	"github.com/anyswap/CrossChain-Router/v3/storage"
 	"github.com/anyswap/CrossChain-Router/v3/tokens"
 	mapset "github.com/deckarep/golang-set"
 )
@@ -36,7 +37,7 @@ func StartVerifyJob() {
 func startVerifyProducer() {
 	for {
 		septime := getSepTimeInFind(maxVerifyLifetime)
-		res, err := mongodb.FindRouterSwapsWithStatus(mongodb.TxNotStable, septime)
+		res, err := storage.Get().FindRouterSwapsWithStatus(mongodb.TxNotStable, septime)
 		if err != nil {
 			logWorkerError("verify", "find router swap error", err)
 		}
@@ -55,7 +56,7 @@ func startVerifyProducer() {
 			logWorker("verify", "dispatch swap for verify", "fromChainID", swap.FromChainID, "toChainID", swap.ToChainID, "txid", swap.TxID, "logIndex", swap.LogIndex)
 			verifySwapCh <- swap // produce
 		}
-		restInJob(restIntervalInVerifyJob)
+		verifyTrigger.wait(restIntervalInVerifyJob)
 	}
 }
 
@@ -122,7 +123,7 @@ func processRouterSwapVerify(swap *mongodb.MgoSwap) (err error) {
 	var dbErr error
 	if isBlacked(swap) {
 		err = tokens.ErrSwapInBlacklist
-		dbErr = mongodb.UpdateRouterSwapStatus(fromChainID, txid, logIndex, mongodb.SwapInBlacklist, now(), err.Error())
+		dbErr = storage.Get().UpdateRouterSwapStatus(fromChainID, txid, logIndex, mongodb.SwapInBlacklist, now(), err.Error(), "verify")
 		if dbErr != nil {
 			logWorkerError("verify", "verify router swap db error", dbErr, "fromChainID", fromChainID, "toChainID", swap.ToChainID, "txid", txid, "logIndex", logIndex)
 		}
@@ -145,9 +146,9 @@ func processRouterSwapVerify(swap *mongodb.MgoSwap) (err error) {
 	switch {
 	case err == nil:
 		if router.IsBigValueSwap(swapInfo) {
-			dbErr = mongodb.UpdateRouterSwapStatus(fromChainID, txid, logIndex, mongodb.TxWithBigValue, now(), "big swap value")
+			dbErr = storage.Get().UpdateRouterSwapStatus(fromChainID, txid, logIndex, mongodb.TxWithBigValue, now(), "big swap value", "verify")
 		} else {
-			dbErr = mongodb.PassRouterSwapVerify(fromChainID, txid, logIndex, now())
+			dbErr = storage.Get().PassRouterSwapVerify(fromChainID, txid, logIndex, now())
 			if dbErr == nil {
 				dbErr = AddInitialSwapResult(swapInfo, mongodb.MatchTxEmpty)
 			}
@@ -162,22 +163,22 @@ func processRouterSwapVerify(swap *mongodb.MgoSwap) (err error) {
 		if swap.InitTime+1000*maxTxNotFoundTime < nowMilli {
 			duration := time.Duration((nowMilli - swap.InitTime) / 1000 * int64(time.Second))
 			logWorker("verify", "set longer not found swap to verify failed", "fromChainID", fromChainID, "toChainID", swap.ToChainID, "txid", swap.TxID, "logIndex", swap.LogIndex, "inittime", swap.InitTime, "duration", duration.String())
-			dbErr = mongodb.UpdateRouterSwapStatus(fromChainID, txid, logIndex, mongodb.TxVerifyFailed, now(), err.Error())
-			_ = mongodb.UpdateRouterSwapResultStatus(fromChainID, txid, logIndex, mongodb.TxVerifyFailed, now(), err.Error())
+			dbErr = storage.Get().UpdateRouterSwapStatus(fromChainID, txid, logIndex, mongodb.TxVerifyFailed, now(), err.Error(), "verify")
+			_ = storage.Get().UpdateRouterSwapResultStatus(fromChainID, txid, logIndex, mongodb.TxVerifyFailed, now(), err.Error(), "verify")
 		} else {
 			isProcessed = false
 			return err
 		}
 	case errors.Is(err, tokens.ErrTxWithWrongValue):
-		dbErr = mongodb.UpdateRouterSwapStatus(fromChainID, txid, logIndex, mongodb.TxWithWrongValue, now(), err.Error())
+		dbErr = storage.Get().UpdateRouterSwapStatus(fromChainID, txid, logIndex, mongodb.TxWithWrongValue, now(), err.Error(), "verify")
 	case errors.Is(err, tokens.ErrTxWithWrongPath):
-		dbErr = mongodb.UpdateRouterSwapStatus(fromChainID, txid, logIndex, mongodb.TxWithWrongPath, now(), err.Error())
+		dbErr = storage.Get().UpdateRouterSwapStatus(fromChainID, txid, logIndex, mongodb.TxWithWrongPath, now(), err.Error(), "verify")
 	case errors.Is(err, tokens.ErrMissTokenConfig):
-		dbErr = mongodb.UpdateRouterSwapStatus(fromChainID, txid, logIndex, mongodb.MissTokenConfig, now(), err.Error())
+		dbErr = storage.Get().UpdateRouterSwapStatus(fromChainID, txid, logIndex, mongodb.MissTokenConfig, now(), err.Error(), "verify")
 	case errors.Is(err, tokens.ErrNoUnderlyingToken):
-		dbErr = mongodb.UpdateRouterSwapStatus(fromChainID, txid, logIndex, mongodb.NoUnderlyingToken, now(), err.Error())
+		dbErr = storage.Get().UpdateRouterSwapStatus(fromChainID, txid, logIndex, mongodb.NoUnderlyingToken, now(), err.Error(), "verify")
 	default:
-		dbErr = mongodb.UpdateRouterSwapStatus(fromChainID, txid, logIndex, mongodb.TxVerifyFailed, now(), err.Error())
+		dbErr = storage.Get().UpdateRouterSwapStatus(fromChainID, txid, logIndex, mongodb.TxVerifyFailed, now(), err.Error(), "verify")
 	}
 
 	if dbErr != nil {
@@ -0,0 +1,121 @@
+package worker
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/anyswap/CrossChain-Router/v3/cmd/utils"
+	"github.com/anyswap/CrossChain-Router/v3/mongodb"
+	"github.com/anyswap/CrossChain-Router/v3/params"
+	"github.com/anyswap/CrossChain-Router/v3/storage"
+)
+
+var (
+	restIntervalInWatchJob = 10 * time.Second
+
+	verifyTrigger   = newJobTrigger()
+	swapTriggers    sync.Map // key is fromChainID
+	stableTriggers  sync.Map // key is toChainID
+	replaceTriggers sync.Map // key is toChainID
+)
+
+// jobTrigger wakes up a polling job early, notifications are coalesced
+type jobTrigger chan struct{}
+
+func newJobTrigger() jobTrigger {
+	return make(jobTrigger, 1)
+}
+
+func (t jobTrigger) notify() {
+	select {
+	case t <- struct{}{}:
+	default:
+	}
+}
+
+// wait until notified or timeout
+func (t jobTrigger) wait(duration time.Duration) {
+	timer := time.NewTimer(duration)
+	defer timer.Stop()
+	select {
+	case <-t:
+	case <-timer.C:
+	}
+}
+
+func getJobTrigger(triggers *sync.Map, chainID string) jobTrigger {
+	trigger, _ := triggers.LoadOrStore(chainID, newJobTrigger())
+	return trigger.(jobTrigger)
+}
+
+func notifyJobTrigger(triggers *sync.Map, chainID string) {
+	if trigger, exist := triggers.Load(chainID); exist {
+		trigger.(jobTrigger).notify()
+	}
+}
+
+// StartWatchJob watch database changes to drive verify/swap/stable/replace jobs.
+// polling in these jobs is kept as fallback if watching failed.
+func StartWatchJob() {
+	serverCfg := params.GetRouterServerConfig()
+	if serverCfg == nil || !serverCfg.EnableEventDrivenJobs {
+		return
+	}
+	watcher, ok := storage.Get().(storage.Watcher)
+	if !ok {
+		logWorkerWarn("watch", "storage does not support watching, use polling only")
+		return
+	}
+	logWorker("watch", "start watch job")
+
+	ctx, cancel := context.WithCancel(context.Background())
+	go func() {
+		<-utils.CleanupChan
+		cancel()
+	}()
+
+	go startWatchJob(ctx, "watch swaps", func() error {
+		return watcher.WatchRouterSwaps(ctx,
+			[]mongodb.SwapStatus{mongodb.TxNotStable, mongodb.TxNotSwapped},
+			onRouterSwapEvent)
+	})
+	go startWatchJob(ctx, "watch swap results", func() error {
+		return watcher.WatchRouterSwapResults(ctx,
+			[]mongodb.SwapStatus{mongodb.MatchTxNotStable},
+			onRouterSwapResultEvent)
+	})
+}
+
+func startWatchJob(ctx context.Context, subject string, watch func() error) {
+	for {
+		err := watch()
+		if ctx.Err() != nil {
+			logWorker("watch", "stop "+subject)
+			return
+		}
+		logWorkerWarn("watch", subject+" failed, fallback to polling", "err", err)
+		select {
+		case <-ctx.Done():
+			logWorker("watch", "stop "+subject)
+			return
+		case <-time.After(restIntervalInWatchJob):
+		}
+	}
+}
+
+func onRouterSwapEvent(ev *mongodb.SwapEvent) {
+	switch ev.Status {
+	case mongodb.TxNotStable:
+		verifyTrigger.notify()
+	case mongodb.TxNotSwapped:
+		notifyJobTrigger(&swapTriggers, ev.FromChainID)
+	}
+}
+
+func onRouterSwapResultEvent(ev *mongodb.SwapEvent) {
+	if ev.Status == mongodb.MatchTxNotStable {
+		notifyJobTrigger(&stableTriggers, ev.ToChainID)
+		notifyJobTrigger(&replaceTriggers, ev.ToChainID)
+	}
+}
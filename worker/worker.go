@@ -21,6 +21,8 @@ func StartRouterSwapWork(isServer bool) {
 	if !isServer {
 		StartAcceptSignJob()
 		time.Sleep(interval)
+		StartAcceptLedgerJob()
+		time.Sleep(interval)
 		StartReportStatJob()
 		return
 	}
@@ -41,4 +43,13 @@ func StartRouterSwapWork(isServer bool) {
 	time.Sleep(interval)
 
 	StartCheckFailedSwapJob()
+	time.Sleep(interval)
+
+	StartNonceAuditJob()
+	time.Sleep(interval)
+
+	StartArchiveJob()
+	time.Sleep(interval)
+
+	StartWatchJob()
 }